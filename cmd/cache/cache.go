@@ -0,0 +1,33 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the local cache of tags, tag values and recent results",
+	Long: `Tag names and tag values are cached for 10 minutes and recent logs get
+result sets for 2 minutes, under $XDG_CACHE_HOME/lakerunner. Shell completion
+keeps its own suggestions there for 5 minutes. Pass --no-cache to any command
+to bypass the cache.`,
+}
+
+func init() {
+	CacheCmd.AddCommand(StatsCmd)
+	CacheCmd.AddCommand(ClearCmd)
+}
@@ -0,0 +1,44 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"fmt"
+
+	internalCache "github.com/lakerunner/cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var ClearCmd = &cobra.Command{
+	Use:       "clear [tags|logs|completion]...",
+	Short:     "Delete cached entries (all of them unless caches are named)",
+	RunE:      runClearCmd,
+	ValidArgs: []string{"tags", "logs", "completion"},
+	Args:      cobra.OnlyValidArgs,
+}
+
+func runClearCmd(cmdObj *cobra.Command, args []string) error {
+	root, err := internalCache.Root()
+	if err != nil {
+		return err
+	}
+	if err := internalCache.Clear(root, args...); err != nil {
+		return err
+	}
+	if quiet, _ := cmdObj.Flags().GetBool("quiet"); !quiet {
+		fmt.Printf("Cleared cache in %s\n", root)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	internalCache "github.com/lakerunner/cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var StatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the number and size of cached entries",
+	RunE:  runStatsCmd,
+	Args:  cobra.NoArgs,
+}
+
+func runStatsCmd(_ *cobra.Command, _ []string) error {
+	root, err := internalCache.Root()
+	if err != nil {
+		return err
+	}
+	stats, err := internalCache.Usage(root)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Cache directory: %s\n", root)
+	if len(stats) == 0 {
+		fmt.Println("Cache is empty")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CACHE\tENTRIES\tSIZE\tOLDEST\tNEWEST")
+	var entries int
+	var size int64
+	for _, st := range stats {
+		entries += st.Entries
+		size += st.Bytes
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", st.Name, st.Entries, formatBytes(st.Bytes), age(st.Oldest), age(st.Newest))
+	}
+	_, _ = fmt.Fprintf(tw, "total\t%d\t%s\t\t\n", entries, formatBytes(size))
+	return tw.Flush()
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
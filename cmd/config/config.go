@@ -0,0 +1,38 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the merged preset, alias and redaction configuration",
+	Long: `Configuration is merged from these files, later ones taking precedence:
+
+  ~/.lakerunner/config.yaml   personal settings
+  .lakerunner.yaml            nearest one found walking up from the current directory
+  $LAKERUNNER_CONFIG          explicit file
+  --config FILE               explicit file
+
+Any file may pull in shared files with an include: list; paths are relative
+to the including file, and the including file's own entries win.`,
+}
+
+func init() {
+	ConfigCmd.AddCommand(ViewCmd)
+	ConfigCmd.AddCommand(DoctorCmd)
+}
@@ -0,0 +1,241 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/presets"
+	"github.com/spf13/cobra"
+)
+
+// Clock skew tolerances. The Date header has one second resolution.
+const (
+	skewWarn = 5 * time.Second
+	skewFail = 2 * time.Minute
+	// certExpiryWarn flags certificates that are about to expire.
+	certExpiryWarn = 14 * 24 * time.Hour
+)
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check config files, endpoint reachability, TLS, API key and clock skew",
+	Long: `Validate every config file that takes part in the merge and report alias
+flags that collide with built-in flags, then make one authenticated request
+to the endpoint to check that it is reachable, that its TLS certificate is
+trusted, that the API key is accepted and that the local clock agrees with
+the server's.`,
+	RunE: runDoctorCmd,
+	Args: cobra.NoArgs,
+	// Failed checks are not usage errors.
+	SilenceUsage: true,
+	// Alias warnings are part of the report, not printed ahead of it.
+	Annotations: map[string]string{presets.AnnotationSkipWarnings: "true"},
+}
+
+type checkStatus int
+
+const (
+	statusOK checkStatus = iota
+	statusWarn
+	statusFail
+)
+
+func (s checkStatus) symbol() string {
+	switch s {
+	case statusWarn:
+		return "!"
+	case statusFail:
+		return "✗"
+	}
+	return "✓"
+}
+
+type check struct {
+	status checkStatus
+	msg    string
+}
+
+func ok(format string, args ...any) check   { return check{statusOK, fmt.Sprintf(format, args...)} }
+func warn(format string, args ...any) check { return check{statusWarn, fmt.Sprintf(format, args...)} }
+func fail(format string, args ...any) check { return check{statusFail, fmt.Sprintf(format, args...)} }
+
+// configChecks validates each config layer, then the merged result (which
+// also follows includes), and reports alias flag warnings.
+func configChecks(layers []presets.Layer, warnings []string) []check {
+	var checks []check
+	valid := true
+	for _, l := range layers {
+		data, err := os.ReadFile(l.Path)
+		if err != nil {
+			if os.IsNotExist(err) && !l.Required {
+				continue
+			}
+			checks = append(checks, fail("%s (%s): %v", l.Path, l.Source, err))
+			valid = false
+			continue
+		}
+		if err := presets.ValidateFile(l.Path, data); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				checks = append(checks, fail("%s", line))
+			}
+			valid = false
+			continue
+		}
+		checks = append(checks, ok("%s (%s)", l.Path, l.Source))
+	}
+	if valid {
+		if _, err := presets.Load(); err != nil {
+			checks = append(checks, fail("%v", err))
+			valid = false
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, ok("no config files found (presets and aliases are optional)"))
+	}
+	if !valid {
+		// Alias warnings would only repeat the errors above.
+		return checks
+	}
+	for _, w := range warnings {
+		checks = append(checks, warn("%s", w))
+	}
+	return checks
+}
+
+// endpointChecks probes the endpoint once and derives reachability, TLS, API
+// key and clock skew checks from the response.
+func endpointChecks(ctx context.Context, cfg *config.Config) []check {
+	u, err := url.Parse(cfg.LAKERUNNER_QUERY_URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return []check{fail("endpoint %q is not an http(s) URL", cfg.LAKERUNNER_QUERY_URL)}
+	}
+
+	result, err := api.NewClient(cfg).Probe(ctx)
+	if err != nil {
+		var verr *tls.CertificateVerificationError
+		var rerr tls.RecordHeaderError
+		switch {
+		case errors.As(err, &verr):
+			return []check{fail("TLS certificate not trusted: %v (use --insecure or LAKERUNNER_INSECURE=true for self-signed endpoints)", verr.Err)}
+		case errors.As(err, &rerr):
+			return []check{fail("TLS handshake failed: the endpoint does not appear to speak HTTPS; try http://")}
+		}
+		return []check{fail("endpoint unreachable: %v", err)}
+	}
+
+	checks := []check{ok("reachable (%s)", result.Latency.Round(time.Millisecond))}
+	checks = append(checks, tlsCheck(u, result, cfg.Insecure))
+	switch {
+	case result.StatusCode == http.StatusOK:
+		checks = append(checks, ok("API key accepted"))
+	case result.StatusCode == http.StatusUnauthorized || result.StatusCode == http.StatusForbidden:
+		checks = append(checks, fail("API key rejected (status %d)", result.StatusCode))
+	default:
+		checks = append(checks, warn("unexpected status %d from %s/api/v1/logs/tags; API key not verified", result.StatusCode, strings.TrimSuffix(cfg.LAKERUNNER_QUERY_URL, "/")))
+	}
+	return append(checks, skewCheck(result))
+}
+
+func tlsCheck(u *url.URL, result *api.ProbeResult, insecure bool) check {
+	if result.TLS == nil {
+		host := u.Hostname()
+		if host == "localhost" || strings.HasPrefix(host, "127.") || host == "::1" {
+			return ok("plain HTTP to a local endpoint")
+		}
+		return warn("plain HTTP: the API key is sent unencrypted")
+	}
+	version := tls.VersionName(result.TLS.Version)
+	if len(result.TLS.PeerCertificates) == 0 {
+		return ok("%s", version)
+	}
+	notAfter := result.TLS.PeerCertificates[0].NotAfter
+	left := time.Until(notAfter)
+	switch {
+	case left <= 0:
+		return fail("%s, certificate expired on %s", version, notAfter.Format(time.DateOnly))
+	case insecure:
+		return warn("%s, certificate verification disabled (--insecure)", version)
+	case left < certExpiryWarn:
+		return warn("%s, certificate expires in %d days (%s)", version, int(left.Hours()/24), notAfter.Format(time.DateOnly))
+	}
+	return ok("%s, certificate valid until %s", version, notAfter.Format(time.DateOnly))
+}
+
+func skewCheck(result *api.ProbeResult) check {
+	if result.ServerTime.IsZero() {
+		return warn("server sent no Date header; clock skew not checked")
+	}
+	skew := result.LocalTime.Sub(result.ServerTime).Round(time.Second)
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+	direction := "ahead of"
+	if skew < 0 {
+		direction = "behind"
+	}
+	switch {
+	case abs <= skewWarn:
+		return ok("clock in sync with server (skew %s)", abs)
+	case abs <= skewFail:
+		return warn("local clock is %s %s the server; relative times like e-5m will be off", abs, direction)
+	}
+	return fail("local clock is %s %s the server; relative time ranges will miss logs", abs, direction)
+}
+
+func printSection(title string, checks []check) int {
+	fmt.Println(title)
+	failed := 0
+	for _, c := range checks {
+		fmt.Printf("  %s %s\n", c.status.symbol(), c.msg)
+		if c.status == statusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+func runDoctorCmd(cmdObj *cobra.Command, _ []string) error {
+	failed := printSection("Config", configChecks(presets.Layers(), presets.Warnings()))
+
+	endpoint, _ := cmdObj.Flags().GetString("endpoint")
+	apiKey, _ := cmdObj.Flags().GetString("api-key")
+	insecure, _ := cmdObj.Flags().GetBool("insecure")
+	cfg, err := config.LoadWithFlags(endpoint, apiKey, insecure)
+	fmt.Println()
+	if err != nil {
+		failed += printSection("Endpoint", []check{fail("%v", err)})
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		failed += printSection("Endpoint "+cfg.LAKERUNNER_QUERY_URL, endpointChecks(ctx, cfg))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/config"
+)
+
+func statuses(checks []check) string {
+	var b strings.Builder
+	for _, c := range checks {
+		b.WriteString(c.status.symbol())
+	}
+	return b.String()
+}
+
+func TestEndpointChecks(t *testing.T) {
+	handler := func(status int, date time.Time) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("x-cardinalhq-api-key") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Date", date.UTC().Format(http.TimeFormat))
+			w.WriteHeader(status)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		tls     bool
+		want    string
+	}{
+		{"healthy", handler(http.StatusOK, time.Now()), false, "✓✓✓✓"},
+		{"rejected key", handler(http.StatusForbidden, time.Now()), false, "✓✓✗✓"},
+		{"server error", handler(http.StatusInternalServerError, time.Now()), false, "✓✓!✓"},
+		{"small skew", handler(http.StatusOK, time.Now().Add(-time.Minute)), false, "✓✓✓!"},
+		{"large skew", handler(http.StatusOK, time.Now().Add(time.Hour)), false, "✓✓✓✗"},
+		{"untrusted certificate", handler(http.StatusOK, time.Now()), true, "✗"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			if tt.tls {
+				server = httptest.NewTLSServer(tt.handler)
+			} else {
+				server = httptest.NewServer(tt.handler)
+			}
+			defer server.Close()
+
+			cfg := &config.Config{LAKERUNNER_QUERY_URL: server.URL, LAKERUNNER_API_KEY: "key"}
+			checks := endpointChecks(context.Background(), cfg)
+			if got := statuses(checks); got != tt.want {
+				t.Errorf("statuses = %s, want %s: %+v", got, tt.want, checks)
+			}
+			if tt.tls && !strings.Contains(checks[0].msg, "--insecure") {
+				t.Errorf("expected --insecure hint, got %q", checks[0].msg)
+			}
+		})
+	}
+}
+
+func TestEndpointChecksUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close()
+
+	checks := endpointChecks(context.Background(), &config.Config{LAKERUNNER_QUERY_URL: endpoint, LAKERUNNER_API_KEY: "key"})
+	if len(checks) != 1 || checks[0].status != statusFail || !strings.Contains(checks[0].msg, "unreachable") {
+		t.Errorf("unexpected checks: %+v", checks)
+	}
+}
+
+func TestTLSCheckPlainHTTP(t *testing.T) {
+	local := &config.Config{LAKERUNNER_QUERY_URL: "http://localhost:8080"}
+	remote := &config.Config{LAKERUNNER_QUERY_URL: "http://lakerunner.example.com"}
+	for _, tt := range []struct {
+		cfg  *config.Config
+		want checkStatus
+	}{{local, statusOK}, {remote, statusWarn}} {
+		u, _ := url.Parse(tt.cfg.LAKERUNNER_QUERY_URL)
+		if got := tlsCheck(u, &api.ProbeResult{}, false); got.status != tt.want {
+			t.Errorf("tlsCheck(%s) = %+v, want status %d", u, got, tt.want)
+		}
+	}
+}
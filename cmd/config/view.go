@@ -0,0 +1,124 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/lakerunner/cli/internal/presets"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var showOrigin bool
+
+var ViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the merged configuration",
+	RunE:  runViewCmd,
+	Args:  cobra.NoArgs,
+}
+
+func init() {
+	ViewCmd.Flags().BoolVar(&showOrigin, "show-origin", false, "Annotate each preset, alias and the redaction block with the file it came from")
+}
+
+func runViewCmd(_ *cobra.Command, _ []string) error {
+	cfg, err := presets.Load()
+	if err != nil {
+		return err
+	}
+	doc, err := configNode(cfg, showOrigin)
+	if err != nil {
+		return err
+	}
+
+	if showOrigin {
+		if len(cfg.Sources) == 0 {
+			fmt.Println("# No config files found")
+		} else {
+			fmt.Println("# Files merged, lowest precedence first:")
+			for _, l := range cfg.Sources {
+				fmt.Printf("#   %-18s %s\n", l.Source, l.Path)
+			}
+		}
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return enc.Close()
+}
+
+// configNode renders the merged config as YAML, optionally with the origin of
+// each entry as a line comment.
+func configNode(cfg *presets.Config, withOrigin bool) (*yaml.Node, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	origin := func(key string) string {
+		if !withOrigin {
+			return ""
+		}
+		return cfg.Origins[key]
+	}
+
+	if len(cfg.Presets) > 0 {
+		section := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range sortedKeys(cfg.Presets) {
+			list := &yaml.Node{Kind: yaml.SequenceNode}
+			for _, f := range cfg.Presets[name] {
+				list.Content = append(list.Content, scalar(f, ""))
+			}
+			section.Content = append(section.Content, scalar(name, origin(presets.OriginKey("presets", name))), list)
+		}
+		root.Content = append(root.Content, scalar("presets", ""), section)
+	}
+
+	if len(cfg.Aliases) > 0 {
+		section := &yaml.Node{Kind: yaml.MappingNode}
+		for _, alias := range sortedKeys(cfg.Aliases) {
+			section.Content = append(section.Content, scalar(alias, origin(presets.OriginKey("aliases", alias))), scalar(cfg.Aliases[alias], ""))
+		}
+		root.Content = append(root.Content, scalar("aliases", ""), section)
+	}
+
+	if _, ok := cfg.Origins["redaction"]; ok {
+		var value yaml.Node
+		if err := value.Encode(cfg.Redaction); err != nil {
+			return nil, fmt.Errorf("failed to encode redaction config: %w", err)
+		}
+		root.Content = append(root.Content, scalar("redaction", origin("redaction")), &value)
+	}
+	return root, nil
+}
+
+func scalar(value, comment string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	if comment != "" {
+		n.LineComment = comment
+	}
+	return n
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -26,4 +26,9 @@ var DemoCmd = &cobra.Command{
 func init() {
 	DemoCmd.AddCommand(MinioSetupCmd)
 	DemoCmd.AddCommand(PopulateCmd)
+	DemoCmd.AddCommand(ServeCmd)
+	DemoCmd.AddCommand(GenerateCmd)
+	DemoCmd.AddCommand(UpCmd)
+	DemoCmd.AddCommand(StatusCmd)
+	DemoCmd.AddCommand(DownCmd)
 }
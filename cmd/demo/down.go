@@ -0,0 +1,114 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/spf13/cobra"
+)
+
+var downDeleteBucket bool
+
+var DownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Undo demo up: remove the bucket notifications and webhook target",
+	Long: `Remove what demo up configured: the bucket notifications to the webhook
+target and the target itself. Notifications to other targets are kept. The
+bucket and its data stay unless --delete-bucket is given.`,
+	Example: `  lakerunner demo down --dry-run
+  lakerunner demo down --delete-bucket`,
+	RunE: runDownCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	addStackFlags(DownCmd, true)
+	DownCmd.Flags().BoolVar(&downDeleteBucket, "delete-bucket", false, "Also delete the bucket and every object in it")
+	DownCmd.Flags().BoolVar(&stackDryRun, "dry-run", false, "Print the changes without making them")
+}
+
+func runDownCmd(cmd *cobra.Command, _ []string) error {
+	s, err := newMinioStack()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	if err := poll(ctx, stackWait, "MinIO at "+stackEndpoint, s.Ready); err != nil {
+		return err
+	}
+	steps, err := planDown(ctx, s, downDeleteBucket, stackWait)
+	if err != nil {
+		return err
+	}
+	return applySteps(ctx, steps, stackDryRun)
+}
+
+// withoutWebhook returns config without the queues that target the webhook.
+func withoutWebhook(config notification.Configuration) notification.Configuration {
+	var queues []notification.QueueConfig
+	for _, q := range config.QueueConfigs {
+		if q.Queue != webhookArn.String() && q.Arn != webhookArn {
+			queues = append(queues, q)
+		}
+	}
+	config.QueueConfigs = queues
+	return config
+}
+
+// planDown returns the steps that undo demo up on s.
+func planDown(ctx context.Context, s stack, deleteBucket bool, wait time.Duration) ([]step, error) {
+	var steps []step
+	exists, err := s.BucketExists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket: %w", err)
+	}
+	if exists && !deleteBucket {
+		config, err := s.Notifications(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bucket notifications: %w", err)
+		}
+		if kept := withoutWebhook(config); len(kept.QueueConfigs) != len(config.QueueConfigs) {
+			steps = append(steps, step{"remove the notifications to webhook target " + webhookTarget + " from " + stackBucket, func(ctx context.Context) error {
+				config, err := s.Notifications(ctx)
+				if err != nil {
+					return err
+				}
+				return s.SetNotifications(ctx, withoutWebhook(config))
+			}})
+		}
+	}
+	if exists && deleteBucket {
+		steps = append(steps, step{"delete bucket " + stackBucket + " and every object in it", s.RemoveBucket})
+	}
+
+	current, err := s.WebhookEndpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+	if current != "" {
+		steps = append(steps, step{"delete webhook target " + webhookTarget + " (" + current + ")", func(ctx context.Context) error {
+			restart, err := s.DeleteWebhook(ctx)
+			if err != nil {
+				return err
+			}
+			return restartIfNeeded(ctx, s, restart, false, wait)
+		}})
+	}
+	return steps, nil
+}
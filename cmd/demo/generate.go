@@ -0,0 +1,384 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/spf13/cobra"
+
+	"github.com/lakerunner/cli/internal/otlp"
+	"github.com/lakerunner/cli/internal/synth"
+)
+
+// scopeName identifies the generator in the OTLP instrumentation scope.
+const scopeName = "lakerunner-cli/demo-generate"
+
+var (
+	generateDir         string
+	generateEndpoint    string
+	generateAccessKey   string
+	generateSecretKey   string
+	generateBucket      string
+	generateSecure      bool
+	generatePrefix      string
+	generateOrg         string
+	generateCollector   string
+	generateServices    []string
+	generateLevels      string
+	generateSpan        time.Duration
+	generateRate        int
+	generateProfile     string
+	generateBursts      int
+	generateBurstLength time.Duration
+	generateBatch       time.Duration
+	generateSignals     []string
+	generateFormat      string
+	generateGzip        bool
+	generateSeed        uint64
+)
+
+var GenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate synthetic OTLP logs and metrics and upload them to MinIO or write them to disk",
+	Long: `Synthesise OTLP log and metric payloads for a set of services and upload
+them to MinIO under --prefix, where Lakerunner picks them up, or write them
+to --dir.
+
+Logs cover the last --span at --rate lines per minute, shaped by --profile,
+with weighted levels, trace IDs and plain text, logfmt and JSON messages.
+--error-bursts adds windows in which one service floods errors. Metrics are
+a per-level log record counter and a CPU gauge per pod. One file per signal
+is written for each --batch of time, laid out like the collector's S3
+exporter: PREFIX/ORG/COLLECTOR/year=YYYY/month=MM/day=DD/hour=HH/minute=MM/.
+
+MinIO credentials default to MINIO_ROOT_USER/MINIO_ROOT_PASSWORD or
+AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY.`,
+	Example: `  lakerunner demo generate --span 2h --rate 500 --error-bursts 2
+  lakerunner demo generate --dir ./otel --format json --gzip=false --signals logs`,
+	RunE: runGenerateCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	GenerateCmd.Flags().StringVar(&generateDir, "dir", "", "Write files under this directory instead of uploading them")
+	GenerateCmd.Flags().StringVar(&generateEndpoint, "endpoint", "localhost:9000", "MinIO endpoint")
+	GenerateCmd.Flags().StringVar(&generateAccessKey, "access-key", "", "MinIO access key (default $MINIO_ROOT_USER or $AWS_ACCESS_KEY_ID)")
+	GenerateCmd.Flags().StringVar(&generateSecretKey, "secret-key", "", "MinIO secret key (default $MINIO_ROOT_PASSWORD or $AWS_SECRET_ACCESS_KEY)")
+	GenerateCmd.Flags().StringVar(&generateBucket, "bucket", "lakerunner", "Bucket name")
+	GenerateCmd.Flags().BoolVar(&generateSecure, "secure", false, "Use HTTPS to reach the endpoint")
+	GenerateCmd.Flags().StringVar(&generatePrefix, "prefix", "otel-raw", "Object key prefix, e.g. otel-raw or logs-raw")
+	GenerateCmd.Flags().StringVar(&generateOrg, "org", "demo", "Organization path segment")
+	GenerateCmd.Flags().StringVar(&generateCollector, "collector", "synthetic", "Collector path segment")
+	GenerateCmd.Flags().StringSliceVar(&generateServices, "services", synth.DefaultServices, "Services to generate logs for")
+	GenerateCmd.Flags().StringVar(&generateLevels, "levels", "DEBUG=10,INFO=70,WARN=12,ERROR=8", "Relative level frequencies as LEVEL=WEIGHT pairs")
+	GenerateCmd.Flags().DurationVar(&generateSpan, "span", time.Hour, "Generate data for this much time up to now")
+	GenerateCmd.Flags().IntVar(&generateRate, "rate", 100, "Average log lines per minute across all services")
+	GenerateCmd.Flags().StringVar(&generateProfile, "profile", synth.ProfileFlat, "Volume over time: "+strings.Join(synth.Profiles, ", "))
+	GenerateCmd.Flags().IntVar(&generateBursts, "error-bursts", 1, "Number of error bursts")
+	GenerateCmd.Flags().DurationVar(&generateBurstLength, "burst-length", 5*time.Minute, "Length of each error burst")
+	GenerateCmd.Flags().DurationVar(&generateBatch, "batch", time.Minute, "Time covered by each file")
+	GenerateCmd.Flags().StringSliceVar(&generateSignals, "signals", []string{"logs", "metrics"}, "Signals to generate: logs, metrics")
+	GenerateCmd.Flags().StringVar(&generateFormat, "format", "proto", "Payload encoding: proto (OTLP protobuf) or json (OTLP/JSON)")
+	GenerateCmd.Flags().BoolVar(&generateGzip, "gzip", true, "Gzip each file")
+	GenerateCmd.Flags().Uint64Var(&generateSeed, "seed", 1, "Seed for the generated data")
+}
+
+// payloadFile is one generated object.
+type payloadFile struct {
+	key  string
+	data []byte
+}
+
+// podKey identifies the resource a record belongs to.
+type podKey struct{ service, pod string }
+
+func resourceAttributes(k podKey) []otlp.KeyValue {
+	return []otlp.KeyValue{
+		{Key: "service.name", Value: k.service},
+		{Key: "k8s.pod.name", Value: k.pod},
+		{Key: "k8s.namespace.name", Value: "demo"},
+	}
+}
+
+// batchLogs groups records by resource.
+func batchLogs(records []synth.Record) []otlp.ResourceLogs {
+	var out []otlp.ResourceLogs
+	index := make(map[podKey]int)
+	for _, rec := range records {
+		k := podKey{rec.Service, rec.Pod}
+		i, ok := index[k]
+		if !ok {
+			i = len(out)
+			index[k] = i
+			out = append(out, otlp.ResourceLogs{Resource: resourceAttributes(k), Scope: scopeName})
+		}
+		out[i].Records = append(out[i].Records, otlp.LogRecord{
+			Time:         rec.Time,
+			SeverityText: rec.Level,
+			Body:         rec.Message,
+			TraceID:      rec.TraceID,
+			SpanID:       rec.SpanID,
+		})
+	}
+	return out
+}
+
+// metricsState carries cumulative counters and the CPU random walk from one
+// batch to the next.
+type metricsState struct {
+	start  time.Time
+	counts map[podKey]map[string]int
+	cpu    map[podKey]float64
+	rand   *rand.Rand
+}
+
+func newMetricsState(start time.Time, seed uint64) *metricsState {
+	return &metricsState{
+		start:  start,
+		counts: make(map[podKey]map[string]int),
+		cpu:    make(map[podKey]float64),
+		rand:   rand.New(rand.NewPCG(seed, seed+1)),
+	}
+}
+
+// batch returns one data point per pod and metric at end, counting the
+// records of the batch.
+func (s *metricsState) batch(records []synth.Record, services []string, end time.Time) []otlp.ResourceMetrics {
+	for _, rec := range records {
+		k := podKey{rec.Service, rec.Pod}
+		if s.counts[k] == nil {
+			s.counts[k] = make(map[string]int)
+		}
+		s.counts[k][rec.Level]++
+	}
+	var out []otlp.ResourceMetrics
+	for _, service := range services {
+		for _, pod := range synth.Pods(service) {
+			k := podKey{service, pod}
+			cpu, ok := s.cpu[k]
+			if !ok {
+				cpu = 0.2 + 0.3*s.rand.Float64()
+			}
+			cpu = min(max(cpu+0.1*(s.rand.Float64()-0.5), 0.01), 0.99)
+			s.cpu[k] = cpu
+
+			counter := otlp.Metric{Name: "app.log.records", Unit: "{record}", Kind: otlp.Sum}
+			for _, level := range slices.Sorted(maps.Keys(s.counts[k])) {
+				counter.Points = append(counter.Points, otlp.DataPoint{
+					Attributes: []otlp.KeyValue{{Key: "level", Value: level}},
+					Start:      s.start,
+					Time:       end,
+					Value:      float64(s.counts[k][level]),
+				})
+			}
+			metrics := []otlp.Metric{{Name: "process.cpu.utilization", Unit: "1", Points: []otlp.DataPoint{{Time: end, Value: cpu}}}}
+			if len(counter.Points) > 0 {
+				metrics = append(metrics, counter)
+			}
+			out = append(out, otlp.ResourceMetrics{Resource: resourceAttributes(k), Scope: scopeName, Metrics: metrics})
+		}
+	}
+	return out
+}
+
+// generateFiles renders records into one file per signal and batch.
+func generateFiles(records []synth.Record, start, end time.Time) ([]payloadFile, error) {
+	ext := map[string]string{"proto": "binpb", "json": "json"}[generateFormat]
+	if ext == "" {
+		return nil, fmt.Errorf("invalid --format %q: expected proto or json", generateFormat)
+	}
+	if generateGzip {
+		ext += ".gz"
+	}
+	for _, s := range generateSignals {
+		if s != "logs" && s != "metrics" {
+			return nil, fmt.Errorf("invalid signal %q: expected logs or metrics", s)
+		}
+	}
+	if generateBatch <= 0 {
+		return nil, fmt.Errorf("invalid --batch %s: must be positive", generateBatch)
+	}
+
+	metrics := newMetricsState(start, generateSeed)
+	var files []payloadFile
+	for batchStart := start; batchStart.Before(end); batchStart = batchStart.Add(generateBatch) {
+		batchEnd := batchStart.Add(generateBatch)
+		if batchEnd.After(end) {
+			batchEnd = end
+		}
+		var batch []synth.Record
+		for len(records) > 0 && records[0].Time.Before(batchEnd) {
+			batch = append(batch, records[0])
+			records = records[1:]
+		}
+		for _, signal := range generateSignals {
+			var data []byte
+			var err error
+			switch {
+			case signal == "logs" && len(batch) == 0:
+				continue
+			case signal == "logs" && generateFormat == "json":
+				data, err = otlp.MarshalLogsJSON(batchLogs(batch))
+			case signal == "logs":
+				data = otlp.MarshalLogs(batchLogs(batch))
+			case generateFormat == "json":
+				data, err = otlp.MarshalMetricsJSON(metrics.batch(batch, generateServices, batchEnd))
+			default:
+				data = otlp.MarshalMetrics(metrics.batch(batch, generateServices, batchEnd))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", signal, err)
+			}
+			if generateGzip {
+				if data, err = gzipBytes(data); err != nil {
+					return nil, err
+				}
+			}
+			files = append(files, payloadFile{key: otlp.ObjectKey(generatePrefix, generateOrg, generateCollector, signal, batchStart, ext), data: data})
+		}
+	}
+	return files, nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// minioCredentials returns static credentials from the flags, falling back to
+// the MinIO and AWS environment variables.
+func minioCredentials(accessKey, secretKey string) (*credentials.Credentials, error) {
+	if accessKey == "" {
+		accessKey = firstEnv("MINIO_ROOT_USER", "AWS_ACCESS_KEY_ID")
+	}
+	if secretKey == "" {
+		secretKey = firstEnv("MINIO_ROOT_PASSWORD", "AWS_SECRET_ACCESS_KEY")
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("no MinIO credentials: pass --access-key/--secret-key or set MINIO_ROOT_USER/MINIO_ROOT_PASSWORD")
+	}
+	return credentials.NewStaticV4(accessKey, secretKey, ""), nil
+}
+
+// generatePayloads synthesises span worth of data up to now with the generate
+// flags and renders it into files. It also returns the number of log records.
+func generatePayloads(span time.Duration) ([]payloadFile, int, error) {
+	levels, err := synth.ParseLevels(generateLevels)
+	if err != nil {
+		return nil, 0, err
+	}
+	if generateRate <= 0 || span <= 0 {
+		return nil, 0, fmt.Errorf("--rate and --span must be positive")
+	}
+	end := time.Now().Truncate(time.Second)
+	start := end.Add(-span)
+	records, err := synth.Generate(synth.Options{
+		Start:       start,
+		End:         end,
+		Count:       int(span.Minutes() * float64(generateRate)),
+		Services:    generateServices,
+		Levels:      levels,
+		Profile:     generateProfile,
+		Bursts:      generateBursts,
+		BurstLength: generateBurstLength,
+		Seed:        generateSeed,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	files, err := generateFiles(records, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	return files, len(records), nil
+}
+
+func runGenerateCmd(_ *cobra.Command, _ []string) error {
+	files, records, err := generatePayloads(generateSpan)
+	if err != nil {
+		return err
+	}
+
+	if generateDir != "" {
+		for _, f := range files {
+			path := filepath.Join(generateDir, filepath.FromSlash(f.key))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.WriteFile(path, f.data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+		log.Printf("Wrote %d files with %d log records to %s", len(files), records, generateDir)
+		return nil
+	}
+
+	creds, err := minioCredentials(generateAccessKey, generateSecretKey)
+	if err != nil {
+		return fmt.Errorf("%w, or use --dir", err)
+	}
+	minioClient, err := minio.New(generateEndpoint, &minio.Options{
+		Creds:  creds,
+		Secure: generateSecure,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MinIO client: %w", err)
+	}
+	if err := uploadFiles(context.Background(), minioClient, generateBucket, files); err != nil {
+		return err
+	}
+	log.Printf("Uploaded %d files with %d log records to %s/%s", len(files), records, generateBucket, generatePrefix)
+	return nil
+}
+
+func uploadFiles(ctx context.Context, client *minio.Client, bucket string, files []payloadFile) error {
+	for _, f := range files {
+		_, err := client.PutObject(ctx, bucket, f.key, bytes.NewReader(f.data), int64(len(f.data)), minio.PutObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to upload %s: %w", f.key, err)
+		}
+	}
+	return nil
+}
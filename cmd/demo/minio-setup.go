@@ -37,8 +37,9 @@ var (
 )
 
 var MinioSetupCmd = &cobra.Command{
-	Use:   "minio-setup",
-	Short: "Set up MinIO bucket and webhook configuration",
+	Use:        "minio-setup",
+	Short:      "Set up MinIO bucket and webhook configuration",
+	Deprecated: "use 'demo up', which only changes what is missing and waits for MinIO instead of sleeping",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Println("Starting MinIO setup...")
 
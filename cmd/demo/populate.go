@@ -25,7 +25,6 @@ import (
 
 	"github.com/google/go-github/v57/github"
 	minio "github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/spf13/cobra"
 )
 
@@ -40,13 +39,21 @@ var (
 )
 
 var PopulateCmd = &cobra.Command{
-	Use:   "populate",
-	Short: "Download files from GitHub and upload them to MinIO",
+	Use:        "populate",
+	Short:      "Download files from GitHub and upload them to MinIO",
+	Deprecated: "use 'demo generate' to create demo data without a GitHub download",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if populateRepoOwner == "" || populateRepoName == "" {
+			return fmt.Errorf("--repo-owner and --repo-name are required")
+		}
+		creds, err := minioCredentials(populateAccessKey, populateSecretKey)
+		if err != nil {
+			return err
+		}
 		log.Println("Starting file population...")
 
 		minioClient, err := minio.New(populateEndpoint, &minio.Options{
-			Creds:  credentials.NewStaticV4(populateAccessKey, populateSecretKey, ""),
+			Creds:  creds,
 			Secure: false,
 		})
 		if err != nil {
@@ -86,11 +93,11 @@ var PopulateCmd = &cobra.Command{
 
 func init() {
 	PopulateCmd.Flags().StringVar(&populateEndpoint, "endpoint", "localhost:9000", "MinIO endpoint")
-	PopulateCmd.Flags().StringVar(&populateAccessKey, "access-key", "muhfw6BmPZQxyzTZfBnO", "MinIO access key")
-	PopulateCmd.Flags().StringVar(&populateSecretKey, "secret-key", "tyLdAHxvvjXMTx8sQM8znvgh2itpfNYwmPClPw5", "MinIO secret key")
+	PopulateCmd.Flags().StringVar(&populateAccessKey, "access-key", "", "MinIO access key (default $MINIO_ROOT_USER or $AWS_ACCESS_KEY_ID)")
+	PopulateCmd.Flags().StringVar(&populateSecretKey, "secret-key", "", "MinIO secret key (default $MINIO_ROOT_PASSWORD or $AWS_SECRET_ACCESS_KEY)")
 	PopulateCmd.Flags().StringVar(&populateBucketName, "bucket", "lakerunner", "Bucket name")
-	PopulateCmd.Flags().StringVar(&populateRepoOwner, "repo-owner", "aditya-prajapati", "GitHub repo owner")
-	PopulateCmd.Flags().StringVar(&populateRepoName, "repo-name", "otel-pq", "GitHub repo name")
+	PopulateCmd.Flags().StringVar(&populateRepoOwner, "repo-owner", "", "GitHub repo owner")
+	PopulateCmd.Flags().StringVar(&populateRepoName, "repo-name", "", "GitHub repo name")
 	PopulateCmd.Flags().StringVar(&populateRepoPath, "repo-path", "otel-raw", "Path within the repo to download")
 }
 
@@ -0,0 +1,105 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lakerunner/cli/internal/apitest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr    string
+	serveFixture string
+	serveEntries int
+	serveSpan    time.Duration
+	serveSeed    uint64
+	serveKey     string
+)
+
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local mock of the Lakerunner query API",
+	Long: `Serve /api/v1/logs/query, /api/v1/logs/tags and /api/v1/logs/tagvalues
+from memory, for trying the CLI without a deployment.
+
+Entries come from --fixture (an export in any format --from-file reads) or
+are generated: --entries lines from a handful of services spread over the
+last --span. Queries support stream selectors and line filters; parser
+stages are rejected, so the CLI falls back to parsing client-side.`,
+	Example: `  lakerunner demo serve &
+  LAKERUNNER_QUERY_URL=http://localhost:7101 LAKERUNNER_API_KEY=demo lakerunner logs get -l ERROR`,
+	RunE: runServeCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	ServeCmd.Flags().StringVar(&serveAddr, "addr", "localhost:7101", "Address to listen on")
+	ServeCmd.Flags().StringVar(&serveFixture, "fixture", "", "Serve the entries in this export (.ndjson, .ndjson.gz or .parquet) instead of generated data")
+	ServeCmd.Flags().IntVar(&serveEntries, "entries", 10000, "Number of entries to generate")
+	ServeCmd.Flags().DurationVar(&serveSpan, "span", 24*time.Hour, "Spread generated entries over this much time up to now")
+	ServeCmd.Flags().Uint64Var(&serveSeed, "seed", 1, "Seed for generated entries")
+	ServeCmd.Flags().StringVar(&serveKey, "key", "", "Require clients to send this API key (default: accept any)")
+}
+
+func runServeCmd(_ *cobra.Command, _ []string) error {
+	var srv *apitest.Server
+	if serveFixture != "" {
+		var err error
+		if srv, err = apitest.NewFromFile(serveFixture); err != nil {
+			return fmt.Errorf("failed to load fixture: %w", err)
+		}
+	} else {
+		now := time.Now()
+		srv = apitest.New(apitest.Generate(serveEntries, now.Add(-serveSpan), now, serveSeed))
+	}
+	srv.APIKey = serveKey
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+	}
+	url := "http://" + listener.Addr().String()
+	fmt.Printf("Serving %d entries on %s\n", srv.Len(), url)
+	fmt.Printf("  export LAKERUNNER_QUERY_URL=%s\n", url)
+	if serveKey != "" {
+		fmt.Printf("  export LAKERUNNER_API_KEY=%s\n", serveKey)
+	} else {
+		fmt.Println("  export LAKERUNNER_API_KEY=demo")
+	}
+
+	httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/spf13/cobra"
+)
+
+// webhookTarget is the ID of the MinIO webhook target demo up configures.
+const webhookTarget = "lakerunner"
+
+// rawPrefixes are the prefixes Lakerunner ingests from.
+var rawPrefixes = []string{"otel-raw/", "logs-raw/", "metrics-raw/"}
+
+var webhookArn = notification.NewArn("minio", "sqs", "", webhookTarget, "webhook")
+
+// Flags shared by demo up, status and down.
+var (
+	stackEndpoint  string
+	stackAccessKey string
+	stackSecretKey string
+	stackBucket    string
+	stackSecure    bool
+	stackWait      time.Duration
+	stackDryRun    bool
+)
+
+func addStackFlags(cmd *cobra.Command, wait bool) {
+	cmd.Flags().StringVar(&stackEndpoint, "endpoint", "localhost:9000", "MinIO endpoint")
+	cmd.Flags().StringVar(&stackAccessKey, "access-key", "", "MinIO access key (default $MINIO_ROOT_USER or $AWS_ACCESS_KEY_ID)")
+	cmd.Flags().StringVar(&stackSecretKey, "secret-key", "", "MinIO secret key (default $MINIO_ROOT_PASSWORD or $AWS_SECRET_ACCESS_KEY)")
+	cmd.Flags().StringVar(&stackBucket, "bucket", "lakerunner", "Bucket name")
+	cmd.Flags().BoolVar(&stackSecure, "secure", false, "Use HTTPS to reach the endpoint")
+	if wait {
+		cmd.Flags().DurationVar(&stackWait, "wait", time.Minute, "How long to wait for MinIO to become ready")
+	}
+}
+
+// stack is the part of a MinIO deployment the demo commands manage.
+type stack interface {
+	// Ready returns nil once the server answers admin requests.
+	Ready(ctx context.Context) error
+	BucketExists(ctx context.Context) (bool, error)
+	MakeBucket(ctx context.Context) error
+	// RemoveBucket deletes the bucket and every object in it.
+	RemoveBucket(ctx context.Context) error
+	HasObjects(ctx context.Context, prefix string) (bool, error)
+	Upload(ctx context.Context, files []payloadFile) error
+	// WebhookEndpoint returns the endpoint of the webhook target, or "" when
+	// it is not configured.
+	WebhookEndpoint(ctx context.Context) (string, error)
+	// WebhookStatus returns the target's state as MinIO sees it, e.g.
+	// "online" or "offline", or "" when MinIO does not report it.
+	WebhookStatus(ctx context.Context) (string, error)
+	// SetWebhook and DeleteWebhook report whether MinIO must restart to apply
+	// the change.
+	SetWebhook(ctx context.Context, endpoint string) (bool, error)
+	DeleteWebhook(ctx context.Context) (bool, error)
+	Restart(ctx context.Context) error
+	Notifications(ctx context.Context) (notification.Configuration, error)
+	SetNotifications(ctx context.Context, config notification.Configuration) error
+}
+
+// minioStack implements stack with the MinIO S3 and admin APIs.
+type minioStack struct {
+	client *minio.Client
+	admin  *madmin.AdminClient
+	bucket string
+}
+
+func newMinioStack() (*minioStack, error) {
+	creds, err := minioCredentials(stackAccessKey, stackSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	client, err := minio.New(stackEndpoint, &minio.Options{Creds: creds, Secure: stackSecure})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MinIO client: %w", err)
+	}
+	admin, err := madmin.NewWithOptions(stackEndpoint, &madmin.Options{Creds: creds, Secure: stackSecure})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MinIO admin client: %w", err)
+	}
+	return &minioStack{client: client, admin: admin, bucket: stackBucket}, nil
+}
+
+func (s *minioStack) Ready(ctx context.Context) error {
+	_, err := s.admin.ServerInfo(ctx)
+	return err
+}
+
+func (s *minioStack) BucketExists(ctx context.Context) (bool, error) {
+	return s.client.BucketExists(ctx, s.bucket)
+}
+
+func (s *minioStack) MakeBucket(ctx context.Context) error {
+	return s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{})
+}
+
+func (s *minioStack) RemoveBucket(ctx context.Context) error {
+	return s.client.RemoveBucketWithOptions(ctx, s.bucket, minio.RemoveBucketOptions{ForceDelete: true})
+}
+
+func (s *minioStack) HasObjects(ctx context.Context, prefix string) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true, MaxKeys: 1}) {
+		return obj.Err == nil, obj.Err
+	}
+	return false, nil
+}
+
+func (s *minioStack) Upload(ctx context.Context, files []payloadFile) error {
+	return uploadFiles(ctx, s.client, s.bucket, files)
+}
+
+func (s *minioStack) WebhookEndpoint(ctx context.Context) (string, error) {
+	data, err := s.admin.GetConfigKV(ctx, "notify_webhook:"+webhookTarget)
+	if err != nil {
+		var resp madmin.ErrorResponse
+		if errors.As(err, &resp) {
+			// The server answered, it just has no such target.
+			return "", nil
+		}
+		return "", err
+	}
+	return configValue(string(data), "endpoint"), nil
+}
+
+func (s *minioStack) WebhookStatus(ctx context.Context) (string, error) {
+	info, err := s.admin.ServerInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, services := range info.Services.Notifications {
+		for _, targets := range services {
+			for _, target := range targets {
+				for id, status := range target {
+					if strings.Contains(id, webhookTarget) {
+						return strings.ToLower(status.Status), nil
+					}
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *minioStack) SetWebhook(ctx context.Context, endpoint string) (bool, error) {
+	return s.admin.SetConfigKV(ctx, fmt.Sprintf("notify_webhook:%s enable=on endpoint=%q", webhookTarget, endpoint))
+}
+
+func (s *minioStack) DeleteWebhook(ctx context.Context) (bool, error) {
+	return s.admin.DelConfigKV(ctx, "notify_webhook:"+webhookTarget)
+}
+
+func (s *minioStack) Restart(ctx context.Context) error {
+	return s.admin.ServiceRestartV2(ctx)
+}
+
+func (s *minioStack) Notifications(ctx context.Context) (notification.Configuration, error) {
+	return s.client.GetBucketNotification(ctx, s.bucket)
+}
+
+func (s *minioStack) SetNotifications(ctx context.Context, config notification.Configuration) error {
+	return s.client.SetBucketNotification(ctx, s.bucket, config)
+}
+
+// configValue returns the value of key in a MinIO config KV line such as
+// `notify_webhook:x enable=on endpoint="http://..."`.
+func configValue(kv, key string) string {
+	for _, field := range strings.Fields(kv) {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name != key {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value
+	}
+	return ""
+}
+
+// pollInterval is how often poll retries.
+var pollInterval = 500 * time.Millisecond
+
+// permanentError stops poll from retrying.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// poll calls fn until it succeeds or timeout passes, returning the last error.
+// An error wrapped in permanentError is returned at once.
+func poll(ctx context.Context, timeout time.Duration, what string, fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %s: %w", timeout, what, err)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// notifies reports whether config sends object creation events under prefix
+// to the webhook target.
+func notifies(config notification.Configuration, prefix string) bool {
+	return slices.ContainsFunc(config.QueueConfigs, func(q notification.QueueConfig) bool {
+		if q.Queue != webhookArn.String() && q.Arn != webhookArn {
+			return false
+		}
+		if q.Filter == nil {
+			return true
+		}
+		for _, rule := range q.Filter.S3Key.FilterRules {
+			if strings.EqualFold(rule.Name, "prefix") && !strings.HasPrefix(prefix, rule.Value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// missingNotifications returns the raw prefixes config does not cover.
+func missingNotifications(config notification.Configuration) []string {
+	var missing []string
+	for _, prefix := range rawPrefixes {
+		if !notifies(config, prefix) {
+			missing = append(missing, prefix)
+		}
+	}
+	return missing
+}
+
+// step is one change to a stack.
+type step struct {
+	description string
+	apply       func(ctx context.Context) error
+}
+
+// applySteps applies steps in order, or only logs them when dryRun is set.
+func applySteps(ctx context.Context, steps []step, dryRun bool) error {
+	if len(steps) == 0 {
+		log.Println("Nothing to do")
+		return nil
+	}
+	for _, s := range steps {
+		if dryRun {
+			log.Println("Would", s.description)
+			continue
+		}
+		log.Println(capitalize(s.description))
+		if err := s.apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s: %w", s.description, err)
+		}
+	}
+	return nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// errTargetPending is returned while MinIO does not yet report the webhook
+// target the way the last config change left it.
+var errTargetPending = errors.New("webhook target not reloaded yet")
+
+// restartIfNeeded restarts MinIO when a config change asks for it and waits
+// for the server to come back with the webhook target loaded, or gone when
+// wantTarget is false. The restart request returns before the server goes
+// down, so answering admin requests alone does not mean it has restarted.
+func restartIfNeeded(ctx context.Context, s stack, restart, wantTarget bool, wait time.Duration) error {
+	if !restart {
+		return nil
+	}
+	log.Println("Restarting MinIO to apply the webhook config...")
+	if err := s.Restart(ctx); err != nil {
+		return fmt.Errorf("failed to restart MinIO: %w", err)
+	}
+	return poll(ctx, wait, "MinIO to restart with the webhook config", func(ctx context.Context) error {
+		if err := s.Ready(ctx); err != nil {
+			return err
+		}
+		status, err := s.WebhookStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if loaded := status != ""; loaded != wantTarget {
+			return errTargetPending
+		}
+		return nil
+	})
+}
+
+// retryableNotificationError reports whether setting bucket notifications may
+// succeed on a later attempt: MinIO answers InvalidArgument while the webhook
+// ARN is not loaded, and connection errors may pass. Anything else, such as
+// AccessDenied, will not change by waiting.
+func retryableNotificationError(err error) bool {
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) {
+		return true
+	}
+	return resp.Code == "InvalidArgument"
+}
@@ -0,0 +1,262 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// fakeStack is an in-memory MinIO. A restart takes effect one Ready call
+// later, then the server stays down for a few more and comes back with the
+// webhook target as configured. It rejects the webhook ARN until the target
+// has loaded.
+type fakeStack struct {
+	bucket    bool
+	objects   map[string]bool
+	webhook   string
+	loaded    bool
+	stale     int
+	down      int
+	restarts  int
+	config    notification.Configuration
+	notifyErr error
+}
+
+var errDown = errors.New("connection refused")
+
+func (f *fakeStack) Ready(context.Context) error {
+	if f.stale > 0 {
+		// The old process still answers.
+		f.stale--
+		return nil
+	}
+	if f.down > 0 {
+		f.down--
+		if f.down == 0 {
+			f.loaded = f.webhook != ""
+		}
+		return errDown
+	}
+	return nil
+}
+
+func (f *fakeStack) BucketExists(context.Context) (bool, error) { return f.bucket, nil }
+func (f *fakeStack) MakeBucket(context.Context) error           { f.bucket = true; return nil }
+
+func (f *fakeStack) RemoveBucket(context.Context) error {
+	f.bucket, f.objects, f.config = false, nil, notification.Configuration{}
+	return nil
+}
+
+func (f *fakeStack) HasObjects(_ context.Context, prefix string) (bool, error) {
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeStack) Upload(_ context.Context, files []payloadFile) error {
+	if f.objects == nil {
+		f.objects = make(map[string]bool)
+	}
+	for _, file := range files {
+		f.objects[file.key] = true
+	}
+	return nil
+}
+
+func (f *fakeStack) WebhookEndpoint(context.Context) (string, error) { return f.webhook, nil }
+
+func (f *fakeStack) WebhookStatus(context.Context) (string, error) {
+	if f.loaded {
+		return "online", nil
+	}
+	return "", nil
+}
+
+func (f *fakeStack) SetWebhook(_ context.Context, endpoint string) (bool, error) {
+	f.webhook = endpoint
+	return true, nil
+}
+
+func (f *fakeStack) DeleteWebhook(context.Context) (bool, error) {
+	f.webhook = ""
+	return true, nil
+}
+
+func (f *fakeStack) Restart(context.Context) error {
+	f.restarts++
+	f.stale = 1
+	f.down = max(f.down, 2)
+	return nil
+}
+
+func (f *fakeStack) Notifications(context.Context) (notification.Configuration, error) {
+	return f.config, nil
+}
+
+func (f *fakeStack) SetNotifications(_ context.Context, config notification.Configuration) error {
+	if f.notifyErr != nil {
+		return f.notifyErr
+	}
+	if !f.loaded && len(config.QueueConfigs) > 0 {
+		return minio.ErrorResponse{Code: "InvalidArgument", Message: "A specified destination ARN does not exist or is not well-formed"}
+	}
+	f.config = config
+	return nil
+}
+
+func descriptions(steps []step) []string {
+	var out []string
+	for _, s := range steps {
+		out = append(out, s.description)
+	}
+	return out
+}
+
+func TestUpIsIdempotent(t *testing.T) {
+	pollInterval = time.Millisecond
+	ctx := context.Background()
+	f := &fakeStack{}
+	const webhook = "http://pubsub:8080"
+
+	steps, err := planUp(ctx, f, webhook, true, 10*time.Minute, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 4 {
+		t.Fatalf("planned %q, want 4 steps", descriptions(steps))
+	}
+	if err := applySteps(ctx, steps, true); err != nil || f.bucket || f.webhook != "" {
+		t.Fatalf("dry run changed the stack: %v %+v", err, f)
+	}
+	if err := applySteps(ctx, steps, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.restarts != 1 || !f.bucket || len(f.objects) == 0 {
+		t.Errorf("stack after up: %+v", f)
+	}
+	if missing := missingNotifications(f.config); len(missing) > 0 {
+		t.Errorf("missing notifications for %v", missing)
+	}
+
+	steps, err = planUp(ctx, f, webhook, true, 10*time.Minute, time.Second)
+	if err != nil || len(steps) != 0 {
+		t.Errorf("second up planned %q, %v", descriptions(steps), err)
+	}
+	for _, c := range stackChecks(ctx, f) {
+		if c.status != statusOK {
+			t.Errorf("check after up: %s", c.msg)
+		}
+	}
+
+	steps, _ = planUp(ctx, f, "http://other:8080", true, 10*time.Minute, time.Second)
+	if len(steps) != 1 || !strings.Contains(steps[0].description, "was "+webhook) {
+		t.Errorf("changed endpoint planned %q", descriptions(steps))
+	}
+}
+
+func TestDown(t *testing.T) {
+	pollInterval = time.Millisecond
+	ctx := context.Background()
+	other := notification.NewConfig(notification.NewArn("minio", "sqs", "", "other", "webhook"))
+	other.AddEvents(notification.ObjectRemovedAll)
+	f := &fakeStack{}
+	steps, _ := planUp(ctx, f, "http://pubsub:8080", false, 0, time.Second)
+	if err := applySteps(ctx, steps, false); err != nil {
+		t.Fatal(err)
+	}
+	f.config.AddQueue(other)
+
+	steps, err := planDown(ctx, f, false, time.Second)
+	if err != nil || len(steps) != 2 {
+		t.Fatalf("planned %q, %v", descriptions(steps), err)
+	}
+	if err := applySteps(ctx, steps, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.webhook != "" || !f.bucket || len(f.config.QueueConfigs) != 1 || f.config.QueueConfigs[0].Queue != other.Arn.String() {
+		t.Errorf("stack after down: %+v", f)
+	}
+	if steps, _ := planDown(ctx, f, false, time.Second); len(steps) != 0 {
+		t.Errorf("second down planned %q", descriptions(steps))
+	}
+	steps, _ = planDown(ctx, f, true, time.Second)
+	if err := applySteps(ctx, steps, false); err != nil || f.bucket {
+		t.Errorf("bucket not deleted: %v", err)
+	}
+}
+
+func TestRestartTimeout(t *testing.T) {
+	pollInterval = time.Millisecond
+	f := &fakeStack{down: 1 << 30}
+	err := restartIfNeeded(context.Background(), f, true, true, 20*time.Millisecond)
+	if err == nil || !errors.Is(err, errDown) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRestartWaitsForWebhookTarget(t *testing.T) {
+	pollInterval = time.Millisecond
+	ctx := context.Background()
+	f := &fakeStack{webhook: "http://pubsub:8080", loaded: true}
+	if _, err := f.DeleteWebhook(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := restartIfNeeded(ctx, f, true, false, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if f.loaded || f.stale != 0 || f.down != 0 {
+		t.Errorf("returned before MinIO restarted: %+v", f)
+	}
+}
+
+func TestUpStopsOnAccessDenied(t *testing.T) {
+	pollInterval = time.Millisecond
+	ctx := context.Background()
+	denied := minio.ErrorResponse{Code: "AccessDenied", Message: "Access Denied."}
+	f := &fakeStack{bucket: true, webhook: "http://pubsub:8080", loaded: true, notifyErr: denied}
+	steps, err := planUp(ctx, f, "http://pubsub:8080", false, 0, time.Minute)
+	if err != nil || len(steps) != 1 {
+		t.Fatalf("planned %q, %v", descriptions(steps), err)
+	}
+	started := time.Now()
+	err = applySteps(ctx, steps, false)
+	if !errors.As(err, &minio.ErrorResponse{}) || strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want AccessDenied without retrying", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %s to give up", elapsed)
+	}
+}
+
+func TestConfigValue(t *testing.T) {
+	kv := `notify_webhook:lakerunner enable=on endpoint="http://pubsub:8080/x" auth_token=`
+	if got := configValue(kv, "endpoint"); got != "http://pubsub:8080/x" {
+		t.Errorf("endpoint = %q", got)
+	}
+	if got := configValue(kv, "queue_dir"); got != "" {
+		t.Errorf("queue_dir = %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the bucket, webhook target and notifications demo up sets up",
+	Long: `Check that MinIO is reachable, the bucket exists, the webhook target is
+configured and reported online by MinIO (so MinIO itself can reach the
+endpoint, which may be an in-cluster address), and that object events under
+otel-raw/, logs-raw/ and metrics-raw/ are sent to it. Exits non-zero when a
+check fails; run demo up to fix them.`,
+	RunE: runStatusCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	addStackFlags(StatusCmd, false)
+}
+
+type checkStatus int
+
+const (
+	statusOK checkStatus = iota
+	statusWarn
+	statusFail
+)
+
+func (s checkStatus) symbol() string {
+	switch s {
+	case statusWarn:
+		return "!"
+	case statusFail:
+		return "✗"
+	}
+	return "✓"
+}
+
+type check struct {
+	status checkStatus
+	msg    string
+}
+
+func ok(format string, args ...any) check   { return check{statusOK, fmt.Sprintf(format, args...)} }
+func warn(format string, args ...any) check { return check{statusWarn, fmt.Sprintf(format, args...)} }
+func fail(format string, args ...any) check { return check{statusFail, fmt.Sprintf(format, args...)} }
+
+// stackChecks inspects s without changing it.
+func stackChecks(ctx context.Context, s stack) []check {
+	if err := s.Ready(ctx); err != nil {
+		return []check{fail("MinIO unreachable: %v", err)}
+	}
+	checks := []check{ok("MinIO reachable")}
+
+	exists, err := s.BucketExists(ctx)
+	switch {
+	case err != nil:
+		checks = append(checks, fail("bucket %s: %v", stackBucket, err))
+	case !exists:
+		checks = append(checks, fail("bucket %s does not exist", stackBucket))
+	default:
+		checks = append(checks, ok("bucket %s exists", stackBucket))
+	}
+
+	endpoint, err := s.WebhookEndpoint(ctx)
+	switch {
+	case err != nil:
+		checks = append(checks, fail("webhook target %s: %v", webhookTarget, err))
+	case endpoint == "":
+		checks = append(checks, fail("webhook target %s is not configured", webhookTarget))
+	default:
+		checks = append(checks, ok("webhook target %s points at %s", webhookTarget, endpoint))
+		status, err := s.WebhookStatus(ctx)
+		switch {
+		case err != nil:
+			checks = append(checks, warn("webhook status unknown: %v", err))
+		case status == "online":
+			checks = append(checks, ok("webhook endpoint reachable from MinIO"))
+		case status == "":
+			checks = append(checks, warn("MinIO does not report the webhook status; it may need a restart"))
+		default:
+			checks = append(checks, fail("webhook endpoint %s from MinIO", status))
+		}
+	}
+
+	if exists {
+		config, err := s.Notifications(ctx)
+		if err != nil {
+			checks = append(checks, fail("bucket notifications: %v", err))
+		} else if missing := missingNotifications(config); len(missing) > 0 {
+			checks = append(checks, fail("no notifications to %s for %s", webhookTarget, strings.Join(missing, ", ")))
+		} else {
+			checks = append(checks, ok("notifications for %s", strings.Join(rawPrefixes, ", ")))
+		}
+
+		prefix := generatePrefix + "/"
+		if hasData, err := s.HasObjects(ctx, prefix); err != nil {
+			checks = append(checks, warn("%s: %v", prefix, err))
+		} else if !hasData {
+			checks = append(checks, warn("%s is empty; demo generate uploads some data", prefix))
+		} else {
+			checks = append(checks, ok("%s has data", prefix))
+		}
+	}
+	return checks
+}
+
+func runStatusCmd(cmd *cobra.Command, _ []string) error {
+	s, err := newMinioStack()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	fmt.Println("MinIO " + stackEndpoint)
+	failed := 0
+	for _, c := range stackChecks(ctx, s) {
+		fmt.Printf("  %s %s\n", c.status.symbol(), c.msg)
+		if c.status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
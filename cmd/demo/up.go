@@ -0,0 +1,155 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upWebhookEndpoint string
+	upSeed            bool
+	upSeedSpan        time.Duration
+)
+
+var UpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Bootstrap MinIO for a local Lakerunner: bucket, webhook, notifications and demo data",
+	Long: `Bring a MinIO deployment to the state a local Lakerunner needs: the bucket
+exists, a webhook target points at Lakerunner's pubsub endpoint, object
+creation under otel-raw/, logs-raw/ and metrics-raw/ is sent to it, and
+otel-raw/ holds some generated data to look at.
+
+Only the missing pieces are changed, so running it again is safe. It waits for
+MinIO to come up (and back after a restart the webhook config needs) instead
+of sleeping. --dry-run prints what would change without touching anything.`,
+	Example: `  lakerunner demo up --dry-run
+  lakerunner demo up --webhook-endpoint http://host.docker.internal:8080 --seed-span 6h`,
+	RunE: runUpCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	addStackFlags(UpCmd, true)
+	UpCmd.Flags().StringVar(&upWebhookEndpoint, "webhook-endpoint", "http://lakerunner-pubsub-http.lakerunner.svc.cluster.local:8080", "Endpoint MinIO sends object events to")
+	UpCmd.Flags().BoolVar(&upSeed, "seed", true, "Upload generated demo data when otel-raw/ is empty")
+	UpCmd.Flags().DurationVar(&upSeedSpan, "seed-span", time.Hour, "How much time the demo data covers")
+	UpCmd.Flags().BoolVar(&stackDryRun, "dry-run", false, "Print the changes without making them")
+}
+
+func runUpCmd(cmd *cobra.Command, _ []string) error {
+	s, err := newMinioStack()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	if err := poll(ctx, stackWait, "MinIO at "+stackEndpoint, s.Ready); err != nil {
+		return err
+	}
+	steps, err := planUp(ctx, s, upWebhookEndpoint, upSeed, upSeedSpan, stackWait)
+	if err != nil {
+		return err
+	}
+	return applySteps(ctx, steps, stackDryRun)
+}
+
+// planUp returns the steps that bring s to the state demo up sets up.
+func planUp(ctx context.Context, s stack, webhook string, seed bool, seedSpan, wait time.Duration) ([]step, error) {
+	var steps []step
+	exists, err := s.BucketExists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket: %w", err)
+	}
+	if !exists {
+		steps = append(steps, step{"create bucket " + stackBucket, s.MakeBucket})
+	}
+
+	current, err := s.WebhookEndpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+	if current != webhook {
+		description := fmt.Sprintf("point webhook target %s at %s", webhookTarget, webhook)
+		if current != "" {
+			description += " (was " + current + ")"
+		}
+		steps = append(steps, step{description, func(ctx context.Context) error {
+			restart, err := s.SetWebhook(ctx, webhook)
+			if err != nil {
+				return err
+			}
+			return restartIfNeeded(ctx, s, restart, true, wait)
+		}})
+	}
+
+	missing := rawPrefixes
+	if exists {
+		config, err := s.Notifications(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bucket notifications: %w", err)
+		}
+		missing = missingNotifications(config)
+	}
+	if len(missing) > 0 {
+		description := fmt.Sprintf("send object events under %s in %s to webhook target %s", strings.Join(missing, ", "), stackBucket, webhookTarget)
+		steps = append(steps, step{description, func(ctx context.Context) error {
+			config, err := s.Notifications(ctx)
+			if err != nil {
+				return err
+			}
+			for _, prefix := range missingNotifications(config) {
+				queue := notification.NewConfig(webhookArn)
+				queue.AddEvents(notification.ObjectCreatedAll)
+				queue.AddFilterPrefix(prefix)
+				config.AddQueue(queue)
+			}
+			// MinIO rejects the ARN until the webhook target has loaded.
+			return poll(ctx, wait, "the webhook target to load", func(ctx context.Context) error {
+				err := s.SetNotifications(ctx, config)
+				if err != nil && !retryableNotificationError(err) {
+					return permanentError{err}
+				}
+				return err
+			})
+		}})
+	}
+
+	if seed {
+		prefix := generatePrefix + "/"
+		hasData := false
+		if exists {
+			if hasData, err = s.HasObjects(ctx, prefix); err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+			}
+		}
+		if !hasData {
+			description := fmt.Sprintf("upload %s of generated logs and metrics to %s/%s", seedSpan, stackBucket, prefix)
+			steps = append(steps, step{description, func(ctx context.Context) error {
+				files, _, err := generatePayloads(seedSpan)
+				if err != nil {
+					return err
+				}
+				return s.Upload(ctx, files)
+			}})
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,384 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lakerunner/cli/internal/anomaly"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	anomaliesQuery     queryFlags
+	anomaliesBucket    time.Duration
+	anomaliesMethod    string
+	anomaliesThreshold float64
+	anomaliesGroupBy   []string
+	anomaliesLimit     int
+	anomaliesMinCount  int64
+	anomaliesTop       int
+	anomaliesOutput    string
+)
+
+var AnomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Find spikes and drops in log volume",
+	Long: `Count matching log entries per time bucket for each service and level
+(or the tags given with --group-by) over the lookback window, and flag the
+buckets whose volume deviates from the rest of the window by more than
+--threshold. Consecutive flagged buckets are merged into one anomaly.
+
+Each anomaly is printed with a lakerunner logs get command line that
+fetches the entries behind it.`,
+	Example: `  # Spikes over the last six hours in 5 minute buckets
+  lakerunner logs anomalies -s e-6h --bucket 5m
+
+  # Only errors, using z-scores
+  lakerunner logs anomalies -l ERROR --method zscore --threshold 2.5`,
+	RunE: runAnomaliesCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	AnomaliesCmd.Flags().DurationVar(&anomaliesBucket, "bucket", time.Minute, "Bucket size")
+	AnomaliesCmd.Flags().StringVar(&anomaliesMethod, "method", string(anomaly.MAD), "Deviation measure: mad, zscore")
+	AnomaliesCmd.Flags().Float64Var(&anomaliesThreshold, "threshold", 3.5, "Flag buckets whose score is at least this far from the baseline")
+	AnomaliesCmd.Flags().StringSliceVar(&anomaliesGroupBy, "group-by", []string{"service", "level"}, "Tag keys to count volume by")
+	AnomaliesCmd.Flags().IntVar(&anomaliesLimit, "limit", 50000, "Maximum number of log entries to scan")
+	AnomaliesCmd.Flags().Int64Var(&anomaliesMinCount, "min-count", 10, "Ignore groups with fewer entries than this in the whole window")
+	AnomaliesCmd.Flags().IntVar(&anomaliesTop, "top", 10, "Show only the N highest-scoring anomalies (0 = all)")
+	AnomaliesCmd.Flags().StringVarP(&anomaliesOutput, "output", "o", "text", "Output format: text, json")
+	anomaliesQuery.register(AnomaliesCmd)
+}
+
+// volumeGroup is one series of bucket counts, e.g. service=checkout,level=ERROR.
+type volumeGroup struct {
+	values  []string
+	buckets []float64
+	total   int64
+}
+
+// volumeCounter buckets entries by time and group.
+type volumeCounter struct {
+	startNs  int64
+	bucketNs int64
+	n        int
+	keys     []string
+	groups   map[string]*volumeGroup
+}
+
+func newVolumeCounter(startMs, endMs int64, bucket time.Duration, keys []string) *volumeCounter {
+	bucketNs := bucket.Nanoseconds()
+	spanNs := (endMs - startMs) * int64(time.Millisecond)
+	return &volumeCounter{
+		startNs:  startMs * int64(time.Millisecond),
+		bucketNs: bucketNs,
+		n:        int((spanNs + bucketNs - 1) / bucketNs),
+		keys:     keys,
+		groups:   make(map[string]*volumeGroup),
+	}
+}
+
+// add counts one entry; entries outside the window are ignored.
+func (c *volumeCounter) add(message, tags map[string]any) {
+	ns, ok := entryTimestampNs(message)
+	if !ok {
+		return
+	}
+	i := int((ns - c.startNs) / c.bucketNs)
+	if ns < c.startNs || i >= c.n {
+		return
+	}
+	values := make([]string, len(c.keys))
+	for k, key := range c.keys {
+		if v, _, ok := fieldnames.Lookup(tags, key); ok {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	id := strings.Join(values, "\x00")
+	g, ok := c.groups[id]
+	if !ok {
+		g = &volumeGroup{values: values, buckets: make([]float64, c.n)}
+		c.groups[id] = g
+	}
+	g.buckets[i]++
+	g.total++
+}
+
+// volumeAnomaly is a run of consecutive flagged buckets in one group.
+type volumeAnomaly struct {
+	Group     map[string]string `json:"group"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Direction string            `json:"direction"`
+	Score     float64           `json:"score"`
+	Peak      int64             `json:"peak"`
+	Baseline  float64           `json:"baseline"`
+	Command   string            `json:"command"`
+}
+
+// detect scores every group and merges consecutive flagged buckets with the
+// same direction. Results are ordered by absolute score, highest first.
+func (c *volumeCounter) detect(method anomaly.Method, threshold float64, minCount int64) []volumeAnomaly {
+	var out []volumeAnomaly
+	for _, g := range c.groups {
+		if g.total < minCount {
+			continue
+		}
+		centre, _ := anomaly.Baseline(g.buckets, method)
+		scores := anomaly.Scores(g.buckets, method)
+		var cur *volumeAnomaly
+		for i, s := range scores {
+			dir := ""
+			switch {
+			case s >= threshold:
+				dir = "spike"
+			case s <= -threshold:
+				dir = "drop"
+			}
+			if cur != nil && dir != cur.Direction {
+				out = append(out, *cur)
+				cur = nil
+			}
+			if dir == "" {
+				continue
+			}
+			bucketStart := time.Unix(0, c.startNs+int64(i)*c.bucketNs)
+			if cur == nil {
+				cur = &volumeAnomaly{Group: c.groupLabels(g), Start: bucketStart, Direction: dir, Baseline: centre}
+			}
+			cur.End = bucketStart.Add(time.Duration(c.bucketNs))
+			if math.Abs(s) > math.Abs(cur.Score) {
+				cur.Score = s
+				cur.Peak = int64(g.buckets[i])
+			}
+		}
+		if cur != nil {
+			out = append(out, *cur)
+		}
+	}
+	slices.SortFunc(out, func(a, b volumeAnomaly) int {
+		if d := math.Abs(b.Score) - math.Abs(a.Score); d != 0 {
+			if d > 0 {
+				return 1
+			}
+			return -1
+		}
+		return a.Start.Compare(b.Start)
+	})
+	return out
+}
+
+func (c *volumeCounter) groupLabels(g *volumeGroup) map[string]string {
+	labels := make(map[string]string, len(c.keys))
+	for i, key := range c.keys {
+		if g.values[i] != "" {
+			labels[key] = g.values[i]
+		}
+	}
+	return labels
+}
+
+// formatGroup renders group labels in --group-by order.
+func formatGroup(keys []string, labels map[string]string) string {
+	var parts []string
+	for _, key := range keys {
+		if v, ok := labels[key]; ok {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	if len(parts) == 0 {
+		return "(all)"
+	}
+	return strings.Join(parts, ",")
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@,+-]+$`)
+
+// shellQuote quotes s for a POSIX shell when it contains special characters.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// drillCommand builds a `lakerunner logs get` command line for the entries
+// behind an anomaly: the original filters narrowed to its group and time range.
+func drillCommand(f *queryFlags, filters []string, labels map[string]string, start, end time.Time) string {
+	args := []string{"lakerunner", "logs", "get"}
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, flag, shellQuote(value))
+		}
+	}
+	app, level := f.appName, f.logLevel
+	var groupFilters []string
+	for key, v := range labels {
+		switch key {
+		case "service":
+			app = v
+		case "level":
+			level = v
+		default:
+			groupFilters = append(groupFilters, key+":"+v)
+		}
+	}
+	slices.Sort(groupFilters)
+	add("-a", app)
+	add("-l", level)
+	for _, filter := range append(slices.Clone(filters), groupFilters...) {
+		add("-f", filter)
+	}
+	add("-M", f.messageContains)
+	add("-N", f.messageNotContains)
+	add("-R", f.messageRegexMatch)
+	add("-X", f.messageRegexNot)
+	add("-s", start.UTC().Format(time.RFC3339))
+	add("-e", end.UTC().Format(time.RFC3339))
+	return strings.Join(args, " ")
+}
+
+func runAnomaliesCmd(cmdObj *cobra.Command, _ []string) error {
+	anomaliesOutput = strings.ToLower(anomaliesOutput)
+	if anomaliesOutput != "text" && anomaliesOutput != "json" {
+		return fmt.Errorf("invalid output format %q: must be one of text, json", anomaliesOutput)
+	}
+	method, err := anomaly.ParseMethod(anomaliesMethod)
+	if err != nil {
+		return err
+	}
+	if anomaliesBucket <= 0 {
+		return fmt.Errorf("invalid --bucket %s: must be positive", anomaliesBucket)
+	}
+
+	client, _, err := newClientFromFlags(cmdObj)
+	if err != nil {
+		return err
+	}
+	start, end, err := anomaliesQuery.times.resolve()
+	if err != nil {
+		return err
+	}
+	filters, err := anomaliesQuery.resolveFilters()
+	if err != nil {
+		return err
+	}
+	q, err := anomaliesQuery.buildQuery()
+	if err != nil {
+		return err
+	}
+
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+	counter := newVolumeCounter(startMs, endMs, anomaliesBucket, anomaliesGroupBy)
+	if counter.n < 3 {
+		return fmt.Errorf("window holds only %d buckets of %s; use a longer --start or a smaller --bucket", counter.n, anomaliesBucket)
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if anomaliesOutput == "json" {
+		quiet = true
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Counting %s buckets for %s from %s...\n", anomaliesBucket, q, timerange.Describe(start, end, anomaliesQuery.times.location()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	responseChan, err := client.QueryLogs(ctx, q, fmt.Sprintf("%d", startMs), fmt.Sprintf("%d", endMs), anomaliesLimit, true, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query logs: %w", err)
+	}
+	var scanned int
+	for response := range responseChan {
+		tags, _ := response.Data["tags"].(map[string]any)
+		if tags == nil {
+			continue
+		}
+		counter.add(response.Data, tags)
+		scanned++
+		if scanned >= anomaliesLimit {
+			cancel()
+			break
+		}
+	}
+	if !quiet && scanned >= anomaliesLimit {
+		fmt.Fprintf(os.Stderr, "Warning: hit --limit %d; older buckets are incomplete and may show as drops\n", anomaliesLimit)
+	}
+
+	found := counter.detect(method, anomaliesThreshold, anomaliesMinCount)
+	if anomaliesTop > 0 && len(found) > anomaliesTop {
+		found = found[:anomaliesTop]
+	}
+	for i := range found {
+		found[i].Command = drillCommand(&anomaliesQuery, filters, found[i].Group, found[i].Start, found[i].End)
+	}
+
+	if anomaliesOutput == "json" {
+		if found == nil {
+			found = []volumeAnomaly{}
+		}
+		data, err := json.MarshalIndent(found, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal anomalies: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("No anomalies found in %d entries\n", scanned)
+		return nil
+	}
+	noColor, _ := cmdObj.Flags().GetBool("no-color")
+	printAnomalies(found, anomaliesGroupBy, anomaliesQuery.times.location(), noColor)
+	return nil
+}
+
+// printAnomalies writes the ranked text report, each row followed by its drill-in command.
+func printAnomalies(found []volumeAnomaly, keys []string, loc *time.Location, noColor bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "#\tSCORE\tTYPE\tSTART\tEND\tPEAK\tBASELINE\tGROUP")
+	for i, a := range found {
+		// Colour only the last column so escapes do not skew the alignment.
+		group := formatGroup(keys, a.Group)
+		if !noColor {
+			color := colorRed
+			if a.Direction == "drop" {
+				color = colorBlue
+			}
+			group = color + group + colorReset
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%+.1f\t%s\t%s\t%s\t%d\t%.1f\t%s\n",
+			i+1, a.Score, a.Direction,
+			a.Start.In(loc).Format("2006-01-02 15:04:05"), a.End.In(loc).Format("15:04:05"),
+			a.Peak, a.Baseline, group)
+		_, _ = fmt.Fprintf(w, "\t  %s\n", a.Command)
+	}
+	_ = w.Flush()
+}
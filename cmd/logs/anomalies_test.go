@@ -0,0 +1,85 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/anomaly"
+)
+
+func TestVolumeCounterDetect(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newVolumeCounter(start.UnixMilli(), start.Add(20*time.Minute).UnixMilli(), time.Minute, []string{"service", "level"})
+	if c.n != 20 {
+		t.Fatalf("n = %d, want 20", c.n)
+	}
+	add := func(minute, count int, service, level string) {
+		for j := range count {
+			ts := start.Add(time.Duration(minute)*time.Minute + time.Duration(j)*time.Second)
+			c.add(map[string]any{"timestamp_ns": ts.UnixNano()}, map[string]any{"service": service, "level": level})
+		}
+	}
+	for m := range 20 {
+		add(m, 5, "checkout", "ERROR")
+		add(m, 3, "cart", "INFO")
+	}
+	// Two-minute error spike in checkout.
+	add(12, 40, "checkout", "ERROR")
+	add(13, 30, "checkout", "ERROR")
+	// Outside the window: ignored.
+	c.add(map[string]any{"timestamp_ns": start.Add(-time.Second).UnixNano()}, map[string]any{"service": "cart"})
+
+	found := c.detect(anomaly.MAD, 3.5, 10)
+	if len(found) != 1 {
+		t.Fatalf("detect() found %d anomalies: %+v", len(found), found)
+	}
+	a := found[0]
+	if a.Direction != "spike" || a.Group["service"] != "checkout" || a.Group["level"] != "ERROR" {
+		t.Errorf("anomaly = %+v", a)
+	}
+	if !a.Start.Equal(start.Add(12*time.Minute)) || !a.End.Equal(start.Add(14*time.Minute)) {
+		t.Errorf("range = %v..%v", a.Start, a.End)
+	}
+	if a.Peak != 45 || a.Baseline != 5 {
+		t.Errorf("peak = %d, baseline = %v", a.Peak, a.Baseline)
+	}
+}
+
+func TestDrillCommand(t *testing.T) {
+	f := &queryFlags{appName: "frontend", messageContains: "timed out"}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	got := drillCommand(f, []string{"env:prod"}, map[string]string{"service": "checkout", "level": "ERROR", "k8s_pod_name": "p1"}, start, start.Add(2*time.Minute))
+	want := "lakerunner logs get -a checkout -l ERROR -f env:prod -f k8s_pod_name:p1 -M 'timed out' -s 2026-01-01T12:00:00Z -e 2026-01-01T12:02:00Z"
+	if got != want {
+		t.Errorf("drillCommand() =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":         "plain",
+		"has space":     "'has space'",
+		"it's":          `'it'\''s'`,
+		"a|b":           "'a|b'",
+		"key:value-1.2": "key:value-1.2",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
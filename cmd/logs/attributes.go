@@ -21,22 +21,23 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cardinalhq/oteltools/pkg/dateutils"
 	"github.com/lakerunner/cli/internal/api"
 	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/logql"
 	"github.com/lakerunner/cli/internal/presets"
+	"github.com/lakerunner/cli/internal/timerange"
 	"github.com/spf13/cobra"
 )
 
 var (
-	attributesFilters      []string
-	attributesPreset       string
-	attributesStartTime    string
-	attributesEndTime      string
-	attributesAppName      string
-	attributesLogLevel     string
-	attributesAliasValues  map[string]*string
-	tagValuesAliasValues   map[string]*string
+	attributesFilters     []string
+	attributesPreset      string
+	attributesTimes       timeFlags
+	attributesAppName     string
+	attributesLogLevel    string
+	attributesAliasValues map[string]*string
+	tagValuesAliasValues  map[string]*string
 )
 
 var AttributesCmd = &cobra.Command{
@@ -58,19 +59,20 @@ var TagValuesCmd = &cobra.Command{
 func init() {
 	AttributesCmd.Flags().StringSliceVarP(&attributesFilters, "filter", "f", []string{}, "Filter in format 'key:value' (can be used multiple times)")
 	AttributesCmd.Flags().StringVarP(&attributesPreset, "preset", "p", "", "Use a named filter preset from ~/.lakerunner/config.yaml")
-	AttributesCmd.Flags().StringVarP(&attributesStartTime, "start", "s", "", "Start time (e.g., 'e-1h', '2024-01-01T00:00:00Z')")
-	AttributesCmd.Flags().StringVarP(&attributesEndTime, "end", "e", "", "End time (e.g., 'now', '2024-01-01T23:59:59Z')")
+	attributesTimes.register(AttributesCmd)
 	AttributesCmd.Flags().StringVarP(&attributesAppName, "app", "a", "", "Filter by application/service name")
 	AttributesCmd.Flags().StringVarP(&attributesLogLevel, "level", "l", "", "Filter by log level (e.g., ERROR, INFO, DEBUG, WARN)")
 
 	TagValuesCmd.Flags().StringSliceVarP(&attributesFilters, "filter", "f", []string{}, "Filter in format 'key:value' (can be used multiple times)")
 	TagValuesCmd.Flags().StringVarP(&attributesPreset, "preset", "p", "", "Use a named filter preset from ~/.lakerunner/config.yaml")
-	TagValuesCmd.Flags().StringVarP(&attributesStartTime, "start", "s", "", "Start time (e.g., 'e-1h', '2024-01-01T00:00:00Z')")
-	TagValuesCmd.Flags().StringVarP(&attributesEndTime, "end", "e", "", "End time (e.g., 'now', '2024-01-01T23:59:59Z')")
+	attributesTimes.register(TagValuesCmd)
 	TagValuesCmd.Flags().StringVarP(&attributesAppName, "app", "a", "", "Filter by application/service name")
 	TagValuesCmd.Flags().StringVarP(&attributesLogLevel, "level", "l", "", "Filter by log level (e.g., ERROR, INFO, DEBUG, WARN)")
 	attributesAliasValues = presets.RegisterAliasFlags(AttributesCmd)
 	tagValuesAliasValues = presets.RegisterAliasFlags(TagValuesCmd)
+	registerCompletions(AttributesCmd)
+	registerCompletions(TagValuesCmd)
+	TagValuesCmd.ValidArgsFunction = completeTagNameArg
 }
 
 func runAttributesCmd(cmdObj *cobra.Command, _ []string) error {
@@ -84,13 +86,14 @@ func runAttributesCmd(cmdObj *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 	client := api.NewClient(cfg)
+	enableCache(cmdObj, client)
 
-	startMs, endMs, err := dateutils.ToStartEnd(attributesStartTime, attributesEndTime)
+	start, end, err := attributesTimes.resolve()
 	if err != nil {
-		return fmt.Errorf("failed to parse time range: %w", err)
+		return err
 	}
-	startTimeStr := fmt.Sprintf("%d", startMs)
-	endTimeStr := fmt.Sprintf("%d", endMs)
+	startTimeStr := fmt.Sprintf("%d", start.UnixMilli())
+	endTimeStr := fmt.Sprintf("%d", end.UnixMilli())
 
 	// Assemble filter set from preset + -f flags + alias flags (same rules as `logs get`).
 	allFilters := attributesFilters
@@ -121,7 +124,7 @@ func runAttributesCmd(cmdObj *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to query tags: %w", err)
 	}
 
-	fmt.Printf("Querying tags from %s to %s", startTimeStr, endTimeStr)
+	fmt.Printf("Querying tags from %s", timerange.Describe(start, end, attributesTimes.location()))
 	if q != "" {
 		fmt.Printf(" with query %s", q)
 	}
@@ -168,15 +171,16 @@ func runTagValuesCmd(cmdObj *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 	client := api.NewClient(cfg)
+	enableCache(cmdObj, client)
 
-	tagName := normalizeTag(args[0])
+	tagName := fieldnames.Label(args[0])
 
-	startMs, endMs, err := dateutils.ToStartEnd(attributesStartTime, attributesEndTime)
+	start, end, err := attributesTimes.resolve()
 	if err != nil {
-		return fmt.Errorf("failed to parse time range: %w", err)
+		return err
 	}
-	startTimeStr := fmt.Sprintf("%d", startMs)
-	endTimeStr := fmt.Sprintf("%d", endMs)
+	startTimeStr := fmt.Sprintf("%d", start.UnixMilli())
+	endTimeStr := fmt.Sprintf("%d", end.UnixMilli())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -202,16 +206,14 @@ func runTagValuesCmd(cmdObj *cobra.Command, args []string) error {
 
 	var conditions []string
 	if attributesAppName != "" {
-		conditions = append(conditions, fmt.Sprintf(`service="%s"`, normalizeTag(attributesAppName)))
+		conditions = append(conditions, "service="+logql.Quote(attributesAppName))
 	}
 	if attributesLogLevel != "" {
-		conditions = append(conditions, fmt.Sprintf(`level="%s"`, normalizeTag(attributesLogLevel)))
+		conditions = append(conditions, "level="+logql.Quote(attributesLogLevel))
 	}
 	for _, f := range allFilters {
 		if parts := strings.SplitN(f, ":", 2); len(parts) == 2 {
-			key := normalizeTag(parts[0])
-			val := normalizeTag(parts[1])
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, key, val))
+			conditions = append(conditions, fieldnames.Label(parts[0])+"="+logql.Quote(parts[1]))
 		}
 	}
 
@@ -228,7 +230,7 @@ func runTagValuesCmd(cmdObj *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to query tag values: %w", err)
 	}
 
-	fmt.Printf("Querying values for tag '%s' from %s to %s", tagName, startTimeStr, endTimeStr)
+	fmt.Printf("Querying values for tag '%s' from %s", tagName, timerange.Describe(start, end, attributesTimes.location()))
 	if q != "" {
 		fmt.Printf(" with query %s", q)
 	}
@@ -0,0 +1,223 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/cache"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/presets"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// completionTTL bounds how stale suggested tag names and values can be.
+	completionTTL = 5 * time.Minute
+	// completionTimeout keeps a slow endpoint from hanging the shell.
+	completionTimeout = 5 * time.Second
+)
+
+// defaultLevels are suggested for -l when the endpoint cannot be asked.
+var defaultLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// registerCompletions adds dynamic completion to whichever of the filter
+// flags cmd has.
+func registerCompletions(cmd *cobra.Command) {
+	funcs := map[string]cobra.CompletionFunc{
+		"preset": completePreset,
+		"filter": completeFilter,
+		"app":    completeApp,
+		"level":  completeLevel,
+	}
+	for name, fn := range funcs {
+		if cmd.Flags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+}
+
+// completionValues returns the cached result of fetch, calling it on a miss.
+// Errors are logged to cobra's completion debug file and yield no suggestions.
+func completionValues(cmd *cobra.Command, kind, tag string, fetch func(ctx context.Context, client *api.Client, s, e string) ([]string, error)) []string {
+	client, cfg, err := newClientFromFlags(cmd)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), false)
+		return nil
+	}
+	var c *cache.Cache
+	key := cache.Key(cfg.LAKERUNNER_QUERY_URL, kind, tag)
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	if dir, err := cache.DefaultDir("completion"); err == nil && !noCache {
+		c = cache.New(dir, completionTTL)
+		var values []string
+		if c.Get(key, &values) {
+			return values
+		}
+	}
+
+	start, end, err := timerange.Spec{}.Resolve()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), false)
+		return nil
+	}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	values, err := fetch(ctx, client, fmt.Sprintf("%d", startMs), fmt.Sprintf("%d", endMs))
+	if err != nil {
+		cobra.CompDebugln(err.Error(), false)
+		return nil
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+	if c != nil {
+		if err := c.Set(key, values); err != nil {
+			cobra.CompDebugln(err.Error(), false)
+		}
+	}
+	return values
+}
+
+func completionTagNames(cmd *cobra.Command) []string {
+	return completionValues(cmd, "tags", "", func(ctx context.Context, client *api.Client, s, e string) ([]string, error) {
+		responseChan, err := client.QueryLogTags(ctx, "", s, e)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for response := range responseChan {
+			tags, _ := response.Data["tags"].([]string)
+			for _, name := range tags {
+				if !strings.HasPrefix(name, "_cardinalhq") {
+					names = append(names, name)
+				}
+			}
+		}
+		return names, nil
+	})
+}
+
+func completionTagValues(cmd *cobra.Command, tag string) []string {
+	return completionValues(cmd, "tagvalues", tag, func(ctx context.Context, client *api.Client, s, e string) ([]string, error) {
+		responseChan, err := client.QueryLogTagValues(ctx, tag, "", s, e)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		for response := range responseChan {
+			if response.Type != "result" {
+				continue
+			}
+			if v, ok := response.Data["value"].(string); ok && v != "" {
+				values = append(values, v)
+			}
+		}
+		return values, nil
+	})
+}
+
+// withPrefix keeps the candidates starting with toComplete, each prefixed by
+// keep (the part of the word that is already settled).
+func withPrefix(keep, toComplete string, candidates []string) []string {
+	var out []string
+	for _, c := range candidates {
+		if s := keep + c; strings.HasPrefix(s, toComplete) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func completePreset(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := presets.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, name := range slices.Sorted(maps.Keys(cfg.Presets)) {
+		if strings.HasPrefix(name, toComplete) {
+			out = append(out, name+"\t"+strings.Join(cfg.Presets[name], ", "))
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFilter suggests "tag:" for -f, then the tag's values once the
+// colon has been typed. Alias names are accepted as keys.
+func completeFilter(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var aliases map[string]string
+	if cfg, err := presets.Load(); err == nil {
+		aliases = cfg.Aliases
+	}
+
+	if key, _, ok := strings.Cut(toComplete, ":"); ok {
+		tag := fieldnames.Label(key)
+		if full, ok := aliases[key]; ok {
+			tag = fieldnames.Label(full)
+		}
+		return withPrefix(key+":", toComplete, completionTagValues(cmd, tag)), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var out []string
+	for _, name := range completionTagNames(cmd) {
+		if strings.HasPrefix(name, toComplete) {
+			out = append(out, name+":")
+		}
+	}
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		if strings.HasPrefix(alias, toComplete) {
+			out = append(out, alias+":\talias for "+aliases[alias])
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeApp suggests service names; -a takes a comma-separated list, so only
+// the last element is completed.
+func completeApp(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	keep := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		keep = toComplete[:i+1]
+	}
+	chosen := strings.Split(keep, ",")
+	services := slices.DeleteFunc(completionTagValues(cmd, "service"), func(s string) bool {
+		return slices.Contains(chosen, s)
+	})
+	return withPrefix(keep, toComplete, services), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeLevel(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	levels := completionTagValues(cmd, "level")
+	if len(levels) == 0 {
+		levels = defaultLevels
+	}
+	return withPrefix("", toComplete, levels), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTagNameArg completes the tag argument of get-values.
+func completeTagNameArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return withPrefix("", toComplete, completionTagNames(cmd)), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,116 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// completionServer serves tag names and values, counting requests.
+func completionServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	values := map[string][]string{
+		"service": {"checkout", "api", "cart"},
+		"level":   {"ERROR", "INFO"},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/api/v1/logs/tags" {
+			_, _ = fmt.Fprint(w, `{"tags":["service","level","k8s_pod_name","_cardinalhq_fingerprint"]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, v := range values[r.URL.Query().Get("tagName")] {
+			_, _ = fmt.Fprintf(w, "data: {\"type\":\"result\",\"data\":{\"value\":%q}}\n\n", v)
+		}
+		_, _ = fmt.Fprint(w, "data: {\"type\":\"done\"}\n\n")
+	}))
+}
+
+func TestCompletion(t *testing.T) {
+	var requests atomic.Int32
+	srv := completionServer(t, &requests)
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("LAKERUNNER_QUERY_URL", srv.URL)
+	t.Setenv("LAKERUNNER_API_KEY", "test")
+	t.Setenv("LAKERUNNER_CONFIG", "")
+	t.Chdir(home)
+	if err := os.MkdirAll(filepath.Join(home, ".lakerunner"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "presets:\n  payments: [service:payments]\naliases:\n  svc: service\n"
+	if err := os.WriteFile(filepath.Join(home, ".lakerunner", "config.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	tests := []struct {
+		name       string
+		fn         cobra.CompletionFunc
+		toComplete string
+		want       []string
+	}{
+		{"filter keys", completeFilter, "", []string{"k8s_pod_name:", "level:", "service:", "svc:\talias for service"}},
+		{"filter key prefix", completeFilter, "le", []string{"level:"}},
+		{"filter values", completeFilter, "service:c", []string{"service:cart", "service:checkout"}},
+		{"filter alias values", completeFilter, "svc:a", []string{"svc:api"}},
+		{"app", completeApp, "", []string{"api", "cart", "checkout"}},
+		{"app list", completeApp, "api,c", []string{"api,cart", "api,checkout"}},
+		{"level", completeLevel, "E", []string{"ERROR"}},
+		{"preset", completePreset, "pay", []string{"payments\tservice:payments"}},
+		{"tag argument", completeTagNameArg, "s", []string{"service"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := tt.fn(cmd, nil, tt.toComplete)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("completion of %q = %q, want %q", tt.toComplete, got, tt.want)
+			}
+		})
+	}
+
+	// One tags request and one tagvalues request per tag; the rest are cached.
+	if n := requests.Load(); n != 3 {
+		t.Errorf("server saw %d requests, want 3", n)
+	}
+}
+
+func TestCompletionLevelFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("LAKERUNNER_QUERY_URL", "")
+	t.Setenv("LAKERUNNER_API_KEY", "")
+
+	got, _ := completeLevel(&cobra.Command{Use: "test"}, nil, "")
+	if !slices.Equal(got, defaultLevels) {
+		t.Errorf("completeLevel without endpoint = %q, want %q", got, defaultLevels)
+	}
+	if got, _ := completeFilter(&cobra.Command{Use: "test"}, nil, "service:"); len(got) != 0 {
+		t.Errorf("completeFilter without endpoint = %q, want none", strings.Join(got, ","))
+	}
+}
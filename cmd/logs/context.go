@@ -0,0 +1,217 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/fieldnames"
+)
+
+const (
+	contextSeparator = "--"
+	matchMarker      = "> "
+	contextMarker    = "  "
+
+	// maxContextMatches caps the matches that get their neighbours fetched;
+	// each costs up to two queries.
+	maxContextMatches = 50
+)
+
+// contextOptions holds the grep-style -A/-B/-C flags of `logs get`.
+type contextOptions struct {
+	before int
+	after  int
+	around int
+	keys   []string
+	window time.Duration
+}
+
+// resolve applies --context as the default for --before/--after.
+func (o contextOptions) resolve(beforeSet, afterSet bool) contextOptions {
+	if !beforeSet {
+		o.before = o.around
+	}
+	if !afterSet {
+		o.after = o.around
+	}
+	return o
+}
+
+func (o contextOptions) enabled() bool {
+	return o.before > 0 || o.after > 0
+}
+
+// entryTimestampNs returns the entry timestamp in nanoseconds.
+func entryTimestampNs(message map[string]any) (int64, bool) {
+	if tsns, ok := message["timestamp_ns"].(int64); ok {
+		return tsns, true
+	}
+	if ts, ok := message["timestamp"].(int64); ok {
+		return ts * int64(time.Millisecond), true
+	}
+	if ts, ok := message["timestamp"].(float64); ok {
+		return int64(ts) * int64(time.Millisecond), true
+	}
+	return 0, false
+}
+
+// entryIdentity identifies an entry well enough to drop the match itself and
+// duplicates from the neighbouring results.
+func entryIdentity(message map[string]any) string {
+	ts, _ := entryTimestampNs(message)
+	tags, _ := message["tags"].(map[string]any)
+	return fmt.Sprintf("%d\x00%v\x00%v", ts, tags["message"], tags["k8s_pod_name"])
+}
+
+// streamFilters returns key:value filters selecting the stream an entry came
+// from. Keys missing from the entry are skipped.
+func streamFilters(tags map[string]any, keys []string) []string {
+	var out []string
+	for _, k := range keys {
+		if v, _, ok := fieldnames.Lookup(tags, k); ok && fmt.Sprintf("%v", v) != "" {
+			out = append(out, fmt.Sprintf("%s:%v", fieldnames.Label(k), v))
+		}
+	}
+	return out
+}
+
+// contextGroup is a match with the entries its stream logged just before and after it.
+type contextGroup struct {
+	before []api.LogsResponse
+	match  api.LogsResponse
+	after  []api.LogsResponse
+}
+
+// fetchContext queries the match's stream for up to opts.before entries
+// preceding it and opts.after entries following it, within opts.window.
+func fetchContext(ctx context.Context, client *api.Client, match api.LogsResponse, opts contextOptions, fields []string) (contextGroup, error) {
+	group := contextGroup{match: match}
+	tsNs, ok := entryTimestampNs(match.Data)
+	if !ok {
+		return group, nil
+	}
+	tags, _ := match.Data["tags"].(map[string]any)
+	q := buildLogQLQuery("", "", streamFilters(tags, opts.keys), "", "", "", "")
+	tsMs := tsNs / int64(time.Millisecond)
+	windowMs := opts.window.Milliseconds()
+	seen := map[string]bool{entryIdentity(match.Data): true}
+
+	query := func(start, end int64, n int, reverse bool, keep func(ns int64) bool) ([]api.LogsResponse, error) {
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+		// +1 leaves room for the match itself, which falls inside both windows.
+		ch, err := client.QueryLogs(ctx, q, fmt.Sprintf("%d", start), fmt.Sprintf("%d", end), n+1, reverse, fields)
+		if err != nil {
+			return nil, err
+		}
+		var out []api.LogsResponse
+		for r := range ch {
+			id := entryIdentity(r.Data)
+			if ns, ok := entryTimestampNs(r.Data); seen[id] || ok && !keep(ns) {
+				continue
+			}
+			seen[id] = true
+			if len(out) < n {
+				out = append(out, r)
+			}
+		}
+		return out, nil
+	}
+
+	if opts.before > 0 {
+		before, err := query(tsMs-windowMs, tsMs+1, opts.before, true, func(ns int64) bool { return ns <= tsNs })
+		if err != nil {
+			return group, fmt.Errorf("failed to fetch context before match: %w", err)
+		}
+		// Fetched newest first; print oldest first.
+		slices.Reverse(before)
+		group.before = before
+	}
+	if opts.after > 0 {
+		after, err := query(tsMs, tsMs+windowMs, opts.after, false, func(ns int64) bool { return ns >= tsNs })
+		if err != nil {
+			return group, fmt.Errorf("failed to fetch context after match: %w", err)
+		}
+		group.after = after
+	}
+	return group, nil
+}
+
+// contextLine is one line of a context block.
+type contextLine struct {
+	entry api.LogsResponse
+	match bool
+}
+
+// mergeContextGroups joins groups whose lines overlap into one block, the way
+// grep prints overlapping context once and without a separator. Lines within
+// a block are in time order; a line that is both context and a match is
+// printed as a match.
+func mergeContextGroups(groups []contextGroup) [][]contextLine {
+	var blocks [][]contextLine
+	var index map[string]int
+	for _, group := range groups {
+		lines := make([]contextLine, 0, len(group.before)+1+len(group.after))
+		for _, entry := range group.before {
+			lines = append(lines, contextLine{entry: entry})
+		}
+		lines = append(lines, contextLine{entry: group.match, match: true})
+		for _, entry := range group.after {
+			lines = append(lines, contextLine{entry: entry})
+		}
+
+		overlaps := slices.ContainsFunc(lines, func(l contextLine) bool {
+			_, ok := index[entryIdentity(l.entry.Data)]
+			return ok
+		})
+		if !overlaps {
+			blocks = append(blocks, nil)
+			index = make(map[string]int)
+		}
+		block := blocks[len(blocks)-1]
+		for _, l := range lines {
+			id := entryIdentity(l.entry.Data)
+			if i, ok := index[id]; ok {
+				block[i].match = block[i].match || l.match
+				continue
+			}
+			index[id] = len(block)
+			block = append(block, l)
+		}
+		blocks[len(blocks)-1] = block
+	}
+	for _, block := range blocks {
+		slices.SortStableFunc(block, func(a, b contextLine) int {
+			ta, _ := entryTimestampNs(a.entry.Data)
+			tb, _ := entryTimestampNs(b.entry.Data)
+			return cmp.Compare(ta, tb)
+		})
+	}
+	return blocks
+}
+
+// formatMatchMarker returns the line prefix for a match, coloured unless noColor.
+func formatMatchMarker(noColor bool) string {
+	if noColor {
+		return matchMarker
+	}
+	return colorRed + matchMarker + colorReset
+}
@@ -0,0 +1,173 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/config"
+)
+
+func TestContextOptionsResolve(t *testing.T) {
+	o := contextOptions{around: 3}.resolve(false, false)
+	if o.before != 3 || o.after != 3 {
+		t.Errorf("--context 3 = before %d after %d, want 3/3", o.before, o.after)
+	}
+	o = contextOptions{before: 1, around: 3}.resolve(true, false)
+	if o.before != 1 || o.after != 3 {
+		t.Errorf("-B 1 -C 3 = before %d after %d, want 1/3", o.before, o.after)
+	}
+	if (contextOptions{}).resolve(false, false).enabled() {
+		t.Error("no context flags should not enable context")
+	}
+}
+
+func TestStreamFilters(t *testing.T) {
+	tags := mockLogEntries[0].tags
+	got := streamFilters(tags, []string{"service", "k8s.pod.name", "missing"})
+	want := []string{"service:cartservice", "k8s_pod_name:otel-demo-cartservice-744fc69cf7-bmm9z"}
+	if !slices.Equal(got, want) {
+		t.Errorf("streamFilters() = %v, want %v", got, want)
+	}
+}
+
+// contextServer serves a single stream of entries one second apart, honouring
+// the start/end, limit and reverse fields of the query body.
+func contextServer(t *testing.T, base time.Time, n int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			S       string `json:"s"`
+			E       string `json:"e"`
+			Limit   int    `json:"limit"`
+			Reverse bool   `json:"reverse"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		s, _ := strconv.ParseInt(body.S, 10, 64)
+		e, _ := strconv.ParseInt(body.E, 10, 64)
+		var idx []int
+		for i := range n {
+			ts := base.Add(time.Duration(i) * time.Second).UnixMilli()
+			if ts >= s && ts < e {
+				idx = append(idx, i)
+			}
+		}
+		if body.Reverse {
+			slices.Reverse(idx)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for j, i := range idx {
+			if j >= body.Limit {
+				break
+			}
+			ts := base.Add(time.Duration(i) * time.Second)
+			_, _ = fmt.Fprintf(w, "data: {\"type\":\"event\",\"data\":{\"timestamp\":%d,\"timestamp_ns\":%d,\"tags\":{\"service\":\"cart\",\"message\":\"line %d\"}}}\n\n",
+				ts.UnixMilli(), ts.UnixNano(), i)
+		}
+		_, _ = fmt.Fprint(w, "data: {\"type\":\"done\"}\n\n")
+	}))
+}
+
+func TestFetchContext(t *testing.T) {
+	base := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	srv := contextServer(t, base, 10)
+	defer srv.Close()
+	client := api.NewClient(&config.Config{LAKERUNNER_QUERY_URL: srv.URL, LAKERUNNER_API_KEY: "test"})
+
+	matchTs := base.Add(5 * time.Second)
+	match := api.LogsResponse{Data: map[string]any{
+		"timestamp":    matchTs.UnixMilli(),
+		"timestamp_ns": matchTs.UnixNano(),
+		"tags":         map[string]any{"service": "cart", "message": "line 5"},
+	}}
+
+	opts := contextOptions{before: 2, after: 3, keys: []string{"service"}, window: time.Minute}
+	group, err := fetchContext(context.Background(), client, match, opts, nil)
+	if err != nil {
+		t.Fatalf("fetchContext() error: %v", err)
+	}
+
+	messages := func(entries []api.LogsResponse) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Data["tags"].(map[string]any)["message"].(string))
+		}
+		return out
+	}
+	if got, want := messages(group.before), []string{"line 3", "line 4"}; !slices.Equal(got, want) {
+		t.Errorf("before = %v, want %v", got, want)
+	}
+	if got, want := messages(group.after), []string{"line 6", "line 7", "line 8"}; !slices.Equal(got, want) {
+		t.Errorf("after = %v, want %v", got, want)
+	}
+}
+
+func TestMergeContextGroups(t *testing.T) {
+	base := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	entry := func(i int) api.LogsResponse {
+		ts := base.Add(time.Duration(i) * time.Second)
+		return api.LogsResponse{Data: map[string]any{
+			"timestamp_ns": ts.UnixNano(),
+			"tags":         map[string]any{"message": fmt.Sprintf("line %d", i)},
+		}}
+	}
+	group := func(before []int, match int, after []int) contextGroup {
+		g := contextGroup{match: entry(match)}
+		for _, i := range before {
+			g.before = append(g.before, entry(i))
+		}
+		for _, i := range after {
+			g.after = append(g.after, entry(i))
+		}
+		return g
+	}
+	// Newest first, as `logs get` streams by default: 6's context reaches 5,
+	// which is itself a match, and 1 stands apart.
+	groups := []contextGroup{
+		group([]int{4, 5}, 6, []int{7}),
+		group([]int{3, 4}, 5, []int{6}),
+		group(nil, 1, []int{2}),
+	}
+	var got []string
+	for _, block := range mergeContextGroups(groups) {
+		var lines []string
+		for _, l := range block {
+			line := l.entry.Data["tags"].(map[string]any)["message"].(string)
+			if l.match {
+				line = "> " + line
+			}
+			lines = append(lines, line)
+		}
+		got = append(got, strings.Join(lines, ", "))
+	}
+	want := []string{
+		"line 3, line 4, > line 5, > line 6, line 7",
+		"> line 1, line 2",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("mergeContextGroups() = %q, want %q", got, want)
+	}
+}
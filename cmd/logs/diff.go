@@ -0,0 +1,457 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/patterns"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffQuery     queryFlags
+	diffBaseline  string
+	diffTarget    string
+	diffLimit     int
+	diffTags      []string
+	diffMinChange float64
+	diffMinCount  int64
+	diffOutput    string
+)
+
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare log patterns, services and tag values between two time windows",
+	Long: `Run the same filters over a baseline and a target window and report the
+message patterns, services and tag values that appeared, disappeared or
+changed volume significantly. Volumes are compared as rates, so the windows
+may differ in length. Each window's messages are clustered on their own and
+the templates paired afterwards, so a message only the target logged is
+reported as new even when it resembles a baseline pattern.
+
+Windows are written START..END using the same syntax as --start/--end:
+
+  lakerunner logs diff --baseline e-2h..e-1h --target e-1h..now -a checkout`,
+	RunE: runDiffCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	diffQuery.registerFilters(DiffCmd)
+	DiffCmd.Flags().StringVar(&diffBaseline, "baseline", "e-2h..e-1h", "Baseline window as START..END")
+	DiffCmd.Flags().StringVar(&diffTarget, "target", "e-1h..now", "Target window as START..END")
+	DiffCmd.Flags().IntVar(&diffLimit, "limit", 10000, "Maximum number of log entries to scan per window")
+	DiffCmd.Flags().StringSliceVar(&diffTags, "tags", []string{"level"}, "Tag keys whose values are compared (services are always compared)")
+	DiffCmd.Flags().Float64Var(&diffMinChange, "min-change", 2, "Minimum rate ratio (either direction) reported as a volume change")
+	DiffCmd.Flags().Int64Var(&diffMinCount, "min-count", 5, "Ignore items seen fewer than N times in both windows")
+	DiffCmd.Flags().StringVarP(&diffOutput, "output", "o", "text", "Output format: text, json")
+	diffQuery.registerAliases(DiffCmd)
+}
+
+// Diff kinds and change labels.
+const (
+	diffKindPattern = "pattern"
+	diffKindService = "service"
+	diffKindTag     = "tag"
+
+	changeNew  = "new"
+	changeGone = "gone"
+	changeUp   = "up"
+	changeDown = "down"
+)
+
+// diffWindow is a parsed START..END range.
+type diffWindow struct {
+	startMs, endMs int64
+}
+
+func (w diffWindow) duration() time.Duration {
+	return time.Duration(w.endMs-w.startMs) * time.Millisecond
+}
+
+// parseDiffWindow parses "START..END"; either side may be empty to use the
+// --start/--end defaults. When both sides are relative to "e" there is no end
+// time to count from, so both count from now: e-2h..e-1h is the hour that
+// ended an hour ago.
+func parseDiffWindow(spec string) (diffWindow, error) {
+	start, end, ok := strings.Cut(spec, "..")
+	if !ok {
+		return diffWindow{}, fmt.Errorf("invalid window %q: expected START..END", spec)
+	}
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if isEndRelative(end) && isEndRelative(start) {
+		start, end = "now"+start[1:], "now"+end[1:]
+	}
+	startTime, endTime, err := timerange.Spec{Start: start, End: end}.Resolve()
+	if err != nil {
+		return diffWindow{}, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	if !endTime.After(startTime) {
+		return diffWindow{}, fmt.Errorf("invalid window %q: end must be after start", spec)
+	}
+	return diffWindow{startMs: startTime.UnixMilli(), endMs: endTime.UnixMilli()}, nil
+}
+
+// isEndRelative reports whether s is an offset from the end time, like e-1h.
+func isEndRelative(s string) bool {
+	return strings.HasPrefix(s, "e-") || strings.HasPrefix(s, "e+")
+}
+
+// windowTally is what was observed in one window.
+type windowTally struct {
+	patterns  *patternCollector
+	services  map[string]int64
+	tags      map[string]map[string]int64
+	truncated bool
+}
+
+// newWindowTally starts a tally with its own pattern miner, so messages of
+// one window never generalise the templates of the other.
+func newWindowTally() *windowTally {
+	return &windowTally{
+		patterns: newPatternCollector(patterns.NewMiner(patterns.DefaultThreshold)),
+		services: make(map[string]int64),
+		tags:     make(map[string]map[string]int64),
+	}
+}
+
+// add records one entry, counting values for the given tag keys.
+func (t *windowTally) add(message, tags map[string]any, tagKeys []string) {
+	t.patterns.add(message, tags)
+	if svc, ok := tags["service"].(string); ok && svc != "" {
+		t.services[svc]++
+	}
+	for _, key := range tagKeys {
+		v, _, ok := fieldnames.Lookup(tags, key)
+		if !ok {
+			continue
+		}
+		if t.tags[key] == nil {
+			t.tags[key] = make(map[string]int64)
+		}
+		t.tags[key][fmt.Sprint(v)]++
+	}
+}
+
+// sortedPatterns returns the tally's patterns in creation order.
+func (t *windowTally) sortedPatterns() []*patterns.Pattern {
+	out := make([]*patterns.Pattern, 0, len(t.patterns.stats))
+	for p := range t.patterns.stats {
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b *patterns.Pattern) int { return a.ID - b.ID })
+	return out
+}
+
+// templatesCompatible reports whether two templates of the same length agree
+// at every position, a wildcard on either side matching anything.
+func templatesCompatible(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && a[i] != patterns.Wildcard && b[i] != patterns.Wildcard {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPatterns returns per-template counts for both windows once each
+// target pattern is paired with the baseline pattern it is compatible with,
+// preferring the one sharing the most literal tokens. A paired template is
+// reported generalised over both windows; a target pattern with no partner is
+// new, however close it comes to a baseline one.
+func matchPatterns(base, target *windowTally) (baseCounts, targetCounts map[string]int64) {
+	basePatterns := base.sortedPatterns()
+	merged := make(map[*patterns.Pattern][]string, len(basePatterns))
+	for _, b := range basePatterns {
+		merged[b] = slices.Clone(b.Tokens)
+	}
+	partner := make(map[*patterns.Pattern]*patterns.Pattern)
+	for _, t := range target.sortedPatterns() {
+		var best *patterns.Pattern
+		bestSame := -1
+		for _, b := range basePatterns {
+			if !templatesCompatible(b.Tokens, t.Tokens) {
+				continue
+			}
+			same := 0
+			for i := range b.Tokens {
+				if b.Tokens[i] == t.Tokens[i] {
+					same++
+				}
+			}
+			if same > bestSame {
+				best, bestSame = b, same
+			}
+		}
+		if best == nil {
+			continue
+		}
+		partner[t] = best
+		for i, tok := range merged[best] {
+			if tok != t.Tokens[i] {
+				merged[best][i] = patterns.Wildcard
+			}
+		}
+	}
+
+	baseCounts = make(map[string]int64, len(base.patterns.stats))
+	for b, st := range base.patterns.stats {
+		baseCounts[strings.Join(merged[b], " ")] += st.count
+	}
+	targetCounts = make(map[string]int64, len(target.patterns.stats))
+	for t, st := range target.patterns.stats {
+		template := t.Template()
+		if b, ok := partner[t]; ok {
+			template = strings.Join(merged[b], " ")
+		}
+		targetCounts[template] += st.count
+	}
+	return baseCounts, targetCounts
+}
+
+// diffEntry is one reported difference.
+type diffEntry struct {
+	Kind     string  `json:"kind"`
+	Key      string  `json:"key,omitempty"`
+	Value    string  `json:"value"`
+	Change   string  `json:"change"`
+	Baseline int64   `json:"baseline"`
+	Target   int64   `json:"target"`
+	Ratio    float64 `json:"ratio,omitempty"`
+}
+
+// diffCounts compares per-value counts from two windows. Counts are turned
+// into rates over each window's duration before computing the ratio.
+func diffCounts(kind, key string, base, target map[string]int64, baseDur, targetDur time.Duration, minChange float64, minCount int64) []diffEntry {
+	values := make(map[string]struct{}, len(base)+len(target))
+	for v := range base {
+		values[v] = struct{}{}
+	}
+	for v := range target {
+		values[v] = struct{}{}
+	}
+
+	var out []diffEntry
+	for v := range values {
+		b, t := base[v], target[v]
+		if b < minCount && t < minCount {
+			continue
+		}
+		e := diffEntry{Kind: kind, Key: key, Value: v, Baseline: b, Target: t}
+		switch {
+		case b == 0:
+			e.Change = changeNew
+		case t == 0:
+			e.Change = changeGone
+		default:
+			e.Ratio = float64(t) * baseDur.Seconds() / (float64(b) * targetDur.Seconds())
+			switch {
+			case e.Ratio >= minChange:
+				e.Change = changeUp
+			case e.Ratio <= 1/minChange:
+				e.Change = changeDown
+			default:
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+	slices.SortFunc(out, compareDiffEntries)
+	return out
+}
+
+var changeOrder = map[string]int{changeNew: 0, changeGone: 1, changeUp: 2, changeDown: 3}
+
+// compareDiffEntries orders by change kind, then by the larger count, then value.
+func compareDiffEntries(a, b diffEntry) int {
+	if a.Change != b.Change {
+		return changeOrder[a.Change] - changeOrder[b.Change]
+	}
+	am, bm := max(a.Baseline, a.Target), max(b.Baseline, b.Target)
+	if am != bm {
+		if am > bm {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.Value, b.Value)
+}
+
+// fetchWindow streams up to limit entries of one window into a tally.
+func fetchWindow(ctx context.Context, client *api.Client, q string, w diffWindow, limit int, tagKeys []string, tally *windowTally) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responseChan, err := client.QueryLogs(ctx, q, fmt.Sprintf("%d", w.startMs), fmt.Sprintf("%d", w.endMs), limit, true, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query logs: %w", err)
+	}
+	var n int
+	for response := range responseChan {
+		tags, _ := response.Data["tags"].(map[string]any)
+		if tags == nil {
+			continue
+		}
+		tally.add(response.Data, tags, tagKeys)
+		n++
+		if n >= limit {
+			tally.truncated = true
+			break
+		}
+	}
+	return nil
+}
+
+func runDiffCmd(cmdObj *cobra.Command, _ []string) error {
+	diffOutput = strings.ToLower(diffOutput)
+	if diffOutput != "text" && diffOutput != "json" {
+		return fmt.Errorf("invalid output format %q: must be one of text, json", diffOutput)
+	}
+	if diffMinChange <= 1 {
+		return fmt.Errorf("invalid --min-change %v: must be greater than 1", diffMinChange)
+	}
+	baseline, err := parseDiffWindow(diffBaseline)
+	if err != nil {
+		return fmt.Errorf("failed to parse --baseline: %w", err)
+	}
+	target, err := parseDiffWindow(diffTarget)
+	if err != nil {
+		return fmt.Errorf("failed to parse --target: %w", err)
+	}
+
+	client, _, err := newClientFromFlags(cmdObj)
+	if err != nil {
+		return err
+	}
+	q, err := diffQuery.buildQuery()
+	if err != nil {
+		return err
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if diffOutput == "json" {
+		quiet = true
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Comparing %s against %s for %s...\n", diffTarget, diffBaseline, q)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	baseTally, targetTally := newWindowTally(), newWindowTally()
+	if err := fetchWindow(ctx, client, q, baseline, diffLimit, diffTags, baseTally); err != nil {
+		return err
+	}
+	if err := fetchWindow(ctx, client, q, target, diffLimit, diffTags, targetTally); err != nil {
+		return err
+	}
+	if !quiet && (baseTally.truncated || targetTally.truncated) {
+		fmt.Fprintf(os.Stderr, "Warning: a window hit --limit %d; volume changes may be understated\n", diffLimit)
+	}
+
+	bd, td := baseline.duration(), target.duration()
+	basePatterns, targetPatterns := matchPatterns(baseTally, targetTally)
+	var entries []diffEntry
+	entries = append(entries, diffCounts(diffKindPattern, "", basePatterns, targetPatterns, bd, td, diffMinChange, diffMinCount)...)
+	entries = append(entries, diffCounts(diffKindService, "", baseTally.services, targetTally.services, bd, td, diffMinChange, diffMinCount)...)
+	for _, key := range diffTags {
+		entries = append(entries, diffCounts(diffKindTag, key, baseTally.tags[key], targetTally.tags[key], bd, td, diffMinChange, diffMinCount)...)
+	}
+
+	if diffOutput == "json" {
+		if entries == nil {
+			entries = []diffEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No significant differences between the windows")
+		return nil
+	}
+	noColor, _ := cmdObj.Flags().GetBool("no-color")
+	printDiff(entries, noColor)
+	return nil
+}
+
+// formatDiffChange renders the change column, e.g. "+ new" or "▲ x3.2".
+func formatDiffChange(e diffEntry, noColor bool) string {
+	var label, color string
+	switch e.Change {
+	case changeNew:
+		label, color = "+ new", colorGreen
+	case changeGone:
+		label, color = "- gone", colorRed
+	case changeUp:
+		label, color = fmt.Sprintf("▲ x%.1f", e.Ratio), colorYellow
+	case changeDown:
+		label, color = fmt.Sprintf("▼ x%.2f", e.Ratio), colorBlue
+	}
+	label = fmt.Sprintf("%-8s", label)
+	if noColor {
+		return label
+	}
+	return color + label + colorReset
+}
+
+// printDiff writes the text report grouped into patterns, services and tags.
+func printDiff(entries []diffEntry, noColor bool) {
+	sections := []struct {
+		kind, title string
+	}{
+		{diffKindPattern, "Patterns"},
+		{diffKindService, "Services"},
+		{diffKindTag, "Tag values"},
+	}
+	for _, sec := range sections {
+		first := true
+		for _, e := range entries {
+			if e.Kind != sec.kind {
+				continue
+			}
+			if first {
+				fmt.Printf("%s:\n", sec.title)
+				first = false
+			}
+			value := e.Value
+			if e.Key != "" {
+				value = e.Key + "=" + value
+			}
+			fmt.Printf("  %s %6d → %-6d %s\n", formatDiffChange(e, noColor), e.Baseline, e.Target, value)
+		}
+		if !first {
+			fmt.Println()
+		}
+	}
+}
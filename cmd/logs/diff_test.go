@@ -0,0 +1,115 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestParseDiffWindow(t *testing.T) {
+	w, err := parseDiffWindow("e-2h..e-1h")
+	if err != nil {
+		t.Fatalf("parseDiffWindow() error: %v", err)
+	}
+	if w.duration() != time.Hour {
+		t.Errorf("duration() = %v, want 1h", w.duration())
+	}
+	now := time.Now().UnixMilli()
+	if d := now - 2*time.Hour.Milliseconds() - w.startMs; d < 0 || d > time.Minute.Milliseconds() {
+		t.Errorf("startMs is %dms off now-2h", d)
+	}
+	if w, err = parseDiffWindow("2024-01-01T00:00:00Z..e+30m"); err != nil || w.duration() != 30*time.Minute {
+		t.Errorf("parseDiffWindow(absolute..relative) = %v, %v", w, err)
+	}
+	for _, spec := range []string{"e-2h", "e-1h..e-2h"} {
+		if _, err := parseDiffWindow(spec); err == nil {
+			t.Errorf("parseDiffWindow(%q) expected error", spec)
+		}
+	}
+}
+
+func TestDiffCounts(t *testing.T) {
+	base := map[string]int64{"steady": 100, "gone": 20, "grew": 10, "shrank": 90, "rare": 2}
+	target := map[string]int64{"steady": 110, "new": 15, "grew": 50, "shrank": 10, "rare": 1}
+
+	got := diffCounts(diffKindPattern, "", base, target, time.Hour, time.Hour, 2, 5)
+	want := []struct {
+		value, change string
+	}{
+		{"new", changeNew},
+		{"gone", changeGone},
+		{"grew", changeUp},
+		{"shrank", changeDown},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("diffCounts() returned %d entries: %+v", len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Value != w.value || got[i].Change != w.change {
+			t.Errorf("entry %d = %s/%s, want %s/%s", i, got[i].Value, got[i].Change, w.value, w.change)
+		}
+	}
+	if got[2].Ratio != 5 {
+		t.Errorf("grew ratio = %v, want 5", got[2].Ratio)
+	}
+}
+
+func TestDiffCountsUsesRates(t *testing.T) {
+	// Twice the count over a window twice as long is the same rate.
+	got := diffCounts(diffKindService, "", map[string]int64{"svc": 10}, map[string]int64{"svc": 20}, time.Hour, 2*time.Hour, 2, 1)
+	if len(got) != 0 {
+		t.Errorf("expected no change, got %+v", got)
+	}
+}
+
+func TestWindowTallyMatchesPatterns(t *testing.T) {
+	base, target := newWindowTally(), newWindowTally()
+	add := func(tally *windowTally, message, service string) {
+		tally.add(map[string]any{}, map[string]any{"message": message, "service": service, "level": "WARN"}, []string{"level"})
+	}
+	add(base, "retry 1 of 3", "a")
+	add(target, "retry 2 of 3", "b")
+	// One user in the baseline, many in the target: still one pattern.
+	add(base, "user alice logged in from web", "a")
+	add(target, "user bob logged in from web", "b")
+	add(target, "user carol logged in from web", "b")
+	// Close enough to join the baseline pattern in a shared miner, but only
+	// seen in the target.
+	add(base, "payment accepted for order 1 via card", "a")
+	add(target, "payment declined for order 2 via card", "b")
+
+	bc, tc := matchPatterns(base, target)
+	wantBase := map[string]int64{
+		"retry <NUM> of <NUM>":                      1,
+		"user <*> logged in from web":               1,
+		"payment accepted for order <NUM> via card": 1,
+	}
+	wantTarget := map[string]int64{
+		"retry <NUM> of <NUM>":                      1,
+		"user <*> logged in from web":               2,
+		"payment declined for order <NUM> via card": 1,
+	}
+	if !maps.Equal(bc, wantBase) || !maps.Equal(tc, wantTarget) {
+		t.Errorf("pattern counts = %v, %v; want %v, %v", bc, tc, wantBase, wantTarget)
+	}
+	if base.services["a"] != 3 || target.services["b"] != 4 {
+		t.Errorf("service counts = %v, %v", base.services, target.services)
+	}
+	if base.tags["level"]["WARN"] != 3 {
+		t.Errorf("tag counts = %v", base.tags)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/direct"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+// wideRange is the span above which --explain warns that a query reads a
+// lot of data.
+const wideRange = 7 * 24 * time.Hour
+
+// queryPlan is what `logs get --explain` prints instead of running a query.
+type queryPlan struct {
+	query string
+	// parsing says where parser stages run; empty when there are none.
+	parsing string
+	start   time.Time
+	end     time.Time
+	loc     *time.Location
+	// ranged is false for exports read whole because no range was given.
+	ranged  bool
+	source  string
+	output  string
+	limit   int
+	order   string
+	fields  []string
+	post    []string
+	offline bool
+}
+
+// scanEstimate is how much data a query would read, or why that is unknown.
+// A note next to a size qualifies it.
+type scanEstimate struct {
+	segments int64
+	bytes    int64
+	rows     int64
+	note     string
+}
+
+func (e scanEstimate) String() string {
+	if e.note != "" && e.segments == 0 && e.bytes == 0 {
+		return e.note
+	}
+	s := fmt.Sprintf("%d segments, %s", e.segments, formatSize(e.bytes))
+	if e.rows > 0 {
+		s += fmt.Sprintf(", %d rows", e.rows)
+	}
+	if e.note != "" {
+		s += " (" + e.note + ")"
+	}
+	return s
+}
+
+// print writes the plan and estimate to w, one "Name: value" line each.
+func (p queryPlan) print(w io.Writer, estimate scanEstimate) {
+	line := func(name, value string) {
+		_, _ = fmt.Fprintf(w, "%-10s %s\n", name+":", value)
+	}
+	line("Query", p.query)
+	if p.parsing != "" {
+		line("Parsing", p.parsing)
+	}
+	if p.ranged {
+		line("Range", timerange.Describe(p.start, p.end, p.loc))
+	} else {
+		line("Range", "whole file (no time range given)")
+	}
+	line("Source", p.source)
+	line("Output", p.output)
+	line("Limit", fmt.Sprintf("%d, %s first", p.limit, p.order))
+	switch {
+	case p.offline:
+		line("Fields", "all (read locally)")
+	case len(p.fields) == 0:
+		line("Fields", "all")
+	default:
+		line("Fields", strings.Join(p.fields, ", "))
+	}
+	if len(p.post) > 0 {
+		line("Post", strings.Join(p.post, ", "))
+	}
+	line("Estimate", estimate.String())
+}
+
+// explainGet completes plan for the source `logs get` would read, prints it
+// with a scan estimate and warns on stderr when the range is wide. No
+// entries are read.
+func explainGet(ctx context.Context, cmdObj *cobra.Command, plan queryPlan, parsed bool, client *api.Client, endpointURL string) error {
+	if parsed {
+		plan.parsing = "server-side, client-side if the server rejects it"
+		if plan.offline {
+			plan.parsing = "client-side"
+		}
+	}
+	if plan.output == "otlp" {
+		switch {
+		case otlpEndpoint != "":
+			plan.output += " to " + otlpEndpoint
+		case otlpFile != "":
+			plan.output += " to " + otlpFile
+		}
+	}
+	s, e := fmt.Sprintf("%d", plan.start.UnixMilli()), fmt.Sprintf("%d", plan.end.UnixMilli())
+
+	var estimate scanEstimate
+	switch {
+	case fromFile != "":
+		var err error
+		if estimate, err = estimateFile(fromFile); err != nil {
+			return err
+		}
+	case directURL != "":
+		loc, err := direct.ParseURL(directURL)
+		if err != nil {
+			return err
+		}
+		insecure, _ := cmdObj.Flags().GetBool("insecure")
+		store, err := direct.NewS3Store(loc, direct.S3Options{Endpoint: s3Endpoint, Insecure: insecure})
+		if err != nil {
+			return err
+		}
+		if estimate, err = estimateDirect(ctx, store, loc, plan.start, plan.end); err != nil {
+			return err
+		}
+	default:
+		plan.source = endpointURL
+		estimate = estimateAPI(ctx, client, plan.query, s, e)
+	}
+
+	plan.print(os.Stdout, estimate)
+	if span := plan.end.Sub(plan.start); plan.ranged && span > wideRange {
+		fmt.Fprintf(os.Stderr, "Warning: the query spans %.0f days; narrow it with --start/--since or add filters before running it\n", span.Hours()/24)
+	}
+	return nil
+}
+
+// describePost lists the client-side stages run on each entry, in the order
+// newPostPipeline applies them.
+func describePost(opts postOptions, redact bool) []string {
+	var stages []string
+	if len(opts.flattenJSON) > 0 {
+		stages = append(stages, "flatten-json "+strings.Join(opts.flattenJSON, ","))
+	}
+	for _, expr := range opts.extract {
+		stages = append(stages, fmt.Sprintf("extract %q", expr))
+	}
+	if len(opts.dedupe) > 0 {
+		stages = append(stages, "dedupe "+strings.Join(opts.dedupe, ","))
+	}
+	if opts.sample > 0 {
+		stages = append(stages, fmt.Sprintf("sample %g", opts.sample))
+	}
+	if len(opts.redactField) > 0 {
+		stages = append(stages, "redact-field "+strings.Join(opts.redactField, ","))
+	}
+	if redact {
+		stages = append(stages, "redact")
+	}
+	return stages
+}
+
+// estimateAPI asks the query API for an estimate through the proposed
+// estimate endpoint, which released servers do not serve; only the mock
+// does. A server without it, or one that fails, leaves a note rather than an
+// error: the rest of the plan is still worth printing.
+func estimateAPI(ctx context.Context, client *api.Client, q, s, e string) scanEstimate {
+	est, err := client.EstimateLogs(ctx, q, s, e)
+	switch {
+	case est == nil && (err == nil || api.IsNotImplemented(err)):
+		return scanEstimate{note: "not available (the query API does not offer estimates)"}
+	case err != nil:
+		return scanEstimate{note: fmt.Sprintf("unavailable (%v)", err)}
+	}
+	return scanEstimate{segments: est.Segments, bytes: est.Bytes, rows: est.Rows, note: "experimental estimate endpoint"}
+}
+
+// estimateDirect lists the segments --direct would read over [start, end).
+// Listing reads object metadata only.
+func estimateDirect(ctx context.Context, store direct.Store, loc direct.Location, start, end time.Time) (scanEstimate, error) {
+	segments, err := direct.Segments(ctx, store, loc.Prefix, start, end)
+	if err != nil {
+		return scanEstimate{}, fmt.Errorf("failed to list segments in %s: %w", loc, err)
+	}
+	est := scanEstimate{segments: int64(len(segments))}
+	for _, obj := range segments {
+		est.bytes += obj.Size
+	}
+	return est, nil
+}
+
+// estimateFile reports the size of an export; --from-file always reads it whole.
+func estimateFile(path string) (scanEstimate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return scanEstimate{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return scanEstimate{note: fmt.Sprintf("1 file, %s", formatSize(info.Size()))}, nil
+}
+
+// formatSize formats n bytes with a binary unit.
+func formatSize(n int64) string {
+	const units = "KMGTPE"
+	if n < 1<<10 {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(1<<10), 0
+	for m := n >> 10; m >= 1<<10 && exp < len(units)-1; m >>= 10 {
+		div <<= 10
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), units[exp])
+}
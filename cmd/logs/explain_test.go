@@ -0,0 +1,74 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 40, "3.0 TiB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.n); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestQueryPlanPrint(t *testing.T) {
+	start := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	plan := queryPlan{
+		query:  `{service="checkout"} | json`,
+		start:  start,
+		end:    start.Add(time.Hour),
+		loc:    time.UTC,
+		ranged: true,
+		source: "http://localhost:7101",
+		output: "json",
+		limit:  50,
+		order:  "oldest",
+		fields: []string{"status"},
+		post:   describePost(postOptions{dedupe: []string{"message"}, sample: 0.1}, true),
+	}
+	var buf bytes.Buffer
+	plan.print(&buf, scanEstimate{segments: 12, bytes: 3 << 30, rows: 1000, note: "experimental estimate endpoint"})
+	for _, want := range []string{
+		"Query:     {service=\"checkout\"} | json\n",
+		"Range:     2026-10-15 14:00:00 UTC to 2026-10-15 15:00:00 UTC (1h0m0s)\n",
+		"Limit:     50, oldest first\n",
+		"Fields:    status\n",
+		"Post:      dedupe message, sample 0.1, redact\n",
+		"Estimate:  12 segments, 3.0 GiB, 1000 rows (experimental estimate endpoint)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "Parsing:") {
+		t.Errorf("plan without parser stages has a Parsing line:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/cache"
+	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/presets"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+// queryFlags is the filter flag set shared by the commands that analyse a
+// stream of log entries (patterns, diff, anomalies, watch). It mirrors the
+// filter flags of `logs get`.
+type queryFlags struct {
+	filters            []string
+	preset             string
+	times              timeFlags
+	appName            string
+	logLevel           string
+	messageContains    string
+	messageNotContains string
+	messageRegexMatch  string
+	messageRegexNot    string
+	aliasValues        map[string]*string
+}
+
+// register adds the filter flags to cmd. It also registers alias flags, so it
+// must be called after the command's own flags to let those win collisions.
+func (f *queryFlags) register(cmd *cobra.Command) {
+	f.registerFilters(cmd)
+	f.times.register(cmd)
+	f.registerAliases(cmd)
+}
+
+// registerAliases adds the configured alias flags. Commands that take their
+// time range differently call registerFilters, then their own flags, then this.
+func (f *queryFlags) registerAliases(cmd *cobra.Command) {
+	f.aliasValues = presets.RegisterAliasFlags(cmd)
+}
+
+// registerFilters adds every filter flag except the time range.
+func (f *queryFlags) registerFilters(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&f.filters, "filter", "f", []string{}, "Filter in format 'key:value' (can be used multiple times)")
+	cmd.Flags().StringVarP(&f.preset, "preset", "p", "", "Use a named filter preset from ~/.lakerunner/config.yaml")
+	cmd.Flags().StringVarP(&f.appName, "app", "a", "", "Filter by service name (comma-separated for multiple)")
+	cmd.Flags().StringVarP(&f.logLevel, "level", "l", "", "Filter logs by log level (e.g., ERROR, INFO, DEBUG, WARN)")
+	cmd.Flags().StringVarP(&f.messageContains, "contains", "M", "", "Filter logs where message contains this string (|=)")
+	cmd.Flags().StringVarP(&f.messageNotContains, "not-contains", "N", "", "Filter logs where message does not contain this string (!=)")
+	cmd.Flags().StringVarP(&f.messageRegexMatch, "msg-regex", "R", "", "Filter logs where message matches this regex (|~)")
+	cmd.Flags().StringVarP(&f.messageRegexNot, "msg-not-regex", "X", "", "Filter logs where message does not match this regex (!~)")
+	registerCompletions(cmd)
+}
+
+// resolveFilters assembles preset, -f and alias flag filters, same rules as `logs get`.
+func (f *queryFlags) resolveFilters() ([]string, error) {
+	allFilters := f.filters
+	if f.preset != "" {
+		presetFilters, err := presets.GetFilters(f.preset)
+		if err != nil {
+			return nil, err
+		}
+		allFilters = append(presetFilters, f.filters...)
+	}
+	allFilters, err := presets.ResolveFilters(allFilters)
+	if err != nil {
+		return nil, err
+	}
+	return append(allFilters, presets.CollectAliasFilters(f.aliasValues)...), nil
+}
+
+// buildQuery returns the LogQL query for the flag values.
+func (f *queryFlags) buildQuery() (string, error) {
+	allFilters, err := f.resolveFilters()
+	if err != nil {
+		return "", err
+	}
+	return buildLogQLQuery(f.appName, f.logLevel, allFilters, f.messageContains, f.messageNotContains, f.messageRegexMatch, f.messageRegexNot), nil
+}
+
+// timeFlags are the time range flags: --start/--end, or --since, or
+// --around with --window, plus --snap and the --tz they are read in.
+type timeFlags struct {
+	start  string
+	end    string
+	since  string
+	around string
+	window string
+	snap   time.Duration
+	tz     string
+}
+
+func (t *timeFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&t.start, "start", "s", "", "Start time (e.g., 'e-1h', 'yesterday', '2024-01-01T00:00:00Z')")
+	cmd.Flags().StringVarP(&t.end, "end", "e", "", "End time (e.g., 'now', '2024-01-01T23:59:59Z')")
+	cmd.Flags().StringVar(&t.since, "since", "", "Start this long ago (e.g., '15m', '2d') or at this time (e.g., 'today')")
+	cmd.Flags().StringVar(&t.around, "around", "", "Search either side of this time (e.g., '2026-10-15T14:03Z'); see --window")
+	cmd.Flags().StringVar(&t.window, "window", "5m", "How far before and after --around to search (e.g., '90s', '2h', '1d12h')")
+	cmd.Flags().DurationVar(&t.snap, "snap", 0, "Widen the range to whole multiples of this duration (e.g., '1m', '1h')")
+	cmd.Flags().StringVar(&t.tz, "tz", "Local", "Time zone for times without one and for printed times: UTC, Local or e.g. America/New_York")
+	cmd.MarkFlagsMutuallyExclusive("since", "start")
+	cmd.MarkFlagsMutuallyExclusive("around", "start")
+	cmd.MarkFlagsMutuallyExclusive("around", "end")
+	cmd.MarkFlagsMutuallyExclusive("around", "since")
+}
+
+// location returns the --tz location, Local if it is invalid (resolve
+// reports that).
+func (t *timeFlags) location() *time.Location {
+	loc, err := timerange.LoadLocation(t.tz)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// resolve returns the absolute time range.
+func (t *timeFlags) resolve() (time.Time, time.Time, error) {
+	loc, err := timerange.LoadLocation(t.tz)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	window, err := timerange.ParseDuration(t.window)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --window: %w", err)
+	}
+	spec := timerange.Spec{Start: t.start, End: t.end, Since: t.since, Around: t.around, Window: window, Snap: t.snap, Location: loc}
+	start, end, err := spec.Resolve()
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse time range: %w", err)
+	}
+	return start, end, nil
+}
+
+// alignRange moves [start, end] forward, keeping its length, so that end falls
+// on a multiple of d.
+func alignRange(start, end time.Time, d time.Duration) (time.Time, time.Time) {
+	aligned := end.Truncate(d)
+	if aligned.Before(end) {
+		aligned = aligned.Add(d)
+	}
+	shift := aligned.Sub(end)
+	return start.Add(shift), aligned
+}
+
+// newClientFromFlags loads configuration honouring the global --endpoint,
+// --api-key and --insecure flags and returns an API client for it.
+func newClientFromFlags(cmdObj *cobra.Command) (*api.Client, *config.Config, error) {
+	endpoint, _ := cmdObj.Flags().GetString("endpoint")
+	apiKey, _ := cmdObj.Flags().GetString("api-key")
+	insecure, _ := cmdObj.Flags().GetBool("insecure")
+	cfg, err := config.LoadWithFlags(endpoint, apiKey, insecure)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return api.NewClient(cfg), cfg, nil
+}
+
+// enableCache turns on the client's response cache unless --no-cache is set.
+func enableCache(cmdObj *cobra.Command, client *api.Client) {
+	if noCache, _ := cmdObj.Flags().GetBool("no-cache"); noCache {
+		return
+	}
+	if dir, err := cache.Root(); err == nil {
+		client.EnableCache(dir)
+	}
+}
@@ -17,15 +17,23 @@ package logs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
-	"github.com/cardinalhq/oteltools/pkg/dateutils"
 	"github.com/lakerunner/cli/internal/api"
 	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/direct"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/logparse"
+	"github.com/lakerunner/cli/internal/logql"
 	"github.com/lakerunner/cli/internal/presets"
+	"github.com/lakerunner/cli/internal/redact"
+	"github.com/lakerunner/cli/internal/timerange"
 	"github.com/spf13/cobra"
 )
 
@@ -62,24 +70,28 @@ func getColorForLevel(level string, noColor bool) string {
 	}
 }
 
-// normalizeTag replaces dots with underscores in keys/values
-func normalizeTag(s string) string {
-	return strings.ReplaceAll(s, ".", "_")
+// timeDisplay formats timestamps per --time-format and --tz. The zero value
+// prints local time without a zone.
+var timeDisplay timerange.Formatter
+
+// formatEntryTime formats the entry timestamp, with nanosecond precision when
+// the entry has it.
+func formatEntryTime(message map[string]any) string {
+	if tsns, ok := message["timestamp_ns"].(int64); ok {
+		return timeDisplay.Time(time.Unix(0, tsns), true)
+	} else if ts, ok := message["timestamp"].(int64); ok {
+		return timeDisplay.Time(time.UnixMilli(ts), false)
+	} else if ts, ok := message["timestamp"].(float64); ok {
+		return timeDisplay.Time(time.UnixMilli(int64(ts)), false)
+	}
+	return ""
 }
 
 // getFieldValue extracts a field value from a log entry
 func getFieldValue(message map[string]any, tags map[string]any, field string) string {
 	switch strings.ToLower(field) {
 	case "timestamp", "ts":
-		// Prefer nanoseconds for highest precision
-		if tsns, ok := message["timestamp_ns"].(int64); ok {
-			return time.Unix(0, tsns).Format("2006-01-02 15:04:05.999999999")
-		} else if ts, ok := message["timestamp"].(int64); ok {
-			return time.UnixMilli(ts).Format("2006-01-02 15:04:05.000")
-		} else if ts, ok := message["timestamp"].(float64); ok {
-			return time.UnixMilli(int64(ts)).Format("2006-01-02 15:04:05.000")
-		}
-		return ""
+		return formatEntryTime(message)
 	case "level":
 		if tags != nil {
 			if level, ok := tags["level"].(string); ok {
@@ -102,20 +114,13 @@ func getFieldValue(message map[string]any, tags map[string]any, field string) st
 		}
 		return ""
 	case "pod":
-		if tags != nil {
-			if pod, ok := tags["k8s_pod_name"].(string); ok {
-				return pod
-			}
+		if pod, _, ok := fieldnames.Lookup(tags, "k8s_pod_name"); ok {
+			return fmt.Sprintf("%v", pod)
 		}
 		return ""
 	default:
-		if tags != nil {
-			colNorm := normalizeTag(field)
-			if v, ok := tags[field]; ok {
-				return fmt.Sprintf("%v", v)
-			} else if v, ok := tags[colNorm]; ok {
-				return fmt.Sprintf("%v", v)
-			}
+		if v, _, ok := fieldnames.Lookup(tags, field); ok {
+			return fmt.Sprintf("%v", v)
 		}
 		return ""
 	}
@@ -144,22 +149,25 @@ func formatCSVRow(values []string, delimiter string) string {
 // Multiple apps: service=~"app1|app2|app3"
 func buildAppCondition(appName string) string {
 	apps := strings.Split(appName, ",")
-	// Filter and normalize app names
-	var normalized []string
+	// Drop empty entries; names are matched as given.
+	var names []string
 	for _, a := range apps {
 		trimmed := strings.TrimSpace(a)
 		if trimmed != "" {
-			normalized = append(normalized, normalizeTag(trimmed))
+			names = append(names, trimmed)
 		}
 	}
-	if len(normalized) == 0 {
+	if len(names) == 0 {
 		return ""
 	}
-	if len(normalized) == 1 {
-		return fmt.Sprintf(`service="%s"`, normalized[0])
+	if len(names) == 1 {
+		return "service=" + logql.Quote(names[0])
+	}
+	// Multiple apps: use regex match, escaping regex metacharacters in names
+	for i, name := range names {
+		names[i] = regexp.QuoteMeta(name)
 	}
-	// Multiple apps: use regex match
-	return fmt.Sprintf(`service=~"%s"`, strings.Join(normalized, "|"))
+	return "service=~" + logql.QuoteRegex(strings.Join(names, "|"))
 }
 
 // buildLogQLQuery constructs a LogQL query from filter parameters
@@ -171,14 +179,12 @@ func buildLogQLQuery(appName, logLevel string, filters []string, messageContains
 		}
 	}
 	if logLevel != "" {
-		conditions = append(conditions, fmt.Sprintf(`level="%s"`, normalizeTag(logLevel)))
+		conditions = append(conditions, "level="+logql.Quote(logLevel))
 	}
 	for _, f := range filters {
 		parts := strings.SplitN(f, ":", 2)
 		if len(parts) == 2 {
-			key := normalizeTag(parts[0])
-			val := normalizeTag(parts[1])
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, key, val))
+			conditions = append(conditions, fieldnames.Label(parts[0])+"="+logql.Quote(parts[1]))
 		}
 	}
 
@@ -188,21 +194,53 @@ func buildLogQLQuery(appName, logLevel string, filters []string, messageContains
 	}
 
 	if messageContains != "" {
-		q += fmt.Sprintf(` |= "%s"`, normalizeTag(messageContains))
+		q += " |= " + logql.Quote(messageContains)
 	}
 	if messageNotContains != "" {
-		q += fmt.Sprintf(` != "%s"`, normalizeTag(messageNotContains))
+		q += " != " + logql.Quote(messageNotContains)
 	}
 	if messageRegexMatch != "" {
-		q += fmt.Sprintf(` |~ "%s"`, normalizeTag(messageRegexMatch))
+		q += " |~ " + logql.QuoteRegex(messageRegexMatch)
 	}
 	if messageRegexNot != "" {
-		q += fmt.Sprintf(` !~ "%s"`, normalizeTag(messageRegexNot))
+		q += " !~ " + logql.QuoteRegex(messageRegexNot)
 	}
 
 	return q
 }
 
+// buildPipeline renders parser stages and label filters as LogQL pipeline
+// expressions to append after the stream selector and line filters.
+// Example: | json | status >= 500
+func buildPipeline(stages []logparse.Stage, where []logparse.Filter) string {
+	var parts []string
+	for _, st := range stages {
+		parts = append(parts, st.LogQL())
+	}
+	for _, f := range where {
+		parts = append(parts, f.LogQL())
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+// applyClientPipeline runs parser stages and label filters locally against a
+// streamed entry, for servers that reject them. Extracted fields are merged
+// into tags so columns can reference them. Returns false if the entry is filtered out.
+func applyClientPipeline(tags map[string]any, stages []logparse.Stage, where []logparse.Filter) bool {
+	for _, st := range stages {
+		st.Apply(tags)
+	}
+	for _, f := range where {
+		if !f.Match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
 // formatJSONEntry formats a log entry as JSON
 func formatJSONEntry(message map[string]any, tags map[string]any, cols []string) string {
 	output := make(map[string]any)
@@ -217,12 +255,89 @@ func formatJSONEntry(message map[string]any, tags map[string]any, cols []string)
 	return string(data)
 }
 
+// formatTextEntry formats a log entry as a human-readable line. With no columns
+// selected it prints "[timestamp] level service: message".
+// The message is passed through hl for match highlighting and truncation.
+func formatTextEntry(message map[string]any, tags map[string]any, selectedColumns []string, noColor bool, hl *highlighter) string {
+	timestamp := formatEntryTime(message)
+
+	logMessage := ""
+	serviceName := ""
+	levelVal := ""
+	podName := ""
+	if tags != nil {
+		if msg, ok := tags["message"].(string); ok {
+			logMessage = msg
+		}
+		if service, ok := tags["service"].(string); ok {
+			serviceName = service
+		}
+		if level, ok := tags["level"].(string); ok {
+			levelVal = level
+		}
+		if pod, _, ok := fieldnames.Lookup(tags, "k8s_pod_name"); ok {
+			podName = fmt.Sprintf("%v", pod)
+		}
+	}
+
+	if len(selectedColumns) > 0 {
+		var parts []string
+		for _, col := range selectedColumns {
+			val := ""
+			switch strings.ToLower(col) {
+			case "timestamp", "ts":
+				if noColor {
+					val = timestamp
+				} else {
+					val = fmt.Sprintf("%s%s%s", colorBlue, timestamp, colorReset)
+				}
+			case "level":
+				if noColor {
+					val = levelVal
+				} else {
+					val = fmt.Sprintf("%s%s%s", getColorForLevel(levelVal, noColor), levelVal, colorReset)
+				}
+			case "message":
+				val = hl.render(logMessage, noColor)
+			case "service", "svc":
+				if noColor {
+					val = serviceName
+				} else {
+					val = fmt.Sprintf("%s%s%s", colorCyan, serviceName, colorReset)
+				}
+			case "pod":
+				if noColor {
+					val = podName
+				} else {
+					val = fmt.Sprintf("%s%s%s", colorPurple, podName, colorReset)
+				}
+			default:
+				if v, _, ok := fieldnames.Lookup(tags, col); ok {
+					val = fmt.Sprintf("%v", v)
+				} else {
+					val = "<undefined>"
+				}
+			}
+			parts = append(parts, val)
+		}
+		return strings.Join(parts, " ")
+	}
+	if noColor {
+		return fmt.Sprintf("[%s] %s %s: %s", timestamp, levelVal, serviceName, hl.render(logMessage, noColor))
+	}
+	return fmt.Sprintf("[%s%s%s] %s%s%s %s%s%s: %s",
+		colorBlue, timestamp, colorReset,
+		getColorForLevel(levelVal, noColor), levelVal, colorReset,
+		colorCyan, serviceName, colorReset,
+		hl.render(logMessage, noColor))
+}
+
 var (
 	limit              int
 	filters            []string
 	preset             string
-	startTime          string
-	endTime            string
+	getTimes           timeFlags
+	timeFormat         string
 	appName            string
 	logLevel           string
 	columns            string
@@ -234,14 +349,30 @@ var (
 	orderFlag          string
 	rawQuery           string
 	outputFormat       string
+	parseSpecs         []string
+	whereExprs         []string
+	postOpts           postOptions
+	redactOutput       bool
+	ctxOpts            contextOptions
+	highlightExprs     []string
+	highlightColorList []string
+	maxWidth           int
+	fromFile           string
+	directURL          string
+	s3Endpoint         string
+	otlpEndpoint       string
+	otlpFile           string
+	otlpHeaders        []string
+	otlpBatchSize      int
+	explainQuery       bool
 )
 
 func init() {
 	GetCmd.Flags().IntVar(&limit, "limit", 1000, "Limit the number of results returned")
 	GetCmd.Flags().StringSliceVarP(&filters, "filter", "f", []string{}, "Filter in format 'key:value' (can be used multiple times)")
 	GetCmd.Flags().StringVarP(&preset, "preset", "p", "", "Use a named filter preset from ~/.lakerunner/config.yaml")
-	GetCmd.Flags().StringVarP(&startTime, "start", "s", "", "Start time (e.g., 'e-1h', '2024-01-01T00:00:00Z')")
-	GetCmd.Flags().StringVarP(&endTime, "end", "e", "", "End time (e.g., 'now', '2024-01-01T23:59:59Z')")
+	getTimes.register(GetCmd)
+	GetCmd.Flags().StringVar(&timeFormat, "time-format", "default", "Timestamp format: default (with zone offset), iso, rfc3339, epoch (milliseconds) or epoch-ns, in --tz")
 	GetCmd.Flags().StringVarP(&appName, "app", "a", "", "Filter by service name (comma-separated for multiple)")
 	GetCmd.Flags().StringVarP(&logLevel, "level", "l", "", "Filter logs by log level (e.g., ERROR, INFO, DEBUG, WARN)")
 	GetCmd.Flags().StringVarP(&columns, "columns", "c", "", "Comma or space separated columns to display (e.g., 'timestamp,level,message')")
@@ -251,8 +382,36 @@ func init() {
 	GetCmd.Flags().StringVarP(&messageRegexNot, "msg-not-regex", "X", "", "Filter logs where message does not match this regex (!~)")
 	GetCmd.Flags().StringVar(&orderFlag, "order", "newest", "Log ordering: newest or oldest")
 	GetCmd.Flags().StringVar(&rawQuery, "query", "", "Raw LogQL query (bypasses filter flags)")
-	GetCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, csv, tsv")
+	GetCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, csv, tsv, otlp")
+	GetCmd.Flags().StringArrayVar(&parseSpecs, "parse", []string{}, `Parse the message: json, logfmt or pattern:"<ip> - <_> <status>" (can be used multiple times)`)
+	GetCmd.Flags().StringArrayVar(&whereExprs, "where", []string{}, `Filter on parsed fields, e.g. 'status>=500' or 'method="GET"' (can be used multiple times)`)
+	GetCmd.Flags().StringSliceVar(&postOpts.dedupe, "dedupe", []string{}, "Drop entries whose values for these columns were already printed (e.g., 'message' or 'service,message')")
+	GetCmd.Flags().Float64Var(&postOpts.sample, "sample", 0, "Print a random fraction of entries (e.g., 0.1 for 10%)")
+	GetCmd.Flags().StringArrayVar(&postOpts.extract, "extract", []string{}, `Extract fields from the message with a regex, e.g. 'latency=(\d+)ms' (can be used multiple times)`)
+	GetCmd.Flags().StringSliceVar(&postOpts.flattenJSON, "flatten-json", []string{}, "Flatten JSON held in these columns into top-level columns (e.g., 'message')")
+	GetCmd.Flags().StringSliceVar(&postOpts.redactField, "redact-field", []string{}, "Replace the value of these columns with [REDACTED]")
+	GetCmd.Flags().IntVarP(&ctxOpts.before, "before", "B", 0, "Show N entries the same stream logged before each match")
+	GetCmd.Flags().IntVarP(&ctxOpts.after, "after", "A", 0, "Show N entries the same stream logged after each match")
+	GetCmd.Flags().IntVarP(&ctxOpts.around, "context", "C", 0, "Show N entries before and after each match")
+	GetCmd.Flags().StringSliceVar(&ctxOpts.keys, "context-key", []string{"service", "k8s_pod_name"}, "Tags identifying the stream that context entries are taken from")
+	GetCmd.Flags().DurationVar(&ctxOpts.window, "context-window", 10*time.Minute, "How far before and after a match to look for context entries")
+	GetCmd.Flags().StringArrayVar(&highlightExprs, "highlight", []string{}, "Highlight matches of this regex in messages, in addition to --contains/--msg-regex (can be used multiple times)")
+	GetCmd.Flags().StringSliceVar(&highlightColorList, "highlight-color", []string{"red"}, "Highlight colors, assigned to patterns in order: red, green, yellow, blue, purple, cyan, white, reverse")
+	GetCmd.Flags().IntVar(&maxWidth, "max-width", 0, "Truncate messages to this many characters around the first match (0 = no limit)")
+	GetCmd.Flags().BoolVar(&redactOutput, "redact", false, "Mask emails, IPs, JWTs, AWS keys, card numbers and configured patterns (default from redaction settings in ~/.lakerunner/config.yaml)")
+	GetCmd.Flags().StringVar(&fromFile, "from-file", "", "Query a saved export (.ndjson, .ndjson.gz or .parquet) locally instead of the API")
+	GetCmd.Flags().StringVar(&directURL, "direct", "", "Read the Parquet segments in s3://bucket/prefix directly instead of querying the API")
+	GetCmd.Flags().StringVar(&s3Endpoint, "s3-endpoint", "", "S3 endpoint for --direct, e.g. http://localhost:9000 for MinIO (default $AWS_ENDPOINT_URL or s3.amazonaws.com)")
+	GetCmd.Flags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP receiver for -o otlp, e.g. http://localhost:4318")
+	GetCmd.Flags().StringVar(&otlpFile, "otlp-file", "", "Write -o otlp as OTLP/JSON to this file instead, one batch per line ('-' for stdout)")
+	GetCmd.Flags().StringArrayVar(&otlpHeaders, "otlp-header", nil, "HTTP header for --otlp-endpoint as key=value (repeatable)")
+	GetCmd.Flags().IntVar(&otlpBatchSize, "otlp-batch-size", 1000, "Records per OTLP request or line")
+	GetCmd.Flags().BoolVar(&explainQuery, "explain", false, "Print the effective query, time range, fields and, where the source can tell, a scan estimate instead of running the query")
+	GetCmd.Flags().BoolVar(&explainQuery, "dry-run", false, "Same as --explain")
+	GetCmd.MarkFlagsMutuallyExclusive("from-file", "direct")
+	GetCmd.MarkFlagsMutuallyExclusive("otlp-endpoint", "otlp-file")
 	getAliasValues = presets.RegisterAliasFlags(GetCmd)
+	registerCompletions(GetCmd)
 }
 
 var GetCmd = &cobra.Command{
@@ -264,13 +423,29 @@ var GetCmd = &cobra.Command{
 func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 	noColor, _ := cmdObj.Flags().GetBool("no-color")
 
+	var err error
+	if timeDisplay, err = timerange.NewFormatter(timeFormat, getTimes.tz); err != nil {
+		return err
+	}
+
 	// Validate output format
 	outputFormat = strings.ToLower(outputFormat)
 	switch outputFormat {
-	case "text", "json", "csv", "tsv":
+	case "text", "json", "csv", "tsv", "otlp":
 		// valid
 	default:
-		return fmt.Errorf("invalid output format %q: must be one of text, json, csv, tsv", outputFormat)
+		return fmt.Errorf("invalid output format %q: must be one of text, json, csv, tsv, otlp", outputFormat)
+	}
+	// Structured output keeps its plain timestamps unless a zone was asked for.
+	timeDisplay.Zone = outputFormat == "text" || cmdObj.Flags().Changed("tz")
+	var otlpOut *otlpOutput
+	if outputFormat == "otlp" && !explainQuery {
+		if otlpOut, err = newOTLPOutput(otlpEndpoint, otlpFile, otlpHeaders, otlpBatchSize); err != nil {
+			return err
+		}
+		defer func() { _ = otlpOut.close() }()
+	} else if outputFormat != "otlp" && (otlpEndpoint != "" || otlpFile != "") {
+		return fmt.Errorf("--otlp-endpoint and --otlp-file require -o otlp")
 	}
 
 	// Validate and convert order flag
@@ -285,6 +460,49 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 		return fmt.Errorf("invalid order %q: must be newest or oldest", orderFlag)
 	}
 
+	var stages []logparse.Stage
+	for _, spec := range parseSpecs {
+		st, err := logparse.ParseStage(spec)
+		if err != nil {
+			return err
+		}
+		stages = append(stages, st)
+	}
+	var where []logparse.Filter
+	for _, expr := range whereExprs {
+		f, err := logparse.ParseFilter(expr)
+		if err != nil {
+			return err
+		}
+		where = append(where, f)
+	}
+
+	pipe, err := newPostPipeline(postOpts, nil)
+	if err != nil {
+		return err
+	}
+
+	var hl *highlighter
+	if messageContains != "" || messageRegexMatch != "" || len(highlightExprs) > 0 || maxWidth > 0 {
+		hl, err = newHighlighter(messageContains, messageRegexMatch, highlightExprs, highlightColorList, maxWidth)
+		if err != nil {
+			return err
+		}
+	}
+
+	contextOpts := ctxOpts.resolve(cmdObj.Flags().Changed("before"), cmdObj.Flags().Changed("after"))
+	if contextOpts.enabled() && outputFormat != "text" {
+		return fmt.Errorf("--before/--after/--context require text output")
+	}
+	if contextOpts.enabled() && fromFile != "" {
+		return fmt.Errorf("--before/--after/--context are not supported with --from-file")
+	}
+	if contextOpts.enabled() && directURL != "" {
+		return fmt.Errorf("--before/--after/--context are not supported with --direct")
+	}
+	// Context entries skip dedupe/sampling but are still redacted.
+	var contextPipe postPipeline
+
 	var selectedColumns []string
 	if columns != "" {
 		parts := strings.Split(columns, ",")
@@ -304,25 +522,68 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 			case "timestamp", "ts", "level", "message", "service", "svc", "pod":
 				// skip display-only
 			default:
-				fields = append(fields, col)
+				fields = append(fields, fieldnames.Label(col))
 			}
 		}
 	}
 
-	endpoint, _ := cmdObj.Flags().GetString("endpoint")
-	apiKey, _ := cmdObj.Flags().GetString("api-key")
-	insecure, _ := cmdObj.Flags().GetBool("insecure")
-	cfg, err := config.LoadWithFlags(endpoint, apiKey, insecure)
+	// Offline and direct queries need no endpoint or API key.
+	offline, source := fromFile != "", fromFile
+	if directURL != "" {
+		offline, source = true, directURL
+	}
+	var client *api.Client
+	var endpointURL string
+	if !offline {
+		endpoint, _ := cmdObj.Flags().GetString("endpoint")
+		apiKey, _ := cmdObj.Flags().GetString("api-key")
+		insecure, _ := cmdObj.Flags().GetBool("insecure")
+		cfg, err := config.LoadWithFlags(endpoint, apiKey, insecure)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		client = api.NewClient(cfg)
+		endpointURL = cfg.LAKERUNNER_QUERY_URL
+	}
+
+	// Redaction runs last so no formatter ever sees the raw values.
+	userCfg, err := presets.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return err
+	}
+	redactEnabled := userCfg.Redaction.DefaultFor(endpointURL)
+	if cmdObj.Flags().Changed("redact") {
+		redactEnabled = redactOutput
+	}
+	if redactEnabled {
+		redactor, err := redact.New(userCfg.Redaction)
+		if err != nil {
+			return err
+		}
+		pipe = append(pipe, redactStage(redactor))
+		contextPipe = append(contextPipe, redactStage(redactor))
+	} else if client != nil {
+		// The cache holds results as the server sent them, so it is only
+		// used when nothing needs masking.
+		enableCache(cmdObj, client)
+		if quiet, _ := cmdObj.Flags().GetBool("quiet"); !quiet {
+			client.NotifyCacheHits(func(stored time.Time) {
+				fmt.Fprintf(os.Stderr, "(cached %s ago; --no-cache to refresh)\n", time.Since(stored).Round(time.Second))
+			})
+		}
 	}
-	client := api.NewClient(cfg)
 
 	// Parse start and end times
-	startMs, endMs, err := dateutils.ToStartEnd(startTime, endTime)
+	start, end, err := getTimes.resolve()
 	if err != nil {
-		return fmt.Errorf("failed to parse time range: %w", err)
+		return err
 	}
+	// Results are cached by exact range, so a range ending now is moved
+	// forward to the next whole minute for a re-run to find it.
+	if client != nil && client.CacheEnabled() && getTimes.snap == 0 && getTimes.around == "" && !cmdObj.Flags().Changed("end") {
+		start, end = alignRange(start, end, api.LogsCacheBucket)
+	}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
 	startTimeStr := fmt.Sprintf("%d", startMs)
 	endTimeStr := fmt.Sprintf("%d", endMs)
 
@@ -360,20 +621,88 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 	} else {
 		q = buildLogQLQuery(appName, logLevel, allFilters, messageContains, messageNotContains, messageRegexMatch, messageRegexNot)
 	}
+	baseQuery := q
+	q += buildPipeline(stages, where)
 
 	// Context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	responseChan, err := client.QueryLogs(ctx, q, startTimeStr, endTimeStr, limit, reverseOrder, fields)
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	summarize := !quiet
+
+	if explainQuery {
+		return explainGet(ctx, cmdObj, queryPlan{
+			query:   q,
+			start:   start,
+			end:     end,
+			loc:     timeDisplay.Location,
+			ranged:  fromFile == "" || slices.ContainsFunc([]string{"start", "end", "since", "around"}, cmdObj.Flags().Changed),
+			source:  source,
+			output:  outputFormat,
+			limit:   limit,
+			order:   orderFlag,
+			fields:  fields,
+			post:    describePost(postOpts, redactEnabled),
+			offline: offline,
+		}, q != baseQuery, client, endpointURL)
+	}
+
+	var responseChan <-chan api.LogsResponse
+	// streamErr, when set, reports an error that cut the stream short.
+	var streamErr func() error
+	clientParse := false
+	// Exports and segments hold raw lines, so parser stages and --where run
+	// client-side, before --limit as on the server.
+	local := localQuery{q: baseQuery, startMs: startMs, endMs: endMs, timeRange: true, reverse: reverseOrder, limit: limit}
+	if q != baseQuery {
+		local.stages, local.where = stages, where
+	}
+	if fromFile != "" {
+		q = baseQuery
+		// An export covers whatever window it was taken from; only an
+		// explicit --start/--end narrows it.
+		local.timeRange = slices.ContainsFunc([]string{"start", "end", "since", "around"}, cmdObj.Flags().Changed)
+		responseChan, err = readLogFile(fromFile, local)
+		if err != nil {
+			return err
+		}
+	} else if directURL != "" {
+		q = baseQuery
+		loc, err := direct.ParseURL(directURL)
+		if err != nil {
+			return err
+		}
+		insecure, _ := cmdObj.Flags().GetBool("insecure")
+		store, err := direct.NewS3Store(loc, direct.S3Options{Endpoint: s3Endpoint, Insecure: insecure})
+		if err != nil {
+			return err
+		}
+		responseChan, streamErr, err = readDirect(ctx, store, loc, local)
+		if err != nil {
+			return err
+		}
+	} else {
+		responseChan, err = client.QueryLogs(ctx, q, startTimeStr, endTimeStr, limit, reverseOrder, fields)
+	}
+	if err != nil && q != baseQuery && (api.IsBadRequest(err) || api.IsNotImplemented(err)) {
+		// Server rejected the parser stages; fetch raw lines and parse locally.
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Warning: server rejected parser stages (%v); parsing client-side, --limit applies before --where\n", err)
+		}
+		clientParse = true
+		q = baseQuery
+		responseChan, err = client.QueryLogs(ctx, q, startTimeStr, endTimeStr, limit, reverseOrder, fields)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to query logs: %w", err)
 	}
-
-	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if clientParse {
+		pipe = append(postPipeline{clientParseStage(stages, where)}, pipe...)
+	}
 
 	// Structured output formats disable colors and progress indicators
-	isStructuredOutput := outputFormat == "json" || outputFormat == "csv" || outputFormat == "tsv"
+	isStructuredOutput := outputFormat == "json" || outputFormat == "csv" || outputFormat == "tsv" || outputFormat == "otlp"
 	if isStructuredOutput {
 		noColor = true
 		quiet = true
@@ -385,8 +714,12 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 		outputColumns = []string{"timestamp", "level", "service", "message"}
 	}
 
-	if !quiet {
-		fmt.Printf("Querying logs from %s to %s...\n", startTimeStr, endTimeStr)
+	if !quiet && offline {
+		fmt.Printf("Reading logs from %s...\n", source)
+		fmt.Printf("LogQL: %s\n", q)
+		fmt.Println("---")
+	} else if !quiet {
+		fmt.Printf("Querying logs from %s...\n", timerange.Describe(start, end, timeDisplay.Location))
 		fmt.Printf("LogQL: %s\n", q)
 		fmt.Printf("Limit: %d results\n", limit)
 		if len(selectedColumns) > 0 {
@@ -422,7 +755,8 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 		}()
 	}
 
-	for response := range responseChan {
+	var matches []api.LogsResponse
+	for response := range pipe.run(ctx, responseChan) {
 		responseCount++
 		if responseCount == 1 && !quiet {
 			fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 50))
@@ -446,95 +780,17 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 				values[i] = getFieldValue(message, tags, col)
 			}
 			fmt.Println(formatCSVRow(values, "\t"))
-		default: // text
-			// Get timestamp with proper precision handling (prefer nanoseconds)
-			timestamp := ""
-			if tsns, ok := message["timestamp_ns"].(int64); ok {
-				timestamp = time.Unix(0, tsns).Format("2006-01-02 15:04:05.999999999")
-			} else if ts, ok := message["timestamp"].(int64); ok {
-				timestamp = time.UnixMilli(ts).Format("2006-01-02 15:04:05.000")
-			} else if ts, ok := message["timestamp"].(float64); ok {
-				timestamp = time.UnixMilli(int64(ts)).Format("2006-01-02 15:04:05.000")
-			}
-
-			logMessage := ""
-			serviceName := ""
-			levelVal := ""
-			podName := ""
-			if tags != nil {
-				if msg, ok := tags["message"].(string); ok {
-					logMessage = msg
-				}
-				if service, ok := tags["service"].(string); ok {
-					serviceName = service
-				}
-				if level, ok := tags["level"].(string); ok {
-					levelVal = level
-				}
-				if pod, ok := tags["k8s_pod_name"].(string); ok {
-					podName = pod
-				}
+		case "otlp":
+			if err := otlpOut.add(cmdObj.Context(), message); err != nil {
+				return err
 			}
-
-			if len(selectedColumns) > 0 {
-				var parts []string
-				for _, col := range selectedColumns {
-					val := ""
-					switch strings.ToLower(col) {
-					case "timestamp", "ts":
-						if noColor {
-							val = timestamp
-						} else {
-							val = fmt.Sprintf("%s%s%s", colorBlue, timestamp, colorReset)
-						}
-					case "level":
-						if noColor {
-							val = levelVal
-						} else {
-							val = fmt.Sprintf("%s%s%s", getColorForLevel(levelVal, noColor), levelVal, colorReset)
-						}
-					case "message":
-						val = logMessage
-					case "service", "svc":
-						if noColor {
-							val = serviceName
-						} else {
-							val = fmt.Sprintf("%s%s%s", colorCyan, serviceName, colorReset)
-						}
-					case "pod":
-						if noColor {
-							val = podName
-						} else {
-							val = fmt.Sprintf("%s%s%s", colorPurple, podName, colorReset)
-						}
-					default:
-						if tags != nil {
-							colNorm := normalizeTag(col)
-							if v, ok := tags[col]; ok {
-								val = fmt.Sprintf("%v", v)
-							} else if v, ok := tags[colNorm]; ok {
-								val = fmt.Sprintf("%v", v)
-							} else {
-								val = "<undefined>"
-							}
-						} else {
-							val = "<undefined>"
-						}
-					}
-					parts = append(parts, val)
-				}
-				fmt.Println(strings.Join(parts, " "))
-			} else {
-				if noColor {
-					fmt.Printf("[%s] %s %s: %s\n", timestamp, levelVal, serviceName, logMessage)
-				} else {
-					fmt.Printf("[%s%s%s] %s%s%s %s%s%s: %s\n",
-						colorBlue, timestamp, colorReset,
-						getColorForLevel(levelVal, noColor), levelVal, colorReset,
-						colorCyan, serviceName, colorReset,
-						logMessage)
-				}
+		default: // text
+			if contextOpts.enabled() {
+				// Print once all matches are in, with their neighbours.
+				matches = append(matches, response)
+				break
 			}
+			fmt.Println(formatTextEntry(message, tags, selectedColumns, noColor, hl))
 		}
 
 		if responseCount >= limit {
@@ -542,9 +798,57 @@ func runGetCmd(cmdObj *cobra.Command, _ []string) error {
 			break
 		}
 	}
+	if streamErr != nil {
+		if err := streamErr(); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to read segments: %w", err)
+		}
+	}
+
+	if len(matches) > maxContextMatches && !quiet {
+		fmt.Fprintf(os.Stderr, "Warning: fetching context for the first %d of %d matches; narrow the query or lower --limit for more\n", maxContextMatches, len(matches))
+	}
+	groups := make([]contextGroup, len(matches))
+	for i, match := range matches {
+		if i >= maxContextMatches {
+			groups[i] = contextGroup{match: match}
+			continue
+		}
+		// The stream's ctx is cancelled once --limit is reached.
+		group, err := fetchContext(cmdObj.Context(), client, match, contextOpts, fields)
+		if err != nil {
+			return err
+		}
+		groups[i] = group
+	}
+	for i, block := range mergeContextGroups(groups) {
+		if i > 0 {
+			fmt.Println(contextSeparator)
+		}
+		for _, line := range block {
+			tags, _ := line.entry.Data["tags"].(map[string]any)
+			if line.match {
+				fmt.Println(formatMatchMarker(noColor) + formatTextEntry(line.entry.Data, tags, selectedColumns, noColor, hl))
+			} else if tags != nil && contextPipe.apply(line.entry.Data, tags) {
+				fmt.Println(contextMarker + formatTextEntry(line.entry.Data, tags, selectedColumns, noColor, hl))
+			}
+		}
+	}
+
+	if otlpOut != nil {
+		if err := otlpOut.close(); err != nil {
+			return err
+		}
+		if summarize {
+			fmt.Fprintf(os.Stderr, "Sent %d records in %d batches to %s\n", otlpOut.batcher.records, otlpOut.batcher.batches, otlpOut.destination)
+		}
+	}
 
 	if responseCount == 0 && !quiet {
-		fmt.Println("No responses received from the API")
+		if offline {
+			fmt.Printf("No entries in %s matched\n", source)
+		} else {
+			fmt.Println("No responses received from the API")
+		}
 	}
 	return nil
 }
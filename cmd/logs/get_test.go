@@ -18,6 +18,10 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logparse"
+	"github.com/lakerunner/cli/internal/logql"
 )
 
 // Mock log entries based on real API responses from OpenTelemetry demo app
@@ -165,6 +169,20 @@ func TestGetFieldValue(t *testing.T) {
 			field:    "resource.k8s.cluster.name",
 			expected: "my-cluster",
 		},
+		{
+			name:     "get underscore field from dotted tag",
+			message:  map[string]any{},
+			tags:     map[string]any{"http.route": "/cart"},
+			field:    "http_route",
+			expected: "/cart",
+		},
+		{
+			name:     "get pod from dotted tag",
+			message:  map[string]any{},
+			tags:     map[string]any{"k8s.pod.name": "my-pod-abc123"},
+			field:    "pod",
+			expected: "my-pod-abc123",
+		},
 		{
 			name:     "missing field returns empty",
 			message:  map[string]any{},
@@ -411,28 +429,6 @@ func TestFormatJSONEntryValues(t *testing.T) {
 	}
 }
 
-func TestNormalizeTag(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"resource.service.name", "resource_service_name"},
-		{"log.level", "log_level"},
-		{"no_dots_here", "no_dots_here"},
-		{"a.b.c.d", "a_b_c_d"},
-		{"", ""},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := normalizeTag(tt.input)
-			if result != tt.expected {
-				t.Errorf("normalizeTag(%q) = %q, want %q", tt.input, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestGetColorForLevel(t *testing.T) {
 	tests := []struct {
 		level   string
@@ -574,7 +570,7 @@ func TestBuildLogQLQuery(t *testing.T) {
 			name:              "message regex match",
 			appName:           "cartservice",
 			messageRegexMatch: "user_id=\\d+",
-			expected:          `{service="cartservice"} |~ "user_id=\d+"`,
+			expected:          "{service=\"cartservice\"} |~ `user_id=\\d+`",
 		},
 		{
 			name:            "message regex not",
@@ -589,7 +585,7 @@ func TestBuildLogQLQuery(t *testing.T) {
 			messageNotContains: "health",
 			messageRegexMatch:  "status=\\d+",
 			messageRegexNot:    "DEBUG",
-			expected:           `{service="cartservice"} |= "request" != "health" |~ "status=\d+" !~ "DEBUG"`,
+			expected:           "{service=\"cartservice\"} |= \"request\" != \"health\" |~ `status=\\d+` !~ \"DEBUG\"",
 		},
 		{
 			name:     "full complex query",
@@ -599,6 +595,17 @@ func TestBuildLogQLQuery(t *testing.T) {
 			messageContains: "timeout",
 			expected: `{service=~"cartservice|checkoutservice", level="ERROR", environment="prod"} |= "timeout"`,
 		},
+		{
+			name:     "dotted filter key, values kept",
+			filters:  []string{"k8s.pod.name:api.v1.2.3", "service_version:1.2.3"},
+			expected: `{k8s_pod_name="api.v1.2.3", service_version="1.2.3"}`,
+		},
+		{
+			name:              "line filters kept",
+			messageContains:   "v1.2.3",
+			messageRegexMatch: "a.b",
+			expected:          `{service=~".+"} |= "v1.2.3" |~ "a.b"`,
+		},
 		{
 			name:     "empty app entries ignored",
 			appName:  ",,,",
@@ -610,6 +617,13 @@ func TestBuildLogQLQuery(t *testing.T) {
 			appName:  "cartservice,",
 			expected: `{service="cartservice"}`,
 		},
+		{
+			name:              "quotes and backslashes escaped",
+			filters:           []string{`path:C:\tmp\"x"`},
+			messageContains:   `said "hi"`,
+			messageRegexMatch: `"id":\d+`,
+			expected:          `{path="C:\\tmp\\\"x\""} |= "said \"hi\"" |~ ` + "`\"id\":\\d+`",
+		},
 	}
 
 	for _, tt := range tests {
@@ -618,6 +632,9 @@ func TestBuildLogQLQuery(t *testing.T) {
 			if result != tt.expected {
 				t.Errorf("buildLogQLQuery() =\n  %q\nwant:\n  %q", result, tt.expected)
 			}
+			if _, err := logql.Parse(result); err != nil {
+				t.Errorf("buildLogQLQuery() built invalid LogQL: %v", err)
+			}
 		})
 	}
 }
@@ -661,17 +678,17 @@ func TestBuildAppCondition(t *testing.T) {
 		{
 			name:     "single app with dots",
 			input:    "my.service.name",
-			expected: `service="my_service_name"`,
+			expected: `service="my.service.name"`,
 		},
 		{
 			name:     "multiple apps with dots",
 			input:    "my.service,another.service",
-			expected: `service=~"my_service|another_service"`,
+			expected: "service=~`my\\.service|another\\.service`",
 		},
 		{
 			name:     "mixed dots and underscores",
 			input:    "cart.service,checkout_service",
-			expected: `service=~"cart_service|checkout_service"`,
+			expected: "service=~`cart\\.service|checkout_service`",
 		},
 		{
 			name:     "trailing comma filtered",
@@ -762,3 +779,83 @@ func TestCSVEscapingWithRealMessages(t *testing.T) {
 	}
 }
 
+func TestBuildPipeline(t *testing.T) {
+	mustStage := func(spec string) logparse.Stage {
+		st, err := logparse.ParseStage(spec)
+		if err != nil {
+			t.Fatalf("ParseStage(%q): %v", spec, err)
+		}
+		return st
+	}
+	mustFilter := func(expr string) logparse.Filter {
+		f, err := logparse.ParseFilter(expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", expr, err)
+		}
+		return f
+	}
+
+	base := buildLogQLQuery("cartservice", "", nil, "timeout", "", "", "")
+	tests := []struct {
+		name     string
+		stages   []logparse.Stage
+		where    []logparse.Filter
+		expected string
+	}{
+		{
+			name:     "no stages",
+			expected: `{service="cartservice"} |= "timeout"`,
+		},
+		{
+			name:     "json with numeric filter",
+			stages:   []logparse.Stage{mustStage("json")},
+			where:    []logparse.Filter{mustFilter("status>=500")},
+			expected: `{service="cartservice"} |= "timeout" | json | status >= 500`,
+		},
+		{
+			name:     "pattern with string filter",
+			stages:   []logparse.Stage{mustStage(`pattern:"<ip> - <_> <status>"`)},
+			where:    []logparse.Filter{mustFilter(`ip=~"10\..*"`)},
+			expected: `{service="cartservice"} |= "timeout" | pattern "<ip> - <_> <status>" | ip=~` + "`10\\..*`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := base + buildPipeline(tt.stages, tt.where)
+			if result != tt.expected {
+				t.Errorf("query =\n  %q\nwant:\n  %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyClientPipeline(t *testing.T) {
+	stage, _ := logparse.ParseStage("logfmt")
+	where, _ := logparse.ParseFilter("status>=500")
+
+	tags := map[string]any{"service": "frontend", "message": `method=GET path=/cart status=503`}
+	if !applyClientPipeline(tags, []logparse.Stage{stage}, []logparse.Filter{where}) {
+		t.Fatal("expected entry with status=503 to pass")
+	}
+	if got := getFieldValue(nil, tags, "path"); got != "/cart" {
+		t.Errorf("extracted column path = %q, want /cart", got)
+	}
+
+	tags = map[string]any{"message": `method=GET path=/ status=200`}
+	if applyClientPipeline(tags, []logparse.Stage{stage}, []logparse.Filter{where}) {
+		t.Error("expected entry with status=200 to be filtered out")
+	}
+}
+
+func TestAlignRange(t *testing.T) {
+	end := time.Date(2026, 10, 15, 14, 3, 27, 0, time.UTC)
+	start, got := alignRange(end.Add(-time.Hour), end, time.Minute)
+	if want := time.Date(2026, 10, 15, 14, 4, 0, 0, time.UTC); !got.Equal(want) || got.Sub(start) != time.Hour {
+		t.Errorf("alignRange() = %s..%s, want an hour ending %s", start, got, want)
+	}
+	whole := time.Date(2026, 10, 15, 14, 4, 0, 0, time.UTC)
+	if _, got := alignRange(whole.Add(-time.Hour), whole, time.Minute); !got.Equal(whole) {
+		t.Errorf("aligned end moved to %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	colorBold    = "\033[1m"
+	colorReverse = "\033[7m"
+	ellipsis     = "…"
+)
+
+// highlightColors maps --highlight-color names to escape sequences.
+var highlightColors = map[string]string{
+	"red":     colorRed,
+	"green":   colorGreen,
+	"yellow":  colorYellow,
+	"blue":    colorBlue,
+	"purple":  colorPurple,
+	"magenta": colorPurple,
+	"cyan":    colorCyan,
+	"white":   colorWhite,
+	"reverse": colorReverse,
+}
+
+// highlighter marks line-filter and --highlight matches in messages and
+// truncates long messages around the first match.
+type highlighter struct {
+	patterns []*regexp.Regexp
+	colors   []string
+	maxWidth int
+}
+
+// newHighlighter compiles the --contains text, --msg-regex and extra
+// --highlight regexes. Colours are assigned to patterns in order, cycling.
+func newHighlighter(contains, msgRegex string, extra []string, colorNames []string, maxWidth int) (*highlighter, error) {
+	h := &highlighter{maxWidth: maxWidth}
+	if contains != "" {
+		h.patterns = append(h.patterns, regexp.MustCompile(regexp.QuoteMeta(contains)))
+	}
+	for _, expr := range append([]string{msgRegex}, extra...) {
+		if expr == "" {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid highlight pattern %q: %w", expr, err)
+		}
+		h.patterns = append(h.patterns, re)
+	}
+	if len(colorNames) == 0 {
+		colorNames = []string{"red"}
+	}
+	for _, name := range colorNames {
+		c, ok := highlightColors[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid highlight color %q", name)
+		}
+		h.colors = append(h.colors, colorBold+c)
+	}
+	if maxWidth < 0 {
+		return nil, fmt.Errorf("invalid --max-width %d", maxWidth)
+	}
+	return h, nil
+}
+
+// span is a highlighted byte range of a message.
+type span struct {
+	start, end int
+	color      string
+}
+
+// spans returns the non-overlapping matches of all patterns in msg, ordered by
+// position. Where matches overlap, the earlier-listed pattern wins.
+func (h *highlighter) spans(msg string) []span {
+	var all []span
+	for i, re := range h.patterns {
+		for _, loc := range re.FindAllStringIndex(msg, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			all = append(all, span{start: loc[0], end: loc[1], color: h.colors[i%len(h.colors)]})
+		}
+	}
+	sort.SliceStable(all, func(a, b int) bool { return all[a].start < all[b].start })
+	var out []span
+	for _, s := range all {
+		if len(out) > 0 && s.start < out[len(out)-1].end {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// window returns the byte range of msg to display so that it fits in maxWidth
+// runes, centred on the first match when there is one.
+func (h *highlighter) window(msg string, spans []span) (int, int) {
+	if h.maxWidth == 0 || utf8.RuneCountInString(msg) <= h.maxWidth {
+		return 0, len(msg)
+	}
+	// Leave room for the ellipses marking the cut ends; only one is needed
+	// when the window touches either end of the message.
+	width := max(1, h.maxWidth-2)
+	centre := 0
+	if len(spans) > 0 {
+		centre = utf8.RuneCountInString(msg[:spans[0].start]) + utf8.RuneCountInString(msg[spans[0].start:spans[0].end])/2
+	}
+	total := utf8.RuneCountInString(msg)
+	first := centre - width/2
+	switch {
+	case first <= 0:
+		return 0, runeOffset(msg, max(1, h.maxWidth-1))
+	case first+width >= total-1:
+		return runeOffset(msg, total-max(1, h.maxWidth-1)), len(msg)
+	}
+	return runeOffset(msg, first), runeOffset(msg, first+width)
+}
+
+// runeOffset converts a rune index into a byte offset in s.
+func runeOffset(s string, n int) int {
+	i := 0
+	for off := range s {
+		if i == n {
+			return off
+		}
+		i++
+	}
+	return len(s)
+}
+
+// render returns msg truncated to --max-width with matches coloured. A nil
+// highlighter returns msg unchanged.
+func (h *highlighter) render(msg string, noColor bool) string {
+	if h == nil {
+		return msg
+	}
+	spans := h.spans(msg)
+	ws, we := h.window(msg, spans)
+
+	var b strings.Builder
+	if ws > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := ws
+	for _, s := range spans {
+		if noColor || s.end <= ws || s.start >= we {
+			continue
+		}
+		start, end := max(s.start, ws), min(s.end, we)
+		b.WriteString(msg[pos:start])
+		b.WriteString(s.color + msg[start:end] + colorReset)
+		pos = end
+	}
+	b.WriteString(msg[pos:we])
+	if we < len(msg) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHighlighterRender(t *testing.T) {
+	red := colorBold + colorRed
+	yellow := colorBold + colorYellow
+
+	tests := []struct {
+		name     string
+		contains string
+		regex    string
+		extra    []string
+		colors   []string
+		maxWidth int
+		noColor  bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "contains literal",
+			contains: "error.",
+			input:    "an error. another error.",
+			expected: "an " + red + "error." + colorReset + " another " + red + "error." + colorReset,
+		},
+		{
+			name:     "regex and extra with color cycling",
+			regex:    `\d+ms`,
+			extra:    []string{"timeout"},
+			colors:   []string{"red", "yellow"},
+			input:    "timeout after 150ms",
+			expected: yellow + "timeout" + colorReset + " after " + red + "150ms" + colorReset,
+		},
+		{
+			name:     "overlap keeps the first pattern",
+			contains: "connection refused",
+			extra:    []string{"refused by peer"},
+			input:    "connection refused by peer",
+			expected: red + "connection refused" + colorReset + " by peer",
+		},
+		{
+			name:     "no color leaves text plain",
+			contains: "error",
+			noColor:  true,
+			input:    "an error occurred",
+			expected: "an error occurred",
+		},
+		{
+			name:     "truncate around first match",
+			contains: "NEEDLE",
+			maxWidth: 12,
+			noColor:  true,
+			input:    strings.Repeat("a", 30) + "NEEDLE" + strings.Repeat("b", 30),
+			expected: "…aaNEEDLEbb…",
+		},
+		{
+			name:     "truncate without match keeps the start",
+			maxWidth: 8,
+			noColor:  true,
+			input:    "abcdefghijklmnop",
+			expected: "abcdefg…",
+		},
+		{
+			name:     "match near the end",
+			contains: "end",
+			maxWidth: 7,
+			input:    "0123456789end",
+			expected: "…789" + red + "end" + colorReset,
+		},
+		{
+			name:     "short message untouched",
+			maxWidth: 80,
+			input:    "GetCartAsync called with userId={userId}",
+			expected: "GetCartAsync called with userId={userId}",
+		},
+		{
+			name:     "multibyte runes counted once",
+			maxWidth: 5,
+			noColor:  true,
+			input:    "héllo wörld",
+			expected: "héll…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := newHighlighter(tt.contains, tt.regex, tt.extra, tt.colors, tt.maxWidth)
+			if err != nil {
+				t.Fatalf("newHighlighter() error: %v", err)
+			}
+			got := h.render(tt.input, tt.noColor)
+			if got != tt.expected {
+				t.Errorf("render() = %q, want %q", got, tt.expected)
+			}
+			if tt.noColor && tt.maxWidth > 0 && utf8.RuneCountInString(got) > tt.maxWidth {
+				t.Errorf("render() is %d runes, exceeds --max-width %d", utf8.RuneCountInString(got), tt.maxWidth)
+			}
+		})
+	}
+}
+
+func TestHighlighterNil(t *testing.T) {
+	var h *highlighter
+	if got := h.render("unchanged", false); got != "unchanged" {
+		t.Errorf("nil highlighter render() = %q", got)
+	}
+}
+
+func TestNewHighlighterErrors(t *testing.T) {
+	if _, err := newHighlighter("", "(", nil, nil, 0); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if _, err := newHighlighter("x", "", nil, []string{"chartreuse"}, 0); err == nil {
+		t.Error("expected error for unknown color")
+	}
+	if _, err := newHighlighter("x", "", nil, nil, -1); err == nil {
+		t.Error("expected error for negative width")
+	}
+}
+
+func TestFormatTextEntryHighlight(t *testing.T) {
+	entry := mockLogEntries[1]
+	h, err := newHighlighter("ErrorCode", "", nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := formatTextEntry(entry.message, entry.tags, []string{"level", "message"}, false, h)
+	if !strings.Contains(got, colorBold+colorRed+"ErrorCode"+colorReset) {
+		t.Errorf("formatTextEntry() did not highlight match: %q", got)
+	}
+	got = formatTextEntry(entry.message, entry.tags, nil, true, h)
+	if strings.Contains(got, "\033[") {
+		t.Errorf("formatTextEntry() with noColor contains escapes: %q", got)
+	}
+}
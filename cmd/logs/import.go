@@ -0,0 +1,607 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/lakerunner/cli/internal/lineformat"
+	"github.com/lakerunner/cli/internal/otlp"
+	"github.com/lakerunner/cli/internal/timerange"
+)
+
+var (
+	importFormat         string
+	importTimestampRegex string
+	importTimeLayout     string
+	importTZ             string
+	importService        string
+	importResource       []string
+	importTo             string
+	importS3Endpoint     string
+	importObjectFormat   string
+	importOrg            string
+	importCollector      string
+	importBatchSize      int
+	importCheckpoint     string
+	importRestart        bool
+	importDryRun         bool
+)
+
+var ImportCmd = &cobra.Command{
+	Use:   "import [flags] path...",
+	Short: "Import archived log files into the lake",
+	Long: `Parse log files and upload them in batches to the bucket Lakerunner ingests
+from, so logs from before Lakerunner can be queried like any other.
+
+Supported formats, detected from the first lines of each file by default:
+  ndjson    JSON objects; time, level, message and service fields are
+            recognised under their common names, the rest become attributes
+  logfmt    key=value pairs, e.g. ts=... level=info msg="..."
+  syslog    RFC 5424, or BSD (RFC 3164) with or without a priority
+  combined  Apache/nginx common and combined access logs
+  text      lines starting with a timestamp (--timestamp-regex); lines
+            without one, such as stack traces, continue the previous entry
+
+Files may be gzipped; directories are imported recursively. Progress is
+recorded in a checkpoint file after every uploaded batch, so an interrupted
+import picks up where it stopped, and finished files are skipped when the
+command is run again. --dry-run parses everything and prints what would be
+imported without uploading.`,
+	Example: `  lakerunner import --dry-run /var/log/archive
+  lakerunner import --to s3://lakerunner --s3-endpoint http://localhost:9000 --service nginx access.log.*.gz
+  lakerunner import --to s3://lakerunner --format text --timestamp-regex '^(?P<ts>\S+ \S+) (?P<level>\w+)' --time-layout '2006/01/02 15:04:05' app.log`,
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         runImportCmd,
+	SilenceUsage: true,
+}
+
+func init() {
+	ImportCmd.Flags().StringVar(&importFormat, "format", "auto", "Input format: auto, "+strings.Join(lineformat.Formats, ", "))
+	ImportCmd.Flags().StringVar(&importTimestampRegex, "timestamp-regex", "", "Regex for the timestamp of text lines, with an optional ts group (and level and msg groups)")
+	ImportCmd.Flags().StringVar(&importTimeLayout, "time-layout", "", "Go time layout of text timestamps, e.g. '02/01/2006 15:04:05' (default: common layouts)")
+	ImportCmd.Flags().StringVar(&importTZ, "tz", "Local", "Time zone of timestamps that do not carry one, e.g. UTC or Europe/Berlin")
+	ImportCmd.Flags().StringVar(&importService, "service", "", "service.name of entries that do not carry one")
+	ImportCmd.Flags().StringArrayVar(&importResource, "resource", nil, "Resource attribute as key=value (repeatable)")
+	ImportCmd.Flags().StringVar(&importTo, "to", "", "Destination s3://bucket/prefix (prefix defaults to logs-raw/)")
+	ImportCmd.Flags().StringVar(&importS3Endpoint, "s3-endpoint", "", "S3 endpoint, e.g. http://localhost:9000 for MinIO (default $AWS_ENDPOINT_URL or s3.amazonaws.com)")
+	ImportCmd.Flags().StringVar(&importObjectFormat, "object-format", "otlp", "Object format: otlp (gzipped OTLP protobuf) or parquet")
+	ImportCmd.Flags().StringVar(&importOrg, "org", "", "Organization path segment of object keys")
+	ImportCmd.Flags().StringVar(&importCollector, "collector", "lakerunner-import", "Collector path segment of object keys")
+	ImportCmd.Flags().IntVar(&importBatchSize, "batch-size", 10000, "Entries per uploaded object")
+	ImportCmd.Flags().StringVar(&importCheckpoint, "checkpoint", "", "Checkpoint file (default ~/.lakerunner/import-checkpoints.json)")
+	ImportCmd.Flags().BoolVar(&importRestart, "restart", false, "Ignore the checkpoint and import every file from the start")
+	ImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Parse the files and print a summary without uploading")
+}
+
+// fileCheckpoint records how far a file has been imported to a destination.
+type fileCheckpoint struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	// Offset is where the first entry not yet uploaded starts, in the
+	// decompressed stream.
+	Offset  int64 `json:"offset"`
+	Entries int   `json:"entries"`
+	Done    bool  `json:"done"`
+}
+
+// importCheckpoints is the checkpoint file, keyed by destination and
+// absolute path.
+type importCheckpoints struct {
+	path  string
+	Files map[string]fileCheckpoint `json:"files"`
+}
+
+func defaultCheckpointPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".lakerunner", "import-checkpoints.json"), nil
+}
+
+func loadCheckpoints(path string) (*importCheckpoints, error) {
+	cp := &importCheckpoints{path: path, Files: map[string]fileCheckpoint{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file %s: %w", path, err)
+	}
+	if cp.Files == nil {
+		cp.Files = map[string]fileCheckpoint{}
+	}
+	return cp, nil
+}
+
+// save writes the checkpoints atomically.
+func (cp *importCheckpoints) save() error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	tmp := cp.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmp, cp.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// importStats summarises one file.
+type importStats struct {
+	path      string
+	format    string
+	entries   int
+	skipped   int
+	untimed   int
+	first     time.Time
+	last      time.Time
+	levels    map[string]int
+	services  map[string]int
+	example   *lineformat.Entry
+	resumedAt int64
+	// done is set for files skipped because an earlier run imported them.
+	done bool
+}
+
+func (s *importStats) add(e lineformat.Entry) {
+	s.entries++
+	if s.first.IsZero() || e.Time.Before(s.first) {
+		s.first = e.Time
+	}
+	if e.Time.After(s.last) {
+		s.last = e.Time
+	}
+	s.levels[e.Level]++
+	s.services[e.Service]++
+	if s.example == nil {
+		s.example = &e
+	}
+}
+
+// countingReader counts the bytes read from a file, for progress.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// importFiles expands directories into the regular, non-hidden files below
+// them.
+func importFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// toLogRecord converts a parsed entry to an OTLP record, attributes sorted
+// by key.
+func toLogRecord(e lineformat.Entry) otlp.LogRecord {
+	rec := otlp.LogRecord{Time: e.Time, SeverityText: e.Level, Body: e.Body}
+	for _, k := range slices.Sorted(maps.Keys(e.Attributes)) {
+		rec.Attributes = append(rec.Attributes, otlp.KeyValue{Key: k, Value: e.Attributes[k]})
+	}
+	return rec
+}
+
+// importer imports files to one sink, or only parses them on a dry run.
+type importer struct {
+	format      string
+	opts        lineformat.Options
+	service     string
+	batchSize   int
+	sink        logSink
+	resource    [][2]string
+	checkpoints *importCheckpoints
+	destination string
+	progress    func(path string, done, total int64, entries int)
+}
+
+// importFile parses path and, unless this is a dry run (sink is nil),
+// uploads its entries, checkpointing after every batch.
+func (im *importer) importFile(ctx context.Context, path string) (*importStats, error) {
+	stats := &importStats{path: path, levels: map[string]int{}, services: map[string]int{}}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	key := im.destination + " " + abs
+	var cp fileCheckpoint
+	if im.checkpoints != nil {
+		cp = im.checkpoints.Files[key]
+		if cp.Size != info.Size() || !cp.ModTime.Equal(info.ModTime()) {
+			cp = fileCheckpoint{Size: info.Size(), ModTime: info.ModTime()}
+		}
+		if cp.Done {
+			stats.done, stats.entries = true, cp.Entries
+			return stats, nil
+		}
+		stats.resumedAt = cp.Offset
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	counter := &countingReader{r: f}
+	var r io.Reader = counter
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(counter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+	reader := bufio.NewReaderSize(r, 64*1024)
+	if _, err := io.CopyN(io.Discard, reader, cp.Offset); err != nil {
+		return nil, fmt.Errorf("failed to resume %s at byte %d: %w", path, cp.Offset, err)
+	}
+
+	// Read the first lines ahead to detect the format.
+	offset := cp.Offset
+	var sample []string
+	var readErr error
+	readLine := func() (string, int64, bool) {
+		raw, err := reader.ReadString('\n')
+		if err != nil {
+			readErr = err
+			if raw == "" {
+				return "", 0, false
+			}
+		}
+		return strings.TrimRight(raw, "\r\n"), int64(len(raw)), true
+	}
+	type rawLine struct {
+		text string
+		size int64
+	}
+	var ahead []rawLine
+	for len(sample) < 50 && readErr == nil {
+		text, size, ok := readLine()
+		if !ok {
+			break
+		}
+		ahead = append(ahead, rawLine{text, size})
+		if strings.TrimSpace(text) != "" {
+			sample = append(sample, text)
+		}
+	}
+	format := im.format
+	if format == "auto" {
+		format = lineformat.Detect(sample, im.opts)
+	}
+	parser, err := lineformat.New(format, im.opts)
+	if err != nil {
+		return nil, err
+	}
+	stats.format = format
+
+	var b *sendBatcher
+	if im.sink != nil {
+		b = &sendBatcher{sink: im.sink, resource: im.resource, size: im.batchSize}
+	}
+	resumedEntries := cp.Entries
+	fallback := info.ModTime()
+	var pending *lineformat.Entry
+	var pendingEnd int64
+	emit := func() error {
+		if pending == nil {
+			return nil
+		}
+		e := *pending
+		pending = nil
+		if e.Time.IsZero() {
+			e.Time = fallback
+			stats.untimed++
+		}
+		fallback = e.Time
+		if e.Service == "" {
+			e.Service = im.service
+		}
+		stats.add(e)
+		if b == nil {
+			return nil
+		}
+		if err := b.add(ctx, toLogRecord(e), e.Service); err != nil {
+			return err
+		}
+		if b.pending == 0 {
+			cp.Offset, cp.Entries = pendingEnd, resumedEntries+stats.entries
+			return im.saveCheckpoint(key, cp)
+		}
+		return nil
+	}
+
+	lastProgress := time.Now()
+	for i := 0; ; i++ {
+		var text string
+		var size int64
+		if i < len(ahead) {
+			text, size = ahead[i].text, ahead[i].size
+		} else {
+			var ok bool
+			if text, size, ok = readLine(); !ok {
+				break
+			}
+		}
+		lineEnd := offset + size
+		offset = lineEnd
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		e, ok := parser.Parse(text)
+		if !ok {
+			if parser.Multiline() && pending != nil {
+				pending.Body += "\n" + text
+				pendingEnd = lineEnd
+			} else {
+				stats.skipped++
+			}
+			continue
+		}
+		if err := emit(); err != nil {
+			return nil, err
+		}
+		pending, pendingEnd = &e, lineEnd
+		if im.progress != nil && time.Since(lastProgress) > 200*time.Millisecond {
+			im.progress(path, counter.n, info.Size(), stats.entries)
+			lastProgress = time.Now()
+		}
+	}
+	if readErr != nil && readErr != io.EOF {
+		return nil, fmt.Errorf("failed to read %s: %w", path, readErr)
+	}
+	if err := emit(); err != nil {
+		return nil, err
+	}
+	if b != nil {
+		if err := b.flush(ctx); err != nil {
+			return nil, err
+		}
+		cp.Offset, cp.Entries, cp.Done = offset, resumedEntries+stats.entries, true
+		if err := im.saveCheckpoint(key, cp); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (im *importer) saveCheckpoint(key string, cp fileCheckpoint) error {
+	if im.checkpoints == nil {
+		return nil
+	}
+	im.checkpoints.Files[key] = cp
+	return im.checkpoints.save()
+}
+
+// formatCounts renders counts as "a 3, b 1", largest first.
+func formatCounts(counts map[string]int) string {
+	keys := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	var parts []string
+	for _, k := range keys {
+		name := k
+		if name == "" {
+			name = "(none)"
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", name, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printImportStats(w io.Writer, s *importStats) {
+	if s.done {
+		fmt.Fprintf(w, "%s: already imported (%d entries)\n", s.path, s.entries)
+		return
+	}
+	fmt.Fprintf(w, "%s: %s, %d entries", s.path, s.format, s.entries)
+	if s.resumedAt > 0 {
+		fmt.Fprintf(w, " after byte %d", s.resumedAt)
+	}
+	fmt.Fprintln(w)
+	if s.entries > 0 {
+		fmt.Fprintf(w, "  time:     %s .. %s\n", s.first.Format(time.RFC3339), s.last.Format(time.RFC3339))
+		fmt.Fprintf(w, "  levels:   %s\n", formatCounts(s.levels))
+		fmt.Fprintf(w, "  services: %s\n", formatCounts(s.services))
+	}
+	if s.skipped > 0 {
+		fmt.Fprintf(w, "  skipped:  %d lines not in %s format\n", s.skipped, s.format)
+	}
+	if s.untimed > 0 {
+		fmt.Fprintf(w, "  untimed:  %d entries stamped with the previous entry's time\n", s.untimed)
+	}
+	if e := s.example; e != nil {
+		body := e.Body
+		if i := strings.IndexByte(body, '\n'); i >= 0 {
+			body = body[:i] + " …"
+		}
+		if len(body) > 80 {
+			body = body[:77] + "..."
+		}
+		fmt.Fprintf(w, "  example:  %s %s %s %q", e.Time.Format(time.RFC3339Nano), e.Level, e.Service, body)
+		if len(e.Attributes) > 0 {
+			fmt.Fprintf(w, " %s", strings.Join(slices.Sorted(maps.Keys(e.Attributes)), ","))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func runImportCmd(cmdObj *cobra.Command, args []string) error {
+	if importFormat != "auto" && !slices.Contains(lineformat.Formats, importFormat) {
+		return fmt.Errorf("invalid --format %q: expected auto, %s", importFormat, strings.Join(lineformat.Formats, ", "))
+	}
+	if importBatchSize <= 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+	loc, err := timerange.LoadLocation(importTZ)
+	if err != nil {
+		return err
+	}
+	resource, err := parsePairs("resource", importResource)
+	if err != nil {
+		return err
+	}
+	files, err := importFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files found in %s", strings.Join(args, ", "))
+	}
+
+	im := &importer{
+		format:    importFormat,
+		opts:      lineformat.Options{TimestampRegex: importTimestampRegex, TimeLayout: importTimeLayout, Location: loc},
+		service:   importService,
+		batchSize: importBatchSize,
+		resource:  resource,
+	}
+	if !importDryRun {
+		if importTo == "" {
+			return fmt.Errorf("--to is required unless --dry-run is given")
+		}
+		insecure, _ := cmdObj.Flags().GetBool("insecure")
+		sink, destination, err := newBucketSink(importTo, importS3Endpoint, importObjectFormat, importOrg, importCollector, insecure)
+		if err != nil {
+			return err
+		}
+		im.sink, im.destination = sink, destination
+
+		path := importCheckpoint
+		if path == "" {
+			if path, err = defaultCheckpointPath(); err != nil {
+				return err
+			}
+		}
+		if im.checkpoints, err = loadCheckpoints(path); err != nil {
+			return err
+		}
+		if importRestart {
+			for k := range im.checkpoints.Files {
+				if strings.HasPrefix(k, destination+" ") {
+					delete(im.checkpoints.Files, k)
+				}
+			}
+		}
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	interactive := !quiet && term.IsTerminal(int(os.Stderr.Fd()))
+	if interactive {
+		im.progress = func(path string, done, total int64, entries int) {
+			percent := 100
+			if total > 0 {
+				percent = int(done * 100 / total)
+			}
+			fmt.Fprintf(os.Stderr, "\r\033[K%s: %d%%, %d entries", path, percent, entries)
+		}
+	}
+
+	ctx := cmdObj.Context()
+	var total, skipped int
+	for i, path := range files {
+		stats, err := im.importFile(ctx, path)
+		if interactive {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+		if err != nil {
+			return err
+		}
+		if !stats.done {
+			total += stats.entries
+		}
+		skipped += stats.skipped
+		if importDryRun {
+			printImportStats(os.Stdout, stats)
+		} else if !quiet {
+			fmt.Fprintf(os.Stderr, "[%d/%d] ", i+1, len(files))
+			printImportStats(os.Stderr, stats)
+		}
+	}
+
+	if importDryRun {
+		objects := (total + importBatchSize - 1) / importBatchSize
+		fmt.Printf("\nWould import %d entries from %d files in about %d objects of up to %d entries", total, len(files), objects, importBatchSize)
+		if skipped > 0 {
+			fmt.Printf(", skipping %d unparsed lines", skipped)
+		}
+		fmt.Println()
+		return nil
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Imported %d entries from %d files to %s\n", total, len(files), im.destination)
+	}
+	return nil
+}
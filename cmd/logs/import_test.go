@@ -0,0 +1,196 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/lineformat"
+	"github.com/lakerunner/cli/internal/otlp"
+)
+
+const importText = `2026-10-15 14:00:00 INFO starting
+2026-10-15 14:00:01 ERROR request failed
+java.lang.IllegalStateException: boom
+	at com.example.Handler.run(Handler.java:42)
+2026-10-15 14:00:02 WARN retrying
+2026-10-15 14:00:03 INFO done
+2026-10-15 14:00:04 INFO idle
+`
+
+// failingSink accepts limit batches, then fails like an interrupted upload.
+type failingSink struct {
+	fakeSink
+	limit int
+}
+
+func (s *failingSink) send(ctx context.Context, logs []otlp.ResourceLogs) error {
+	if len(s.batches) == s.limit {
+		return errors.New("connection reset")
+	}
+	return s.fakeSink.send(ctx, logs)
+}
+
+func sentBodies(batches [][]otlp.ResourceLogs) []string {
+	var bodies []string
+	for _, batch := range batches {
+		for _, rl := range batch {
+			for _, rec := range rl.Records {
+				bodies = append(bodies, rec.Body)
+			}
+		}
+	}
+	return bodies
+}
+
+func newTestImporter(sink logSink, checkpoints *importCheckpoints) *importer {
+	return &importer{
+		format:      "auto",
+		opts:        lineformat.Options{Location: time.UTC},
+		service:     "app",
+		batchSize:   2,
+		sink:        sink,
+		checkpoints: checkpoints,
+		destination: "s3://bucket/logs-raw/",
+	}
+}
+
+func TestImportFileResume(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte(importText), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkpoints := &importCheckpoints{path: filepath.Join(dir, "checkpoints.json"), Files: map[string]fileCheckpoint{}}
+
+	interrupted := &failingSink{limit: 1}
+	if _, err := newTestImporter(interrupted, checkpoints).importFile(context.Background(), path); err == nil {
+		t.Fatal("expected the second batch to fail")
+	}
+	if got := sentBodies(interrupted.batches); !slices.Equal(got, []string{"2026-10-15 14:00:00 INFO starting", "2026-10-15 14:00:01 ERROR request failed\njava.lang.IllegalStateException: boom\n\tat com.example.Handler.run(Handler.java:42)"}) {
+		t.Fatalf("first batch = %q", got)
+	}
+
+	if info, err := os.Stat(checkpoints.path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Errorf("checkpoint file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// A fresh run reads the checkpoint from disk and continues after the
+	// first batch.
+	checkpoints, err := loadCheckpoints(checkpoints.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := &fakeSink{}
+	stats, err := newTestImporter(resumed, checkpoints).importFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sentBodies(resumed.batches); !slices.Equal(got, []string{"2026-10-15 14:00:02 WARN retrying", "2026-10-15 14:00:03 INFO done", "2026-10-15 14:00:04 INFO idle"}) {
+		t.Errorf("resumed import sent %q", got)
+	}
+	if stats.format != "text" || stats.entries != 3 || stats.resumedAt == 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	again := &fakeSink{}
+	stats, err = newTestImporter(again, checkpoints).importFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.done || stats.entries != 5 || len(again.batches) != 0 {
+		t.Errorf("finished file imported again: %+v, %d batches", stats, len(again.batches))
+	}
+
+	// A changed file starts over.
+	if err := os.WriteFile(path, []byte(importText+"2026-10-15 14:00:05 INFO more\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed := &fakeSink{}
+	if _, err := newTestImporter(changed, checkpoints).importFile(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+	if got := sentBodies(changed.batches); len(got) != 6 {
+		t.Errorf("changed file sent %q", got)
+	}
+}
+
+func TestImportFileDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	_, _ = gz.Write([]byte(strings.Join([]string{
+		`ts=2026-10-15T14:00:00Z level=info msg="listening" port=8080 service=api`,
+		`not logfmt at all`,
+		`level=error msg="bind failed"`,
+	}, "\n")))
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := newTestImporter(nil, nil).importFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.format != "logfmt" || stats.entries != 2 || stats.skipped != 1 || stats.untimed != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats.services["api"] != 1 || stats.services["app"] != 1 || stats.levels["ERROR"] != 1 {
+		t.Errorf("services %v, levels %v", stats.services, stats.levels)
+	}
+	if !stats.last.Equal(time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("untimed entry got %s, want the previous entry's time", stats.last)
+	}
+	if stats.example == nil || stats.example.Attributes["port"] != "8080" {
+		t.Errorf("example = %+v", stats.example)
+	}
+}
+
+func TestImportFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.log", "a/x.log.gz", ".hidden", ".git/config"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := importFiles([]string{dir, filepath.Join(dir, "b.log")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "a/x.log.gz"), filepath.Join(dir, "b.log")}
+	if !slices.Equal(got, want) {
+		t.Errorf("importFiles = %q, want %q", got, want)
+	}
+}
@@ -20,11 +20,15 @@ import (
 
 var LogsCmd = &cobra.Command{
 	Use:   "logs",
-	Short: "Commands for querying logs",
+	Short: "Commands for querying and sending logs",
 }
 
 func init() {
 	LogsCmd.AddCommand(GetCmd)
 	LogsCmd.AddCommand(AttributesCmd)
 	LogsCmd.AddCommand(TagValuesCmd)
+	LogsCmd.AddCommand(PatternsCmd)
+	LogsCmd.AddCommand(DiffCmd)
+	LogsCmd.AddCommand(AnomaliesCmd)
+	LogsCmd.AddCommand(SendCmd)
 }
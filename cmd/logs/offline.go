@@ -0,0 +1,228 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/direct"
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/lakerunner/cli/internal/logparse"
+	"github.com/lakerunner/cli/internal/logql"
+)
+
+// localQuery is a logs query evaluated client-side, against a saved export
+// or segments read directly, the way the API would answer it.
+type localQuery struct {
+	q       string
+	startMs int64
+	endMs   int64
+	// timeRange drops entries outside [startMs, endMs).
+	timeRange bool
+	reverse   bool
+	// limit caps the matches returned, as the API does; 0 means no cap.
+	limit int
+	// stages and where run on entries the query matched, before they count
+	// towards limit, as they would on the server.
+	stages []logparse.Stage
+	where  []logparse.Filter
+}
+
+// match reports whether the entry in data is a result, and its timestamp.
+func (lq localQuery) match(query *logql.Query, data map[string]any) (int64, bool) {
+	tags, _ := data["tags"].(map[string]any)
+	if !query.Match(tags) {
+		return 0, false
+	}
+	ns, ok := entryTimestampNs(data)
+	if lq.timeRange {
+		if ms := ns / 1e6; !ok || ms < lq.startMs || ms >= lq.endMs {
+			return 0, false
+		}
+	}
+	if len(lq.stages) > 0 || len(lq.where) > 0 {
+		if !applyClientPipeline(tags, lq.stages, lq.where) {
+			return 0, false
+		}
+	}
+	return ns, true
+}
+
+// readLogFile evaluates lq against the entries saved in path and returns the
+// matches in the order the API would stream them. Only the first lq.limit
+// matches in that order are held while the file is read.
+func readLogFile(path string, lq localQuery) (<-chan api.LogsResponse, error) {
+	query, err := logql.Parse(lq.q)
+	if err != nil {
+		return nil, err
+	}
+	top := newTopMatches(lq.limit, lq.reverse)
+	err = logfile.Read(path, func(data map[string]any) error {
+		if ns, ok := lq.match(query, data); ok {
+			top.add(ns, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return top.responses(), nil
+}
+
+// readDirect evaluates lq against the log segments stored below loc for the
+// time range, skipping the row groups that cannot match on service, level or
+// timestamp. Hour partitions are read in stream order and their matches
+// streamed as each hour completes, stopping once lq.limit have been sent. The
+// returned function reports an error that ended the stream early.
+func readDirect(ctx context.Context, store direct.Store, loc direct.Location, lq localQuery) (<-chan api.LogsResponse, func() error, error) {
+	query, err := logql.Parse(lq.q)
+	if err != nil {
+		return nil, nil, err
+	}
+	segments, err := direct.Segments(ctx, store, loc.Prefix, time.UnixMilli(lq.startMs), time.UnixMilli(lq.endMs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list segments in %s: %w", loc, err)
+	}
+	pred := &logfile.Predicate{
+		StartNs: lq.startMs * int64(time.Millisecond),
+		EndNs:   lq.endMs * int64(time.Millisecond),
+		Values:  make(map[string][]string),
+	}
+	for _, label := range []string{"service", "level"} {
+		if values, ok := query.Values(label); ok {
+			pred.Values[label] = values
+		}
+	}
+
+	responseChan := make(chan api.LogsResponse)
+	var readErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(responseChan)
+		sent := 0
+		for _, hour := range direct.ByHour(segments, lq.reverse) {
+			top := newTopMatches(lq.limit-sent, lq.reverse)
+			err := direct.Read(ctx, store, hour, pred, func(data map[string]any) error {
+				if ns, ok := lq.match(query, data); ok {
+					top.add(ns, data)
+				}
+				return nil
+			})
+			if err != nil {
+				readErr = err
+				return
+			}
+			for _, m := range top.sorted() {
+				select {
+				case responseChan <- api.LogsResponse{Type: "event", Data: m.data}:
+					sent++
+				case <-ctx.Done():
+					return
+				}
+			}
+			if lq.limit > 0 && sent >= lq.limit {
+				return
+			}
+		}
+	}()
+	return responseChan, func() error {
+		<-done
+		return readErr
+	}, nil
+}
+
+// localMatch is a match waiting to be streamed. seq keeps read order among
+// entries with the same timestamp.
+type localMatch struct {
+	ns   int64
+	seq  int
+	data map[string]any
+}
+
+// topMatches keeps the first limit matches in stream order (newest first when
+// reverse), or all of them when limit is 0. It is a heap whose root is the
+// kept match that streams last, the one a better match replaces.
+type topMatches struct {
+	matches []localMatch
+	limit   int
+	reverse bool
+	seq     int
+}
+
+func newTopMatches(limit int, reverse bool) *topMatches {
+	return &topMatches{limit: limit, reverse: reverse}
+}
+
+// before reports whether a streams before b.
+func (t *topMatches) before(a, b localMatch) bool {
+	if a.ns != b.ns {
+		return a.ns > b.ns == t.reverse
+	}
+	return a.seq < b.seq
+}
+
+func (t *topMatches) Len() int           { return len(t.matches) }
+func (t *topMatches) Less(i, j int) bool { return t.before(t.matches[j], t.matches[i]) }
+func (t *topMatches) Swap(i, j int)      { t.matches[i], t.matches[j] = t.matches[j], t.matches[i] }
+func (t *topMatches) Push(x any)         { t.matches = append(t.matches, x.(localMatch)) }
+func (t *topMatches) Pop() any {
+	last := t.matches[len(t.matches)-1]
+	t.matches = t.matches[:len(t.matches)-1]
+	return last
+}
+
+// add offers a match, dropping it or the kept match that streams last once
+// limit are held.
+func (t *topMatches) add(ns int64, data map[string]any) {
+	m := localMatch{ns: ns, seq: t.seq, data: data}
+	t.seq++
+	switch {
+	case t.limit <= 0 || len(t.matches) < t.limit:
+		heap.Push(t, m)
+	case t.before(m, t.matches[0]):
+		t.matches[0] = m
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the kept matches in stream order and empties t.
+func (t *topMatches) sorted() []localMatch {
+	matches := t.matches
+	t.matches = nil
+	slices.SortFunc(matches, func(a, b localMatch) int {
+		if t.before(a, b) {
+			return -1
+		}
+		return 1
+	})
+	return matches
+}
+
+// responses returns the kept matches as a closed stream of events.
+func (t *topMatches) responses() <-chan api.LogsResponse {
+	matches := t.sorted()
+	responseChan := make(chan api.LogsResponse, len(matches))
+	for _, m := range matches {
+		responseChan <- api.LogsResponse{Type: "event", Data: m.data}
+	}
+	close(responseChan)
+	return responseChan
+}
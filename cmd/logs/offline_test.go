@@ -0,0 +1,99 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lakerunner/cli/internal/logparse"
+)
+
+const exportFixture = "testdata/export.ndjson"
+
+func readFixtureIDs(t *testing.T, lq localQuery) []string {
+	t.Helper()
+	ch, err := readLogFile(exportFixture, lq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for r := range ch {
+		tags := r.Data["tags"].(map[string]any)
+		got = append(got, tags["message"].(string))
+	}
+	return got
+}
+
+func TestReadLogFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"all", buildLogQLQuery("", "", nil, "", "", "", ""), []string{
+			"GET /index.html 200", `{"status":503,"path":"/api/cart"}`, "slow query took 1200ms", "GET /health 200", "payment timeout after 3 retries",
+		}},
+		{"app and level", buildLogQLQuery("checkout", "ERROR", nil, "", "", "", ""), []string{"payment timeout after 3 retries"}},
+		{"several apps", buildLogQLQuery("cart,web", "INFO", nil, "", "", "", ""), []string{"GET /index.html 200"}},
+		{"filter", buildLogQLQuery("", "", []string{"k8s_pod_name:cart-1a2"}, "", "", "", ""), []string{`{"status":503,"path":"/api/cart"}`, "slow query took 1200ms"}},
+		{"contains", buildLogQLQuery("", "", nil, "GET", "health", "", ""), []string{"GET /index.html 200"}},
+		{"regex", buildLogQLQuery("", "", nil, "", "", `took \d+ms`, ""), []string{"slow query took 1200ms"}},
+		{"not regex", buildLogQLQuery("checkout", "", nil, "", "", "", `(?i)HEALTH`), []string{"payment timeout after 3 retries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readFixtureIDs(t, localQuery{q: tt.query, reverse: true}); !slices.Equal(got, tt.want) {
+				t.Errorf("%s matched %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLogFileOrderAndRange(t *testing.T) {
+	q := buildLogQLQuery("", "", nil, "", "", "", "")
+	got := readFixtureIDs(t, localQuery{q: q, startMs: 1760536805000, endMs: 1760536815000, timeRange: true})
+	want := []string{"GET /health 200", "slow query took 1200ms"}
+	if !slices.Equal(got, want) {
+		t.Errorf("oldest first within range = %q, want %q", got, want)
+	}
+}
+
+func TestReadLogFileLimit(t *testing.T) {
+	q := buildLogQLQuery("", "", nil, "", "", "", "")
+	tests := []struct {
+		name string
+		lq   localQuery
+		want []string
+	}{
+		{"newest", localQuery{q: q, reverse: true, limit: 2}, []string{"GET /index.html 200", `{"status":503,"path":"/api/cart"}`}},
+		{"oldest", localQuery{q: q, limit: 2}, []string{"payment timeout after 3 retries", "GET /health 200"}},
+		// --where runs before the limit, as it would on the server.
+		{"where", localQuery{q: q, reverse: true, limit: 1, where: []logparse.Filter{{Key: "level", Op: "=", Value: "ERROR"}}}, []string{`{"status":503,"path":"/api/cart"}`}},
+	}
+	for _, tt := range tests {
+		if got := readFixtureIDs(t, tt.lq); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadLogFileUnsupportedQuery(t *testing.T) {
+	_, err := readLogFile(exportFixture, localQuery{q: `sum(count_over_time({service="cart"}[5m]))`, reverse: true})
+	if err == nil || !strings.Contains(err.Error(), "expected '{'") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
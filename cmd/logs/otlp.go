@@ -0,0 +1,247 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/otlp"
+)
+
+// getScope names the CLI as the instrumentation scope of replayed logs.
+const getScope = "lakerunner-cli/logs-get"
+
+// Semantic convention namespaces. Tags in these namespaces had their dots
+// replaced by underscores (see fieldnames.Label), which otlpAttributeName undoes;
+// other tags are passed through as they are. Tags in resourceNamespaces
+// describe the resource rather than the record.
+var (
+	resourceNamespaces = map[string]bool{
+		"service": true, "k8s": true, "host": true, "container": true, "cloud": true,
+		"deployment": true, "process": true, "os": true, "telemetry": true, "faas": true, "device": true,
+	}
+	attributeNamespaces = map[string]bool{
+		"http": true, "url": true, "client": true, "server": true, "network": true, "user_agent": true,
+		"error": true, "exception": true, "code": true, "db": true, "rpc": true, "messaging": true,
+		"log": true, "event": true, "thread": true, "source": true, "destination": true, "session": true,
+		"enduser": true, "user": true, "peer": true, "net": true, "syslog": true,
+	}
+	// compoundSegments are attribute name segments that contain an underscore.
+	compoundSegments = map[string]bool{
+		"user_agent": true, "status_code": true, "availability_zone": true, "resource_id": true,
+		"command_line": true, "instance_id": true, "trace_id": true, "span_id": true,
+	}
+	// Tags that hold the body, level and service under their various names.
+	bodyTags    = []string{"message", "_cardinalhq_message", "log_message"}
+	levelTags   = []string{"level", "_cardinalhq_level", "log_level"}
+	serviceTags = []string{"service", "resource_service_name", "resource.service.name"}
+)
+
+// otlpAttributeName returns the OTel attribute name of tag, undoing
+// fieldnames.Label for semantic convention names, and whether it is a resource
+// attribute. resource_ tags are always resource attributes.
+func otlpAttributeName(tag string) (string, bool) {
+	if name, ok := strings.CutPrefix(tag, "resource."); ok {
+		return name, true
+	}
+	if strings.Contains(tag, ".") {
+		namespace, _, _ := strings.Cut(tag, ".")
+		return tag, resourceNamespaces[namespace]
+	}
+	resource := false
+	if name, ok := strings.CutPrefix(tag, "resource_"); ok {
+		tag, resource = name, true
+	}
+	parts := strings.Split(tag, "_")
+	var segments []string
+	for i := 0; i < len(parts); i++ {
+		if i+1 < len(parts) && compoundSegments[parts[i]+"_"+parts[i+1]] {
+			segments = append(segments, parts[i]+"_"+parts[i+1])
+			i++
+			continue
+		}
+		segments = append(segments, parts[i])
+	}
+	namespace := segments[0]
+	if len(segments) == 1 || (!resourceNamespaces[namespace] && !attributeNamespaces[namespace]) {
+		return tag, resource
+	}
+	return strings.Join(segments, "."), resource || resourceNamespaces[namespace]
+}
+
+// otlpValue converts a tag value to an attribute value. JSON numbers that are
+// whole become integers.
+func otlpValue(v any) any {
+	switch tv := v.(type) {
+	case string, bool, int64:
+		return tv
+	case int:
+		return int64(tv)
+	case float64:
+		if tv == math.Trunc(tv) && math.Abs(tv) < 1<<53 {
+			return int64(tv)
+		}
+		return tv
+	}
+	return fmt.Sprint(v)
+}
+
+// takeTag removes and returns the first of keys present in tags.
+func takeTag(tags map[string]any, keys []string) string {
+	for _, k := range keys {
+		if v, ok := tags[k]; ok {
+			delete(tags, k)
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// otlpLogRecord converts a log entry from the query API to an OTLP record
+// and the attributes of its resource, both sorted by key.
+func otlpLogRecord(message map[string]any) (otlp.LogRecord, []otlp.KeyValue) {
+	tags := map[string]any{}
+	if t, ok := message["tags"].(map[string]any); ok {
+		maps.Copy(tags, t)
+	}
+	var rec otlp.LogRecord
+	if ns, ok := entryTimestampNs(message); ok {
+		rec.Time = time.Unix(0, ns)
+	}
+	rec.Body = takeTag(tags, bodyTags)
+	rec.SeverityText = strings.ToUpper(takeTag(tags, levelTags))
+	service := takeTag(tags, serviceTags)
+	if service == "" {
+		service = "unknown_service"
+	}
+	resource := []otlp.KeyValue{{Key: "service.name", Value: service}}
+
+	var attrs []otlp.KeyValue
+	for _, tag := range slices.Sorted(maps.Keys(tags)) {
+		v := tags[tag]
+		if v == nil || strings.HasPrefix(tag, "_cardinalhq") || strings.HasPrefix(tag, "chq_") {
+			continue
+		}
+		name, isResource := otlpAttributeName(tag)
+		switch {
+		case name == "trace_id" || name == "span_id":
+			id, err := hex.DecodeString(fmt.Sprint(v))
+			if err == nil && name == "trace_id" && len(id) == len(rec.TraceID) {
+				copy(rec.TraceID[:], id)
+				continue
+			}
+			if err == nil && name == "span_id" && len(id) == len(rec.SpanID) {
+				copy(rec.SpanID[:], id)
+				continue
+			}
+			attrs = append(attrs, otlp.KeyValue{Key: name, Value: otlpValue(v)})
+		case isResource:
+			resource = append(resource, otlp.KeyValue{Key: name, Value: otlpValue(v)})
+		default:
+			attrs = append(attrs, otlp.KeyValue{Key: name, Value: otlpValue(v)})
+		}
+	}
+	slices.SortFunc(resource[1:], func(a, b otlp.KeyValue) int { return strings.Compare(a.Key, b.Key) })
+	slices.SortFunc(attrs, func(a, b otlp.KeyValue) int { return strings.Compare(a.Key, b.Key) })
+	rec.Attributes = attrs
+	return rec, resource
+}
+
+// jsonFileSink writes each batch as one line of OTLP/JSON, the layout of the
+// collector's file exporter.
+type jsonFileSink struct {
+	w io.Writer
+}
+
+func (s *jsonFileSink) send(_ context.Context, logs []otlp.ResourceLogs) error {
+	data, err := otlp.MarshalLogsJSON(logs)
+	if err != nil {
+		return fmt.Errorf("failed to encode logs: %w", err)
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write logs: %w", err)
+	}
+	return nil
+}
+
+// otlpOutput batches entries from `logs get -o otlp` to a receiver or file.
+type otlpOutput struct {
+	batcher     *sendBatcher
+	destination string
+	file        io.Closer
+	closed      bool
+}
+
+func newOTLPOutput(endpoint, file string, headerFlags []string, batchSize int) (*otlpOutput, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("--otlp-batch-size must be positive")
+	}
+	out := &otlpOutput{}
+	var sink logSink
+	switch {
+	case endpoint != "":
+		headers, err := parsePairs("otlp-header", headerFlags)
+		if err != nil {
+			return nil, err
+		}
+		exporter := &otlp.HTTPExporter{Endpoint: endpoint, Headers: map[string]string{}, Retries: 5}
+		for _, h := range headers {
+			exporter.Headers[h[0]] = h[1]
+		}
+		sink, out.destination = &httpSink{exporter: exporter}, exporter.LogsURL()
+	case file == "-":
+		sink, out.destination = &jsonFileSink{w: os.Stdout}, "stdout"
+	case file != "":
+		f, err := os.Create(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file, err)
+		}
+		sink, out.destination, out.file = &jsonFileSink{w: f}, file, f
+	default:
+		return nil, fmt.Errorf("-o otlp requires --otlp-endpoint or --otlp-file")
+	}
+	out.batcher = &sendBatcher{sink: sink, size: batchSize, scope: getScope}
+	return out, nil
+}
+
+func (o *otlpOutput) add(ctx context.Context, message map[string]any) error {
+	rec, resource := otlpLogRecord(message)
+	return o.batcher.addResource(ctx, rec, resource)
+}
+
+// close sends the last batch and closes the file. Only the first call does
+// anything.
+func (o *otlpOutput) close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	err := o.batcher.flush(context.Background())
+	if o.file != nil {
+		if cerr := o.file.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to close %s: %w", o.destination, cerr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/otlp"
+)
+
+func TestOTLPAttributeName(t *testing.T) {
+	tests := []struct {
+		tag      string
+		name     string
+		resource bool
+	}{
+		{"k8s_pod_name", "k8s.pod.name", true},
+		{"k8s_namespace_name", "k8s.namespace.name", true},
+		{"resource_service_version", "service.version", true},
+		{"resource_installation", "installation", true},
+		{"resource.custom.thing", "custom.thing", true},
+		{"http_response_status_code", "http.response.status_code", false},
+		{"user_agent_original", "user_agent.original", false},
+		{"host.name", "host.name", true},
+		{"status_code", "status_code", false},
+		{"request_id", "request_id", false},
+		{"http", "http", false},
+	}
+	for _, tt := range tests {
+		name, resource := otlpAttributeName(tt.tag)
+		if name != tt.name || resource != tt.resource {
+			t.Errorf("otlpAttributeName(%q) = %q, %v; want %q, %v", tt.tag, name, resource, tt.name, tt.resource)
+		}
+	}
+}
+
+func TestOTLPLogRecord(t *testing.T) {
+	message := map[string]any{
+		"timestamp":    float64(1760536800000),
+		"timestamp_ns": int64(1760536800123456789),
+		"tags": map[string]any{
+			"service":                   "checkout",
+			"level":                     "error",
+			"message":                   "payment timeout",
+			"k8s_pod_name":              "checkout-7f9",
+			"http_response_status_code": float64(504),
+			"latency":                   1.5,
+			"trace_id":                  "0102030405060708090a0b0c0d0e0f10",
+			"_cardinalhq_fingerprint":   "123",
+		},
+	}
+	rec, resource := otlpLogRecord(message)
+	if !rec.Time.Equal(time.Unix(0, 1760536800123456789)) || rec.Body != "payment timeout" || rec.SeverityText != "ERROR" {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.TraceID[0] != 1 || rec.TraceID[15] != 0x10 {
+		t.Errorf("trace ID = %x", rec.TraceID)
+	}
+	wantResource := []otlp.KeyValue{{Key: "service.name", Value: "checkout"}, {Key: "k8s.pod.name", Value: "checkout-7f9"}}
+	if !slices.Equal(resource, wantResource) {
+		t.Errorf("resource = %v, want %v", resource, wantResource)
+	}
+	wantAttrs := []otlp.KeyValue{{Key: "http.response.status_code", Value: int64(504)}, {Key: "latency", Value: 1.5}}
+	if !slices.Equal(rec.Attributes, wantAttrs) {
+		t.Errorf("attributes = %v, want %v", rec.Attributes, wantAttrs)
+	}
+	if tags := message["tags"].(map[string]any); tags["message"] != "payment timeout" {
+		t.Error("otlpLogRecord modified the entry's tags")
+	}
+}
+
+func TestJSONFileSink(t *testing.T) {
+	var buf bytes.Buffer
+	b := &sendBatcher{sink: &jsonFileSink{w: &buf}, size: 2, scope: getScope}
+	for _, pod := range []string{"a", "b", "a"} {
+		rec, resource := otlpLogRecord(map[string]any{
+			"timestamp": float64(1760536800000),
+			"tags":      map[string]any{"service": "cart", "k8s_pod_name": pod, "message": "m"},
+		})
+		if err := b.addResource(context.Background(), rec, resource); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per batch:\n%s", len(lines), buf.String())
+	}
+	var first struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				Scope      struct{ Name string }
+				LogRecords []any
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if len(first.ResourceLogs) != 2 || first.ResourceLogs[0].ScopeLogs[0].Scope.Name != getScope {
+		t.Errorf("first batch = %s", lines[0])
+	}
+}
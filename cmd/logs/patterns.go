@@ -0,0 +1,251 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lakerunner/cli/internal/patterns"
+	"github.com/lakerunner/cli/internal/timerange"
+	"github.com/spf13/cobra"
+)
+
+var (
+	patternsQuery     queryFlags
+	patternsLimit     int
+	patternsTop       int
+	patternsOutput    string
+	patternsThreshold float64
+)
+
+var PatternsCmd = &cobra.Command{
+	Use:   "patterns",
+	Short: "Cluster matching log messages into patterns",
+	Long: `Stream matching log entries and group their messages into templates,
+masking numbers, UUIDs, IPs and hex IDs. Reports how often each pattern
+occurred, when it was first and last seen, which services logged it, and an
+example message.`,
+	RunE: runPatternsCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	PatternsCmd.Flags().IntVar(&patternsLimit, "limit", 10000, "Maximum number of log entries to scan")
+	PatternsCmd.Flags().IntVar(&patternsTop, "top", 20, "Show only the N most frequent patterns (0 = all)")
+	PatternsCmd.Flags().StringVarP(&patternsOutput, "output", "o", "text", "Output format: text, json")
+	PatternsCmd.Flags().Float64Var(&patternsThreshold, "threshold", patterns.DefaultThreshold, "Fraction of tokens that must match for a message to join a pattern")
+	patternsQuery.register(PatternsCmd)
+}
+
+// patternStats accumulates per-pattern details while entries stream in.
+type patternStats struct {
+	pattern  *patterns.Pattern
+	count    int64
+	first    time.Time
+	last     time.Time
+	services map[string]struct{}
+	example  string
+}
+
+// patternCollector clusters entries and tracks stats per pattern. Several
+// collectors may share one miner so that patterns line up across them.
+type patternCollector struct {
+	miner *patterns.Miner
+	stats map[*patterns.Pattern]*patternStats
+	total int64
+}
+
+func newPatternCollector(miner *patterns.Miner) *patternCollector {
+	return &patternCollector{miner: miner, stats: make(map[*patterns.Pattern]*patternStats)}
+}
+
+// add clusters one log entry.
+func (c *patternCollector) add(message map[string]any, tags map[string]any) {
+	msg, _ := tags["message"].(string)
+	p := c.miner.Add(msg)
+	st, ok := c.stats[p]
+	if !ok {
+		st = &patternStats{pattern: p, services: make(map[string]struct{}), example: msg}
+		c.stats[p] = st
+	}
+	st.count++
+	c.total++
+	if ns, ok := entryTimestampNs(message); ok {
+		ts := time.Unix(0, ns)
+		if st.first.IsZero() || ts.Before(st.first) {
+			st.first = ts
+		}
+		if ts.After(st.last) {
+			st.last = ts
+		}
+	}
+	if svc, ok := tags["service"].(string); ok && svc != "" {
+		st.services[svc] = struct{}{}
+	}
+}
+
+// patternReport is one row of `logs patterns` output.
+type patternReport struct {
+	Pattern   string    `json:"pattern"`
+	Count     int64     `json:"count"`
+	Percent   float64   `json:"percent"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Services  []string  `json:"services"`
+	Example   string    `json:"example"`
+}
+
+// report returns patterns ordered by count (then template), limited to top if > 0.
+func (c *patternCollector) report(top int) []patternReport {
+	var out []patternReport
+	for _, st := range c.stats {
+		services := make([]string, 0, len(st.services))
+		for svc := range st.services {
+			services = append(services, svc)
+		}
+		sort.Strings(services)
+		pct := 0.0
+		if c.total > 0 {
+			pct = float64(st.count) * 100 / float64(c.total)
+		}
+		out = append(out, patternReport{
+			Pattern:   st.pattern.Template(),
+			Count:     st.count,
+			Percent:   pct,
+			FirstSeen: st.first,
+			LastSeen:  st.last,
+			Services:  services,
+			Example:   st.example,
+		})
+	}
+	slices.SortFunc(out, func(a, b patternReport) int {
+		if a.Count != b.Count {
+			return int(b.Count - a.Count)
+		}
+		return strings.Compare(a.Pattern, b.Pattern)
+	})
+	if top > 0 && len(out) > top {
+		out = out[:top]
+	}
+	return out
+}
+
+func runPatternsCmd(cmdObj *cobra.Command, _ []string) error {
+	patternsOutput = strings.ToLower(patternsOutput)
+	if patternsOutput != "text" && patternsOutput != "json" {
+		return fmt.Errorf("invalid output format %q: must be one of text, json", patternsOutput)
+	}
+
+	client, _, err := newClientFromFlags(cmdObj)
+	if err != nil {
+		return err
+	}
+	start, end, err := patternsQuery.times.resolve()
+	if err != nil {
+		return err
+	}
+	q, err := patternsQuery.buildQuery()
+	if err != nil {
+		return err
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if patternsOutput == "json" {
+		quiet = true
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Clustering up to %d entries matching %s from %s...\n", patternsLimit, q, timerange.Describe(start, end, patternsQuery.times.location()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	responseChan, err := client.QueryLogs(ctx, q, fmt.Sprintf("%d", start.UnixMilli()), fmt.Sprintf("%d", end.UnixMilli()), patternsLimit, true, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query logs: %w", err)
+	}
+
+	collector := newPatternCollector(patterns.NewMiner(patternsThreshold))
+	for response := range responseChan {
+		tags, _ := response.Data["tags"].(map[string]any)
+		if tags == nil {
+			continue
+		}
+		collector.add(response.Data, tags)
+		if collector.total >= int64(patternsLimit) {
+			cancel()
+			break
+		}
+	}
+
+	rows := collector.report(patternsTop)
+	if patternsOutput == "json" {
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal patterns: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No log entries found for the specified criteria")
+		return nil
+	}
+	noColor, _ := cmdObj.Flags().GetBool("no-color")
+	printPatternTable(rows, len(collector.stats), collector.total, patternsQuery.times.location(), noColor)
+	return nil
+}
+
+// seenAt formats a first or last seen time for the table, or "-" when no
+// entry of the pattern carried a timestamp.
+func seenAt(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(loc).Format("2006-01-02 15:04:05")
+}
+
+// printPatternTable writes the text report, one pattern per row followed by its
+// example. clusters is the number of patterns found, which --top may cut rows to.
+func printPatternTable(rows []patternReport, clusters int, total int64, loc *time.Location, noColor bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "COUNT\tPCT\tFIRST SEEN\tLAST SEEN\tSERVICES\tPATTERN")
+	for _, r := range rows {
+		pattern := r.Pattern
+		if !noColor {
+			pattern = colorCyan + pattern + colorReset
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%.1f%%\t%s\t%s\t%s\t%s\n",
+			r.Count, r.Percent,
+			seenAt(r.FirstSeen, loc), seenAt(r.LastSeen, loc),
+			strings.Join(r.Services, ","), pattern)
+		_, _ = fmt.Fprintf(w, "\t\t\t\t\t  e.g. %s\n", r.Example)
+	}
+	_ = w.Flush()
+	if len(rows) < clusters {
+		fmt.Printf("---\n%d patterns from %d entries, showing the top %d\n", clusters, total, len(rows))
+		return
+	}
+	fmt.Printf("---\n%d patterns from %d entries\n", clusters, total)
+}
@@ -0,0 +1,67 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/patterns"
+)
+
+func TestPatternCollector(t *testing.T) {
+	c := newPatternCollector(patterns.NewMiner(0))
+	add := func(msg, service string, ns int64) {
+		c.add(map[string]any{"timestamp_ns": ns}, map[string]any{"message": msg, "service": service})
+	}
+	add("cache miss for key 42", "cartservice", 3000)
+	add("cache miss for key 7", "checkout", 1000)
+	add("cache miss for key 9", "cartservice", 2000)
+	add("connection reset by peer", "frontend", 1500)
+
+	rows := c.report(0)
+	if len(rows) != 2 {
+		t.Fatalf("got %d patterns, want 2", len(rows))
+	}
+	top := rows[0]
+	if top.Pattern != "cache miss for key <NUM>" || top.Count != 3 {
+		t.Errorf("top pattern = %q (%d), want cache miss (3)", top.Pattern, top.Count)
+	}
+	if top.Percent != 75 {
+		t.Errorf("Percent = %v, want 75", top.Percent)
+	}
+	if !top.FirstSeen.Equal(time.Unix(0, 1000)) || !top.LastSeen.Equal(time.Unix(0, 3000)) {
+		t.Errorf("seen range = %v..%v", top.FirstSeen, top.LastSeen)
+	}
+	if len(top.Services) != 2 || top.Services[0] != "cartservice" || top.Services[1] != "checkout" {
+		t.Errorf("Services = %v", top.Services)
+	}
+	if top.Example != "cache miss for key 42" {
+		t.Errorf("Example = %q", top.Example)
+	}
+
+	if rows := c.report(1); len(rows) != 1 {
+		t.Errorf("report(1) returned %d rows", len(rows))
+	}
+}
+
+func TestSeenAt(t *testing.T) {
+	if got := seenAt(time.Time{}, time.UTC); got != "-" {
+		t.Errorf("seenAt(zero) = %q, want -", got)
+	}
+	if got := seenAt(time.Unix(1760536980, 0), time.UTC); got != "2025-10-15 14:03:00" {
+		t.Errorf("seenAt = %q", got)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"regexp"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/logparse"
+	"github.com/lakerunner/cli/internal/redact"
+)
+
+const redactedValue = "[REDACTED]"
+
+// postStage transforms a streamed log entry in place. Returning false drops the entry.
+type postStage func(message map[string]any, tags map[string]any) bool
+
+// postPipeline is an ordered list of client-side stages applied to each entry
+// between the API stream and the output formatter.
+type postPipeline []postStage
+
+// postOptions holds the client-side post-processing flags of `logs get`.
+type postOptions struct {
+	dedupe      []string
+	sample      float64
+	extract     []string
+	flattenJSON []string
+	redactField []string
+}
+
+// newPostPipeline builds the pipeline for the given options. Stages run in a
+// fixed order: flatten, extract, dedupe, sample, redact. rng may be nil.
+func newPostPipeline(opts postOptions, rng *rand.Rand) (postPipeline, error) {
+	var p postPipeline
+	for _, field := range opts.flattenJSON {
+		p = append(p, flattenJSONStage(field))
+	}
+	for _, expr := range opts.extract {
+		st, err := extractStage(expr)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, st)
+	}
+	if len(opts.dedupe) > 0 {
+		p = append(p, dedupeStage(opts.dedupe))
+	}
+	if opts.sample != 0 {
+		if opts.sample < 0 || opts.sample > 1 {
+			return nil, fmt.Errorf("invalid --sample %v: must be between 0 and 1", opts.sample)
+		}
+		if rng == nil {
+			rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+		}
+		p = append(p, sampleStage(opts.sample, rng))
+	}
+	if len(opts.redactField) > 0 {
+		p = append(p, redactFieldStage(opts.redactField))
+	}
+	return p, nil
+}
+
+// apply runs every stage against the entry and reports whether it survived.
+func (p postPipeline) apply(message map[string]any, tags map[string]any) bool {
+	for _, st := range p {
+		if !st(message, tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// run streams entries from in through the pipeline. The returned channel is
+// closed when in is drained or ctx is cancelled.
+func (p postPipeline) run(ctx context.Context, in <-chan api.LogsResponse) <-chan api.LogsResponse {
+	if len(p) == 0 {
+		return in
+	}
+	out := make(chan api.LogsResponse)
+	go func() {
+		defer close(out)
+		for response := range in {
+			message := response.Data
+			if message == nil {
+				message = make(map[string]any)
+				response.Data = message
+			}
+			tags, _ := message["tags"].(map[string]any)
+			if tags == nil {
+				tags = make(map[string]any)
+				message["tags"] = tags
+			}
+			if !p.apply(message, tags) {
+				continue
+			}
+			select {
+			case out <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// clientParseStage applies --parse/--where locally when the server rejected them.
+func clientParseStage(stages []logparse.Stage, where []logparse.Filter) postStage {
+	return func(_ map[string]any, tags map[string]any) bool {
+		return applyClientPipeline(tags, stages, where)
+	}
+}
+
+// flattenJSONStage parses the JSON object held in field and merges its
+// flattened keys into tags.
+func flattenJSONStage(field string) postStage {
+	parser := logparse.Stage{Kind: logparse.KindJSON}
+	return func(message map[string]any, tags map[string]any) bool {
+		logparse.Merge(tags, parser.Extract(getFieldValue(message, tags, field)))
+		return true
+	}
+}
+
+// leadingName matches an "ident=" prefix used to name an unnamed capture group,
+// so 'latency=(\d+)ms' yields a field called latency.
+var leadingName = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=`)
+
+// extractStage compiles an --extract regex applied to the message. Named groups
+// become fields of the same name; unnamed groups take the name of a leading
+// "name=" literal, or extract_N otherwise.
+func extractStage(expr string) (postStage, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --extract regex %q: %w", expr, err)
+	}
+	if re.NumSubexp() == 0 {
+		return nil, fmt.Errorf("invalid --extract regex %q: needs a capture group", expr)
+	}
+	names := re.SubexpNames()
+	prefix := ""
+	if m := leadingName.FindStringSubmatch(expr); m != nil {
+		prefix = m[1]
+	}
+	for i := 1; i < len(names); i++ {
+		switch {
+		case names[i] != "":
+		case i == 1 && prefix != "":
+			names[i] = prefix
+		default:
+			names[i] = fmt.Sprintf("extract_%d", i)
+		}
+	}
+	return func(_ map[string]any, tags map[string]any) bool {
+		msg, _ := tags["message"].(string)
+		m := re.FindStringSubmatch(msg)
+		if m == nil {
+			return true
+		}
+		fields := make(map[string]string, len(m)-1)
+		for i := 1; i < len(m); i++ {
+			fields[names[i]] = m[i]
+		}
+		logparse.Merge(tags, fields)
+		return true
+	}, nil
+}
+
+// dedupeStage drops entries whose values for fields were already seen.
+func dedupeStage(fields []string) postStage {
+	seen := make(map[uint64]struct{})
+	return func(message map[string]any, tags map[string]any) bool {
+		h := fnv.New64a()
+		for _, f := range fields {
+			_, _ = h.Write([]byte(getFieldValue(message, tags, f)))
+			_, _ = h.Write([]byte{0})
+		}
+		key := h.Sum64()
+		if _, ok := seen[key]; ok {
+			return false
+		}
+		seen[key] = struct{}{}
+		return true
+	}
+}
+
+// sampleStage keeps each entry with probability rate.
+func sampleStage(rate float64, rng *rand.Rand) postStage {
+	return func(_ map[string]any, _ map[string]any) bool {
+		return rng.Float64() < rate
+	}
+}
+
+// redactFieldStage replaces the values of the given tag fields.
+func redactFieldStage(fields []string) postStage {
+	return func(_ map[string]any, tags map[string]any) bool {
+		for _, f := range fields {
+			if _, key, ok := fieldnames.Lookup(tags, f); ok {
+				tags[key] = redactedValue
+			}
+		}
+		return true
+	}
+}
+
+// redactStage masks PII and secrets in every tag before the entry is formatted.
+func redactStage(r *redact.Redactor) postStage {
+	return func(_ map[string]any, tags map[string]any) bool {
+		r.Apply(tags)
+		return true
+	}
+}
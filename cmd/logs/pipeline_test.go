@@ -0,0 +1,174 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/lakerunner/cli/internal/api"
+)
+
+// mockStream feeds copies of the mock entries (optionally repeated) into a channel.
+func mockStream(repeat int) <-chan api.LogsResponse {
+	ch := make(chan api.LogsResponse)
+	go func() {
+		defer close(ch)
+		for range repeat {
+			for _, entry := range mockLogEntries {
+				data := maps.Clone(entry.message)
+				data["tags"] = maps.Clone(entry.tags)
+				ch <- api.LogsResponse{Type: "event", Data: data}
+			}
+		}
+	}()
+	return ch
+}
+
+// collect runs the pipeline over the mock stream and returns the surviving tags.
+func collect(t *testing.T, opts postOptions, rng *rand.Rand, repeat int) []map[string]any {
+	t.Helper()
+	p, err := newPostPipeline(opts, rng)
+	if err != nil {
+		t.Fatalf("newPostPipeline() error: %v", err)
+	}
+	var out []map[string]any
+	for response := range p.run(context.Background(), mockStream(repeat)) {
+		tags, _ := response.Data["tags"].(map[string]any)
+		out = append(out, tags)
+	}
+	return out
+}
+
+func TestPostPipelinePassthrough(t *testing.T) {
+	out := collect(t, postOptions{}, nil, 1)
+	if len(out) != len(mockLogEntries) {
+		t.Fatalf("got %d entries, want %d", len(out), len(mockLogEntries))
+	}
+}
+
+func TestPostPipelineDedupe(t *testing.T) {
+	out := collect(t, postOptions{dedupe: []string{"message"}}, nil, 3)
+	if len(out) != len(mockLogEntries) {
+		t.Errorf("dedupe on message: got %d entries, want %d", len(out), len(mockLogEntries))
+	}
+
+	out = collect(t, postOptions{dedupe: []string{"service"}}, nil, 2)
+	if len(out) != 2 {
+		t.Errorf("dedupe on service: got %d entries, want 2 (cartservice, loadgenerator)", len(out))
+	}
+}
+
+func TestPostPipelineSample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	out := collect(t, postOptions{sample: 0.1}, rng, 1000)
+	total := 1000 * len(mockLogEntries)
+	if len(out) < total/20 || len(out) > total/5 {
+		t.Errorf("sample 0.1 kept %d of %d entries", len(out), total)
+	}
+
+	if out := collect(t, postOptions{sample: 1}, nil, 1); len(out) != len(mockLogEntries) {
+		t.Errorf("sample 1 kept %d entries, want all", len(out))
+	}
+
+	if _, err := newPostPipeline(postOptions{sample: 1.5}, nil); err == nil {
+		t.Error("expected error for --sample 1.5")
+	}
+}
+
+func TestPostPipelineExtract(t *testing.T) {
+	out := collect(t, postOptions{extract: []string{`key: '(\w+)'`, `StatusCode\.(?P<grpc_status>[A-Z_]+)`}}, nil, 1)
+	if len(out) != len(mockLogEntries) {
+		t.Fatalf("extract must not drop entries, got %d", len(out))
+	}
+	if got := out[1]["extract_1"]; got != "loadgeneratorFloodHomepage" {
+		t.Errorf("extract_1 = %v, want loadgeneratorFloodHomepage", got)
+	}
+	if got := out[2]["grpc_status"]; got != "UNAVAILABLE" {
+		t.Errorf("grpc_status = %v, want UNAVAILABLE", got)
+	}
+	if _, ok := out[0]["grpc_status"]; ok {
+		t.Error("grpc_status should not be set when the regex does not match")
+	}
+
+	tags := map[string]any{"message": "request done latency=153ms"}
+	st, err := extractStage(`latency=(\d+)ms`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st(nil, tags)
+	if tags["latency"] != "153" {
+		t.Errorf("latency = %v, want 153", tags["latency"])
+	}
+
+	for _, bad := range []string{`latency=\d+`, `(`} {
+		if _, err := extractStage(bad); err == nil {
+			t.Errorf("extractStage(%q) expected error", bad)
+		}
+	}
+}
+
+func TestPostPipelineFlattenJSON(t *testing.T) {
+	tags := map[string]any{"message": `{"http":{"status":503,"path":"/cart"},"user":"bob"}`}
+	if !flattenJSONStage("message")(nil, tags) {
+		t.Fatal("flatten must not drop entries")
+	}
+	if tags["http_status"] != "503" || tags["http_path"] != "/cart" || tags["user"] != "bob" {
+		t.Errorf("flattened tags = %v", tags)
+	}
+
+	// Non-JSON messages from the mock stream pass through untouched.
+	out := collect(t, postOptions{flattenJSON: []string{"message"}}, nil, 1)
+	for i, tags := range out {
+		if len(tags) != len(mockLogEntries[i].tags) {
+			t.Errorf("entry %d gained fields from non-JSON message: %v", i, tags)
+		}
+	}
+}
+
+func TestPostPipelineRedactField(t *testing.T) {
+	out := collect(t, postOptions{redactField: []string{"k8s.pod.name", "trace_id"}}, nil, 1)
+	for _, tags := range out {
+		if tags["k8s_pod_name"] != redactedValue {
+			t.Errorf("k8s_pod_name = %v, want redacted", tags["k8s_pod_name"])
+		}
+	}
+	if out[0]["trace_id"] != redactedValue {
+		t.Errorf("trace_id = %v, want redacted", out[0]["trace_id"])
+	}
+	if _, ok := out[1]["trace_id"]; ok {
+		t.Error("redaction must not add missing fields")
+	}
+}
+
+func TestPostPipelineOrder(t *testing.T) {
+	// Dedupe runs on the extracted field, then redaction hides it.
+	opts := postOptions{
+		extract:     []string{`(?P<kind>Error|Transient|GetCart)`},
+		dedupe:      []string{"service"},
+		redactField: []string{"kind"},
+	}
+	out := collect(t, opts, nil, 1)
+	if len(out) != 2 {
+		t.Fatalf("got %d entries, want 2", len(out))
+	}
+	for _, tags := range out {
+		if tags["kind"] != redactedValue {
+			t.Errorf("kind = %v, want redacted", tags["kind"])
+		}
+	}
+}
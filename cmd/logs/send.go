@@ -0,0 +1,574 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lakerunner/cli/internal/direct"
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/lakerunner/cli/internal/otlp"
+)
+
+// sendScope names the CLI as the instrumentation scope of the logs it sends.
+const sendScope = "lakerunner-cli/logs-send"
+
+// commandBuffer is how many lines of a wrapped command are queued while a
+// batch is being sent. Lines beyond it are dropped rather than holding up the
+// command.
+const commandBuffer = 64 * 1024
+
+// ExitStatus is returned when the command run by logs send exits non-zero.
+// The command has already printed its own output, so the CLI exits with Code
+// without an error message.
+type ExitStatus struct {
+	Code int
+}
+
+func (e *ExitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+var (
+	sendFormat        string
+	sendLevel         string
+	sendService       string
+	sendResource      []string
+	sendOTLPEndpoint  string
+	sendHeaders       []string
+	sendTo            string
+	sendS3Endpoint    string
+	sendObjectFormat  string
+	sendOrg           string
+	sendCollector     string
+	sendBatchSize     int
+	sendFlushInterval time.Duration
+	sendRetries       int
+)
+
+var SendCmd = &cobra.Command{
+	Use:   "send [file...] [-- command [arg...]]",
+	Short: "Send log lines to Lakerunner as OTLP logs",
+	Long: `Read log lines from files, stdin, or the output of a command, and send them
+as OTLP logs, either to an OTLP/HTTP receiver such as a collector
+(--otlp-endpoint) or by writing objects straight into the bucket Lakerunner
+ingests from (--to s3://bucket/prefix, logs-raw/ by default).
+
+Lines that are JSON objects, including rows written by 'logs get -o json',
+keep their timestamp, level, service, message and other fields; other lines
+are sent as they are, stamped with the time they were read and --level.
+After --, the command is run with its output passed through and each line
+also sent, with a log.iostream attribute of stdout or stderr. The command is
+never held up or stopped by the destination: batches that cannot be
+delivered are dropped and reported, and logs send exits with the command's
+exit status.
+
+Records are sent in batches of --batch-size, or every --flush-interval.
+Failed OTLP/HTTP requests are retried with backoff.`,
+	Example: `  ./myjob.sh 2>&1 | lakerunner logs send --otlp-endpoint http://localhost:4318 --service myjob
+  lakerunner logs send --otlp-endpoint http://collector:4318 --service myjob -- ./myjob.sh --full
+  lakerunner logs send --to s3://lakerunner --s3-endpoint http://localhost:9000 --service import export.ndjson
+  lakerunner logs send --to s3://lakerunner/logs-raw --object-format parquet app.log`,
+	RunE:         runSendCmd,
+	SilenceUsage: true,
+}
+
+func init() {
+	SendCmd.Flags().StringVar(&sendFormat, "format", "auto", "Input format: auto (JSON objects or plain lines), json or text")
+	SendCmd.Flags().StringVar(&sendLevel, "level", "INFO", "Severity of lines that do not carry one")
+	SendCmd.Flags().StringVar(&sendService, "service", "", "service.name of lines that do not carry one")
+	SendCmd.Flags().StringArrayVar(&sendResource, "resource", nil, "Resource attribute as key=value (repeatable)")
+	SendCmd.Flags().StringVar(&sendOTLPEndpoint, "otlp-endpoint", "", "OTLP/HTTP receiver, e.g. http://localhost:4318")
+	SendCmd.Flags().StringArrayVar(&sendHeaders, "header", nil, "HTTP header for --otlp-endpoint as key=value (repeatable)")
+	SendCmd.Flags().StringVar(&sendTo, "to", "", "Write objects to s3://bucket/prefix instead (prefix defaults to logs-raw/)")
+	SendCmd.Flags().StringVar(&sendS3Endpoint, "s3-endpoint", "", "S3 endpoint for --to, e.g. http://localhost:9000 for MinIO (default $AWS_ENDPOINT_URL or s3.amazonaws.com)")
+	SendCmd.Flags().StringVar(&sendObjectFormat, "object-format", "otlp", "Object format for --to: otlp (gzipped OTLP protobuf) or parquet")
+	SendCmd.Flags().StringVar(&sendOrg, "org", "", "Organization path segment of object keys")
+	SendCmd.Flags().StringVar(&sendCollector, "collector", "lakerunner-cli", "Collector path segment of object keys")
+	SendCmd.Flags().IntVar(&sendBatchSize, "batch-size", 1000, "Maximum records per request or object")
+	SendCmd.Flags().DurationVar(&sendFlushInterval, "flush-interval", 5*time.Second, "Send a partial batch after this long")
+	SendCmd.Flags().IntVar(&sendRetries, "retries", 5, "Retries for a failed OTLP/HTTP request")
+	SendCmd.MarkFlagsMutuallyExclusive("otlp-endpoint", "to")
+	SendCmd.MarkFlagsOneRequired("otlp-endpoint", "to")
+}
+
+// sendLine is one line of input and the stream of a wrapped command it came
+// from, if any.
+type sendLine struct {
+	text   string
+	stream string
+}
+
+// logSink delivers one batch.
+type logSink interface {
+	send(ctx context.Context, logs []otlp.ResourceLogs) error
+}
+
+type httpSink struct {
+	exporter *otlp.HTTPExporter
+}
+
+func (s *httpSink) send(ctx context.Context, logs []otlp.ResourceLogs) error {
+	return s.exporter.ExportLogs(ctx, logs)
+}
+
+// objectWriter is the part of direct.S3Store bucketSink needs.
+type objectWriter interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+type bucketSink struct {
+	store     objectWriter
+	prefix    string
+	org       string
+	collector string
+	format    string
+	now       func() time.Time
+}
+
+// newBucketSink writes objects in format (otlp or parquet) below the
+// s3://bucket/prefix URL to, prefix defaulting to logs-raw/. It also returns
+// the location for messages.
+func newBucketSink(to, endpoint, format, org, collector string, insecure bool) (*bucketSink, string, error) {
+	if format != "otlp" && format != "parquet" {
+		return nil, "", fmt.Errorf("invalid --object-format %q: expected otlp or parquet", format)
+	}
+	loc, err := direct.ParseURL(to)
+	if err != nil {
+		return nil, "", err
+	}
+	if loc.Prefix == "" {
+		loc.Prefix = "logs-raw/"
+	}
+	store, err := direct.NewS3Store(loc, direct.S3Options{Endpoint: endpoint, Insecure: insecure})
+	if err != nil {
+		return nil, "", err
+	}
+	sink := &bucketSink{store: store, prefix: loc.Prefix, org: org, collector: collector, format: format, now: time.Now}
+	return sink, loc.String(), nil
+}
+
+func (s *bucketSink) send(ctx context.Context, logs []otlp.ResourceLogs) error {
+	var buf bytes.Buffer
+	ext := "parquet"
+	if s.format == "parquet" {
+		if err := logfile.WriteParquet(&buf, logEntries(logs)); err != nil {
+			return err
+		}
+	} else {
+		ext = "binpb.gz"
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(otlp.MarshalLogs(logs)); err != nil {
+			return fmt.Errorf("failed to compress logs: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress logs: %w", err)
+		}
+	}
+	key := otlp.ObjectKey(s.prefix, s.org, s.collector, "logs", s.now(), ext)
+	return s.store.Put(ctx, key, buf.Bytes())
+}
+
+// logEntries converts logs to entries in the shape logfile reads, resource
+// attributes becoming resource_* tags.
+func logEntries(logs []otlp.ResourceLogs) []map[string]any {
+	var entries []map[string]any
+	for _, rl := range logs {
+		resource := make(map[string]any)
+		for _, kv := range rl.Resource {
+			if kv.Key == "service.name" {
+				resource["service"] = kv.Value
+			} else {
+				resource["resource."+kv.Key] = kv.Value
+			}
+		}
+		for _, rec := range rl.Records {
+			tags := map[string]any{"message": rec.Body, "level": rec.SeverityText}
+			for k, v := range resource {
+				tags[k] = v
+			}
+			for _, kv := range rec.Attributes {
+				tags[kv.Key] = kv.Value
+			}
+			entries = append(entries, map[string]any{"timestamp_ns": rec.Time.UnixNano(), "tags": tags})
+		}
+	}
+	return entries
+}
+
+// parsePairs parses key=value flags.
+func parsePairs(flag string, values []string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --%s %q: expected key=value", flag, v)
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs, nil
+}
+
+// toRecord converts a line to a log record and the service it belongs to. It
+// returns false for lines that carry no entry, such as blank lines.
+func toRecord(line sendLine, now time.Time) (otlp.LogRecord, string, bool, error) {
+	rec := otlp.LogRecord{Time: now, SeverityText: sendLevel, Body: line.text}
+	if line.stream != "" {
+		rec.Attributes = append(rec.Attributes, otlp.KeyValue{Key: "log.iostream", Value: line.stream})
+	}
+	if strings.TrimSpace(line.text) == "" {
+		return rec, "", false, nil
+	}
+	isJSON := sendFormat == "json" || sendFormat == "auto" && strings.HasPrefix(strings.TrimSpace(line.text), "{")
+	if !isJSON {
+		return rec, sendService, true, nil
+	}
+	data, err := logfile.ParseLine(line.text)
+	if err != nil {
+		if sendFormat == "auto" {
+			return rec, sendService, true, nil
+		}
+		return rec, "", false, err
+	}
+	if data == nil {
+		return rec, "", false, nil
+	}
+	if ns, ok := data["timestamp_ns"].(int64); ok {
+		rec.Time = time.Unix(0, ns)
+	}
+	tags, _ := data["tags"].(map[string]any)
+	message, level, service, rest := logfile.SplitTags(tags)
+	rec.Body = message
+	if level != "" {
+		rec.SeverityText = level
+	}
+	if service == "" {
+		service = sendService
+	}
+	for k, v := range rest {
+		rec.Attributes = append(rec.Attributes, otlp.KeyValue{Key: k, Value: v})
+	}
+	return rec, service, true, nil
+}
+
+// sendBatcher groups records by resource and hands full batches to a sink.
+type sendBatcher struct {
+	sink     logSink
+	resource [][2]string
+	size     int
+	// scope defaults to sendScope.
+	scope string
+	// dropFailed makes flush warn about and drop batches the sink fails to
+	// take instead of returning the error.
+	dropFailed bool
+
+	logs    []otlp.ResourceLogs
+	index   map[string]int
+	pending int
+
+	records, batches int
+	dropped, failed  int
+}
+
+// add adds a record of service, with the batcher's resource attributes.
+func (b *sendBatcher) add(ctx context.Context, rec otlp.LogRecord, service string) error {
+	if service == "" {
+		service = "unknown_service"
+	}
+	resource := []otlp.KeyValue{{Key: "service.name", Value: service}}
+	for _, kv := range b.resource {
+		resource = append(resource, otlp.KeyValue{Key: kv[0], Value: kv[1]})
+	}
+	return b.addResource(ctx, rec, resource)
+}
+
+// addResource adds a record of the given resource.
+func (b *sendBatcher) addResource(ctx context.Context, rec otlp.LogRecord, resource []otlp.KeyValue) error {
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	var key strings.Builder
+	for _, kv := range resource {
+		fmt.Fprintf(&key, "%s=%v\x00", kv.Key, kv.Value)
+	}
+	i, ok := b.index[key.String()]
+	if !ok {
+		scope := b.scope
+		if scope == "" {
+			scope = sendScope
+		}
+		i = len(b.logs)
+		b.index[key.String()] = i
+		b.logs = append(b.logs, otlp.ResourceLogs{Resource: resource, Scope: scope})
+	}
+	b.logs[i].Records = append(b.logs[i].Records, rec)
+	b.pending++
+	if b.pending >= b.size {
+		return b.flush(ctx)
+	}
+	return nil
+}
+
+func (b *sendBatcher) flush(ctx context.Context) error {
+	if b.pending == 0 {
+		return nil
+	}
+	err := b.sink.send(ctx, b.logs)
+	switch {
+	case err == nil:
+		b.records += b.pending
+		b.batches++
+	case b.dropFailed:
+		fmt.Fprintf(os.Stderr, "Warning: dropped %d records: %v\n", b.pending, err)
+		b.dropped += b.pending
+		b.failed++
+	default:
+		return fmt.Errorf("failed to send %d records: %w", b.pending, err)
+	}
+	b.logs, b.index, b.pending = nil, nil, 0
+	return nil
+}
+
+// sendLines batches lines until the channel closes, flushing partial batches
+// every interval.
+func sendLines(ctx context.Context, lines <-chan sendLine, b *sendBatcher, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return b.flush(ctx)
+			}
+			rec, service, ok, err := toRecord(line, time.Now())
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if err := b.add(ctx, rec, service); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// scanLines sends the lines of r to out until r ends or ctx is done.
+func scanLines(ctx context.Context, r io.Reader, out chan<- sendLine) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		select {
+		case out <- sendLine{text: scanner.Text()}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+// teeLines copies the lines of a command's stream to echo and queues them on
+// out without ever blocking, so the command is not held up by a slow or
+// failing destination. Lines that find out full are counted in dropped.
+func teeLines(r io.Reader, stream string, echo io.Writer, out chan<- sendLine, dropped *atomic.Int64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		_, _ = fmt.Fprintln(echo, scanner.Text())
+		select {
+		case out <- sendLine{text: scanner.Text(), stream: stream}:
+		default:
+			dropped.Add(1)
+		}
+	}
+	return scanner.Err()
+}
+
+// runCommand runs command with its output passed through and queued on out,
+// then closes out. Its lifetime is its own: nothing stops it early. A
+// non-zero exit is returned as *ExitStatus.
+func runCommand(command []string, out chan<- sendLine, dropped *atomic.Int64) error {
+	defer close(out)
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdin = os.Stdin
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+	stderr, err := c.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Go(func() { errs[0] = teeLines(stdout, "stdout", os.Stdout, out, dropped) })
+	wg.Go(func() { errs[1] = teeLines(stderr, "stderr", os.Stderr, out, dropped) })
+	wg.Wait()
+	if err := c.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return &ExitStatus{Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("%s failed: %w", command[0], err)
+	}
+	return errors.Join(errs...)
+}
+
+// readInput feeds out from files or stdin, then closes it.
+func readInput(ctx context.Context, files []string, out chan<- sendLine) error {
+	defer close(out)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, path := range files {
+		if path == "-" {
+			if err := scanLines(ctx, os.Stdin, out); err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(strings.ToLower(path), ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				_ = f.Close()
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			r = gz
+		}
+		err = scanLines(ctx, r, out)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func newSendSink(cmdObj *cobra.Command) (logSink, string, error) {
+	if sendOTLPEndpoint != "" {
+		headers, err := parsePairs("header", sendHeaders)
+		if err != nil {
+			return nil, "", err
+		}
+		exporter := &otlp.HTTPExporter{Endpoint: sendOTLPEndpoint, Headers: map[string]string{}, Retries: sendRetries}
+		for _, h := range headers {
+			exporter.Headers[h[0]] = h[1]
+		}
+		return &httpSink{exporter: exporter}, exporter.LogsURL(), nil
+	}
+
+	insecure, _ := cmdObj.Flags().GetBool("insecure")
+	return newBucketSink(sendTo, sendS3Endpoint, sendObjectFormat, sendOrg, sendCollector, insecure)
+}
+
+func runSendCmd(cmdObj *cobra.Command, args []string) error {
+	files, command := args, []string(nil)
+	if dash := cmdObj.ArgsLenAtDash(); dash >= 0 {
+		files, command = args[:dash], args[dash:]
+		if len(files) > 0 || len(command) == 0 {
+			return fmt.Errorf("pass either files or a command after --, e.g. lakerunner logs send --otlp-endpoint URL -- ./myjob.sh")
+		}
+	}
+	switch sendFormat {
+	case "auto", "json", "text":
+	default:
+		return fmt.Errorf("invalid --format %q: expected auto, json or text", sendFormat)
+	}
+	if sendBatchSize <= 0 || sendFlushInterval <= 0 {
+		return fmt.Errorf("--batch-size and --flush-interval must be positive")
+	}
+	resource, err := parsePairs("resource", sendResource)
+	if err != nil {
+		return err
+	}
+	sink, target, err := newSendSink(cmdObj)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := &sendBatcher{sink: sink, resource: resource, size: sendBatchSize}
+	inputErr := make(chan error, 1)
+	var lines chan sendLine
+	var droppedLines atomic.Int64
+	if len(command) > 0 {
+		lines = make(chan sendLine, commandBuffer)
+		b.dropFailed = true
+		go func() { inputErr <- runCommand(command, lines, &droppedLines) }()
+	} else {
+		lines = make(chan sendLine, 1024)
+		go func() { inputErr <- readInput(ctx, files, lines) }()
+	}
+
+	sendErr := sendLines(ctx, lines, b, sendFlushInterval)
+	if sendErr != nil && len(command) == 0 {
+		cancel()
+		<-inputErr
+		return sendErr
+	}
+	if sendErr != nil {
+		// The command carries on regardless; the rest of its lines are dropped.
+		fmt.Fprintf(os.Stderr, "Warning: stopped sending: %v\n", sendErr)
+	}
+	readErr := <-inputErr
+	for range lines {
+		droppedLines.Add(1)
+	}
+
+	if quiet, _ := cmdObj.Flags().GetBool("quiet"); !quiet {
+		fmt.Fprintf(os.Stderr, "Sent %d records in %d batches to %s\n", b.records, b.batches, target)
+	}
+	if b.dropped > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d records in %d batches could not be delivered and were dropped\n", b.dropped, b.failed)
+	}
+	if n := droppedLines.Load(); n > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d lines were dropped because sending fell behind the command\n", n)
+	}
+	var status *ExitStatus
+	if errors.As(readErr, &status) {
+		cmdObj.SilenceErrors = true
+	}
+	return readErr
+}
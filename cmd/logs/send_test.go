@@ -0,0 +1,187 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/lakerunner/cli/internal/otlp"
+)
+
+func TestToRecord(t *testing.T) {
+	sendFormat, sendLevel, sendService = "auto", "INFO", "job"
+	now := time.Unix(1760536800, 0)
+	attr := func(rec otlp.LogRecord, key string) any {
+		for _, kv := range rec.Attributes {
+			if kv.Key == key {
+				return kv.Value
+			}
+		}
+		return nil
+	}
+
+	rec, service, ok, err := toRecord(sendLine{text: "plain line", stream: "stderr"}, now)
+	if err != nil || !ok || rec.Body != "plain line" || rec.SeverityText != "INFO" || service != "job" || !rec.Time.Equal(now) || attr(rec, "log.iostream") != "stderr" {
+		t.Errorf("text line: %+v %q %v %v", rec, service, ok, err)
+	}
+
+	rec, service, ok, err = toRecord(sendLine{text: `{"timestamp":"2026-10-15 14:00:00.5","level":"ERROR","service":"api","message":"boom","status":500}`}, now)
+	want := time.Date(2026, 10, 15, 14, 0, 0, 5e8, time.Local)
+	if err != nil || !ok || rec.Body != "boom" || rec.SeverityText != "ERROR" || service != "api" || !rec.Time.Equal(want) || attr(rec, "status") != float64(500) || len(rec.Attributes) != 1 {
+		t.Errorf("json line: %+v %q %v %v", rec, service, ok, err)
+	}
+
+	rec, _, ok, err = toRecord(sendLine{text: `{"log_message":"from export"}`}, now)
+	if err != nil || !ok || rec.Body != "from export" || len(rec.Attributes) != 0 {
+		t.Errorf("aliased json line: %+v %v %v", rec, ok, err)
+	}
+
+	for _, skipped := range []string{"", "  ", `{"type":"done"}`} {
+		if _, _, ok, err := toRecord(sendLine{text: skipped}, now); ok || err != nil {
+			t.Errorf("%q should be skipped, got %v %v", skipped, ok, err)
+		}
+	}
+
+	if rec, _, ok, _ := toRecord(sendLine{text: "{not json"}, now); !ok || rec.Body != "{not json" {
+		t.Errorf("auto should fall back to text: %+v", rec)
+	}
+	sendFormat = "json"
+	if _, _, _, err := toRecord(sendLine{text: "{not json"}, now); err == nil {
+		t.Error("json format should reject invalid lines")
+	}
+	sendFormat = "text"
+	if rec, _, _, _ := toRecord(sendLine{text: `{"message":"x"}`}, now); rec.Body != `{"message":"x"}` {
+		t.Errorf("text format should keep JSON as is: %q", rec.Body)
+	}
+	sendFormat, sendService = "auto", ""
+}
+
+type fakeSink struct{ batches [][]otlp.ResourceLogs }
+
+func (s *fakeSink) send(_ context.Context, logs []otlp.ResourceLogs) error {
+	s.batches = append(s.batches, logs)
+	return nil
+}
+
+func TestSendLines(t *testing.T) {
+	lines := make(chan sendLine, 10)
+	for _, text := range []string{`{"service":"a","message":"1"}`, `{"service":"b","message":"2"}`, "", `{"service":"a","message":"3"}`, "4"} {
+		lines <- sendLine{text: text}
+	}
+	close(lines)
+
+	sink := &fakeSink{}
+	b := &sendBatcher{sink: sink, resource: [][2]string{{"env", "test"}}, size: 3}
+	if err := sendLines(context.Background(), lines, b, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if b.records != 4 || b.batches != 2 || len(sink.batches) != 2 {
+		t.Fatalf("sent %d records in %d batches", b.records, b.batches)
+	}
+	first := sink.batches[0]
+	if len(first) != 2 || len(first[0].Records) != 2 || first[0].Resource[0].Value != "a" || first[0].Resource[1] != (otlp.KeyValue{Key: "env", Value: "test"}) {
+		t.Errorf("first batch = %+v", first)
+	}
+	if second := sink.batches[1]; len(second) != 1 || second[0].Resource[0].Value != "unknown_service" {
+		t.Errorf("second batch = %+v", second)
+	}
+}
+
+func TestSendLinesDropFailed(t *testing.T) {
+	lines := make(chan sendLine, 10)
+	for _, text := range []string{"1", "2", "3", "4", "5"} {
+		lines <- sendLine{text: text}
+	}
+	close(lines)
+
+	sink := &failingSink{limit: 1}
+	b := &sendBatcher{sink: sink, size: 2, dropFailed: true}
+	if err := sendLines(context.Background(), lines, b, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if b.records != 2 || b.dropped != 3 || b.failed != 2 {
+		t.Errorf("sent %d records, dropped %d in %d batches; want 2, 3 in 2", b.records, b.dropped, b.failed)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	lines := make(chan sendLine, 1)
+	var dropped atomic.Int64
+	err := runCommand([]string{"sh", "-c", "echo one; echo two; echo three >&2; exit 3"}, lines, &dropped)
+	var status *ExitStatus
+	if !errors.As(err, &status) || status.Code != 3 {
+		t.Fatalf("runCommand = %v, want exit status 3", err)
+	}
+	// Only one line fits; the command still ran to the end.
+	var got []sendLine
+	for line := range lines {
+		got = append(got, line)
+	}
+	if len(got) != 1 || dropped.Load() != 2 {
+		t.Errorf("queued %v and dropped %d, want 1 and 2", got, dropped.Load())
+	}
+}
+
+type fakeObjects map[string][]byte
+
+func (f fakeObjects) Put(_ context.Context, key string, data []byte) error {
+	f[key] = data
+	return nil
+}
+
+func TestBucketSink(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 3, 0, 0, time.UTC)
+	logs := []otlp.ResourceLogs{{
+		Resource: []otlp.KeyValue{{Key: "service.name", Value: "api"}, {Key: "env", Value: "prod"}},
+		Records:  []otlp.LogRecord{{Time: now, SeverityText: "WARN", Body: "slow", Attributes: []otlp.KeyValue{{Key: "path", Value: "/x"}}}},
+	}}
+	objects := fakeObjects{}
+	sink := &bucketSink{store: objects, prefix: "logs-raw/", collector: "lakerunner-cli", format: "parquet", now: func() time.Time { return now }}
+	if err := sink.send(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+	sink.format = "otlp"
+	if err := sink.send(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := "logs-raw/lakerunner-cli/year=2026/month=10/day=15/hour=14/minute=03/"
+	name := fmt.Sprintf("logs_%d", now.UnixNano())
+	parquetKey := dir + name + ".parquet"
+	if _, ok := objects[dir+name+".binpb.gz"]; !ok || len(objects) != 2 {
+		t.Fatalf("objects = %v", slices.Collect(maps.Keys(objects)))
+	}
+	var got []map[string]any
+	data := objects[parquetKey]
+	if err := logfile.ReadParquet(bytes.NewReader(data), int64(len(data)), func(e map[string]any) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tags := got[0]["tags"].(map[string]any)
+	if len(got) != 1 || tags["message"] != "slow" || tags["level"] != "WARN" || tags["service"] != "api" || tags["resource_env"] != "prod" || tags["path"] != "/x" {
+		t.Errorf("parquet rows = %v", got)
+	}
+}
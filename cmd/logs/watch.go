@@ -0,0 +1,296 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchQuery     queryFlags
+	watchThreshold string
+	watchInterval  time.Duration
+	watchCooldown  time.Duration
+	watchExec      string
+	watchWebhook   string
+	watchTemplate  string
+	watchSamples   int
+	watchScanLimit int
+	watchName      string
+	watchState     string
+	watchOnce      bool
+)
+
+// WatchCmd is registered on the root command as `lakerunner watch`; it lives
+// here to share the logs filter flags and query building.
+var WatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Poll for matching logs and run a command or webhook when a threshold is crossed",
+	Long: `Poll the logs matching the given filters and notify when at least COUNT
+entries matched within the last WINDOW (--threshold COUNT/WINDOW).
+
+A firing watch notifies again only after --cooldown, and sends a "resolved"
+notification on the first poll below the threshold. State is kept in
+~/.lakerunner/watch/NAME.json so a restarted watcher does not re-fire; a
+notifier that fails is retried on later polls without the others repeating.
+
+Notifications are a JSON event (name, status, count, threshold, query,
+start, end and sample entries) unless --template is given, in which case the
+file is rendered as a Go text/template over the same event. --exec runs the
+command with the payload on stdin and LAKERUNNER_WATCH_STATUS, _NAME, _COUNT,
+_THRESHOLD and _QUERY in its environment; --webhook POSTs the payload.`,
+	Example: `  lakerunner watch -p payments -l ERROR --threshold 10/5m --exec ./page.sh
+  lakerunner watch -a checkout -M timeout --webhook https://hooks.example.com/T000 --template slack.tmpl`,
+	RunE: runWatchCmd,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	watchQuery.registerFilters(WatchCmd)
+	WatchCmd.Flags().StringVar(&watchThreshold, "threshold", "1/5m", "Fire when at least COUNT entries match within WINDOW")
+	WatchCmd.Flags().DurationVar(&watchInterval, "interval", time.Minute, "How often to poll")
+	WatchCmd.Flags().DurationVar(&watchCooldown, "cooldown", 15*time.Minute, "Minimum time between notifications while firing")
+	WatchCmd.Flags().StringVar(&watchExec, "exec", "", "Shell command to run on firing and resolve")
+	WatchCmd.Flags().StringVar(&watchWebhook, "webhook", "", "URL to POST the payload to on firing and resolve")
+	WatchCmd.Flags().StringVar(&watchTemplate, "template", "", "File containing a Go text/template for the payload")
+	WatchCmd.Flags().IntVar(&watchSamples, "samples", 5, "Number of sample entries to include in the payload")
+	WatchCmd.Flags().IntVar(&watchScanLimit, "scan-limit", 1000, "Maximum number of entries counted per poll")
+	WatchCmd.Flags().StringVar(&watchName, "name", "", "Watch name used for state and payloads (default derived from the query)")
+	WatchCmd.Flags().StringVar(&watchState, "state", "", "State file path (default ~/.lakerunner/watch/NAME.json)")
+	WatchCmd.Flags().BoolVar(&watchOnce, "once", false, "Poll once and exit (for cron)")
+	watchQuery.registerAliases(WatchCmd)
+}
+
+// watcher evaluates one watch rule per poll.
+type watcher struct {
+	client    *api.Client
+	name      string
+	query     string
+	threshold watch.Threshold
+	cooldown  time.Duration
+	samples   int
+	scanLimit int
+	statePath string
+	tmpl      *template.Template
+	notifiers []watch.Notifier
+	now       func() time.Time
+	log       func(format string, args ...any)
+}
+
+// defaultWatchName derives a stable name from the query and threshold so
+// that distinct watches get distinct state files.
+func defaultWatchName(preset, query, threshold string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(query + "\x00" + threshold))
+	base := "watch"
+	if preset != "" {
+		base = preset
+	}
+	return fmt.Sprintf("%s-%08x", base, h.Sum32())
+}
+
+// poll counts matches in the threshold window, advances the persisted state
+// and notifies on a status change. Each notifier's delivery is recorded in the
+// state, so one that failed is retried on the next poll without the others
+// notifying again.
+func (w *watcher) poll(ctx context.Context) error {
+	now := w.now()
+	start := now.Add(-w.threshold.Window)
+
+	responseChan, err := w.client.QueryLogs(ctx, w.query, fmt.Sprintf("%d", start.UnixMilli()), fmt.Sprintf("%d", now.UnixMilli()), w.scanLimit, true, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query logs: %w", err)
+	}
+	var count int
+	var samples []watch.Sample
+	for response := range responseChan {
+		tags, _ := response.Data["tags"].(map[string]any)
+		if tags == nil {
+			continue
+		}
+		count++
+		if len(samples) < w.samples {
+			s := watch.Sample{Tags: tags}
+			s.Message, _ = tags["message"].(string)
+			if ns, ok := entryTimestampNs(response.Data); ok {
+				s.Timestamp = time.Unix(0, ns)
+			}
+			samples = append(samples, s)
+		}
+	}
+
+	state, err := watch.LoadState(w.statePath)
+	if err != nil {
+		return err
+	}
+	status := state.Step(w.threshold, count, w.cooldown, now)
+	w.log("%s: %d matches in %s (threshold %s)", w.name, count, w.threshold.Window, w.threshold)
+	if status != "" {
+		w.log("%s: %s", w.name, status)
+		ev := w.event(status, count, start, now, samples)
+		state.Pending, state.Undelivered = &ev, nil
+		for _, n := range w.notifiers {
+			state.Undelivered = append(state.Undelivered, fmt.Sprint(n))
+		}
+	}
+	err = w.deliver(ctx, state)
+	return errors.Join(err, state.Save(w.statePath))
+}
+
+// event builds the notification for a status change.
+func (w *watcher) event(status string, count int, start, now time.Time, samples []watch.Sample) watch.Event {
+	ev := watch.Event{
+		Name:      w.name,
+		Status:    status,
+		Count:     count,
+		Threshold: w.threshold.String(),
+		Query:     w.query,
+		Start:     start,
+		End:       now,
+		Samples:   samples,
+	}
+	if ev.Samples == nil {
+		ev.Samples = []watch.Sample{}
+	}
+	return ev
+}
+
+// deliver sends the state's pending event to the notifiers that still owe
+// it, removing each one that succeeds. Notifiers no longer configured are
+// forgotten.
+func (w *watcher) deliver(ctx context.Context, state *watch.State) error {
+	if state.Pending == nil {
+		return nil
+	}
+	payload, err := watch.Payload(w.tmpl, *state.Pending)
+	if err != nil {
+		return err
+	}
+	var undelivered []string
+	var errs []error
+	for _, n := range w.notifiers {
+		if !slices.Contains(state.Undelivered, fmt.Sprint(n)) {
+			continue
+		}
+		if err := n.Notify(ctx, *state.Pending, payload); err != nil {
+			undelivered = append(undelivered, fmt.Sprint(n))
+			errs = append(errs, err)
+		}
+	}
+	state.Undelivered = undelivered
+	if len(undelivered) == 0 {
+		state.Pending = nil
+	}
+	return errors.Join(errs...)
+}
+
+func runWatchCmd(cmdObj *cobra.Command, _ []string) error {
+	threshold, err := watch.ParseThreshold(watchThreshold)
+	if err != nil {
+		return err
+	}
+	if watchExec == "" && watchWebhook == "" {
+		return fmt.Errorf("nothing to notify: set --exec and/or --webhook")
+	}
+	if watchInterval <= 0 {
+		return fmt.Errorf("invalid --interval %s: must be positive", watchInterval)
+	}
+
+	client, _, err := newClientFromFlags(cmdObj)
+	if err != nil {
+		return err
+	}
+	q, err := watchQuery.buildQuery()
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{
+		client:    client,
+		name:      watchName,
+		query:     q,
+		threshold: threshold,
+		cooldown:  watchCooldown,
+		samples:   watchSamples,
+		scanLimit: max(watchScanLimit, threshold.Count),
+		statePath: watchState,
+		now:       time.Now,
+		log:       func(string, ...any) {},
+	}
+	if w.name == "" {
+		w.name = defaultWatchName(watchQuery.preset, q, threshold.String())
+	}
+	if w.statePath == "" {
+		if w.statePath, err = watch.DefaultStatePath(w.name); err != nil {
+			return err
+		}
+	}
+	if watchTemplate != "" {
+		text, err := os.ReadFile(watchTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		if w.tmpl, err = watch.ParseTemplate(watchTemplate, string(text)); err != nil {
+			return err
+		}
+	}
+	if watchExec != "" {
+		w.notifiers = append(w.notifiers, &watch.ExecNotifier{Command: watchExec, Stdout: os.Stdout, Stderr: os.Stderr})
+	}
+	if watchWebhook != "" {
+		w.notifiers = append(w.notifiers, &watch.WebhookNotifier{URL: watchWebhook})
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if !quiet {
+		w.log = func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "%s "+format+"\n", append([]any{time.Now().Format("15:04:05")}, args...)...)
+		}
+		fmt.Fprintf(os.Stderr, "Watching %s every %s (state %s)\n", q, watchInterval, w.statePath)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if watchOnce {
+		return w.poll(ctx)
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
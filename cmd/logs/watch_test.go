@@ -0,0 +1,170 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/watch"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	base := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	logs := contextServer(t, base, 10) // one entry per second from base
+	defer logs.Close()
+
+	var mu sync.Mutex
+	var received []watch.Event
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev watch.Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		mu.Lock()
+		received = append(received, ev)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	statePath := filepath.Join(t.TempDir(), "w.json")
+	now := base.Add(10 * time.Second)
+	newWatcher := func() *watcher {
+		return &watcher{
+			client:    api.NewClient(&config.Config{LAKERUNNER_QUERY_URL: logs.URL, LAKERUNNER_API_KEY: "test"}),
+			name:      "test",
+			query:     `{resource_service_name="cart"}`,
+			threshold: watch.Threshold{Count: 5, Window: 10 * time.Second},
+			cooldown:  time.Hour,
+			samples:   2,
+			scanLimit: 100,
+			statePath: statePath,
+			notifiers: []watch.Notifier{&watch.WebhookNotifier{URL: hook.URL}},
+			now:       func() time.Time { return now },
+			log:       func(string, ...any) {},
+		}
+	}
+
+	if err := newWatcher().poll(context.Background()); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(received) != 1 || received[0].Status != watch.StatusFiring || received[0].Count != 10 {
+		t.Fatalf("after first poll received %+v", received)
+	}
+	if len(received[0].Samples) != 2 || received[0].Samples[0].Message != "line 9" {
+		t.Errorf("samples = %+v", received[0].Samples)
+	}
+
+	// A restarted watcher within the cooldown must not fire again.
+	if err := newWatcher().poll(context.Background()); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("re-fired after restart: %+v", received)
+	}
+
+	// Only 2 entries in the window: resolved.
+	now = base.Add(18 * time.Second)
+	if err := newWatcher().poll(context.Background()); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(received) != 2 || received[1].Status != watch.StatusResolved {
+		t.Errorf("after drop received %+v", received)
+	}
+}
+
+func TestWatcherRetriesFailedNotification(t *testing.T) {
+	base := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+	logs := contextServer(t, base, 5)
+	defer logs.Close()
+	var hookCalls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hookCalls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer hook.Close()
+
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "w.json")
+	execLog := filepath.Join(dir, "exec.log")
+	now := base.Add(10 * time.Second)
+	w := &watcher{
+		client:    api.NewClient(&config.Config{LAKERUNNER_QUERY_URL: logs.URL, LAKERUNNER_API_KEY: "test"}),
+		name:      "test",
+		threshold: watch.Threshold{Count: 1, Window: time.Minute},
+		cooldown:  time.Hour,
+		scanLimit: 100,
+		statePath: statePath,
+		notifiers: []watch.Notifier{
+			&watch.ExecNotifier{Command: "echo $LAKERUNNER_WATCH_STATUS >> " + execLog},
+			&watch.WebhookNotifier{URL: hook.URL + "/hook?token=s3cret"},
+		},
+		now: func() time.Time { return now },
+		log: func(string, ...any) {},
+	}
+	if err := w.poll(context.Background()); err == nil {
+		t.Fatal("expected webhook error")
+	}
+	state, err := watch.LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Firing || state.Pending == nil || len(state.Undelivered) != 1 || !strings.HasPrefix(state.Undelivered[0], "webhook ") {
+		t.Fatalf("state after failed webhook = %+v", state)
+	}
+	if data, _ := os.ReadFile(statePath); strings.Contains(string(data), "s3cret") {
+		t.Errorf("state file holds the webhook token:\n%s", data)
+	}
+
+	// The next poll retries the webhook only.
+	now = now.Add(time.Second)
+	if err := w.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if hookCalls.Load() != 2 {
+		t.Errorf("webhook called %d times, want 2", hookCalls.Load())
+	}
+	if data, _ := os.ReadFile(execLog); string(data) != "firing\n" {
+		t.Errorf("exec ran with %q, want one firing", data)
+	}
+	if state, _ := watch.LoadState(statePath); state.Pending != nil || len(state.Undelivered) != 0 {
+		t.Errorf("state after retry = %+v, want nothing pending", state)
+	}
+}
+
+func TestDefaultWatchName(t *testing.T) {
+	a := defaultWatchName("payments", "q1", "10/5m0s")
+	if a != defaultWatchName("payments", "q1", "10/5m0s") {
+		t.Error("name is not stable")
+	}
+	if a == defaultWatchName("payments", "q2", "10/5m0s") {
+		t.Error("different queries share a name")
+	}
+	if len(a) != len("payments-")+8 {
+		t.Errorf("unexpected name %q", a)
+	}
+}
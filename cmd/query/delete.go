@@ -0,0 +1,40 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var DeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a saved query",
+	RunE:  runDeleteCmd,
+	Args:  cobra.ExactArgs(1),
+}
+
+func runDeleteCmd(cmdObj *cobra.Command, args []string) error {
+	if err := queries.Delete(args[0]); err != nil {
+		return err
+	}
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if !quiet {
+		fmt.Printf("Deleted query '%s'\n", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var EditCmd = &cobra.Command{
+	Use:   "edit NAME",
+	Short: "Open a saved query in $VISUAL or $EDITOR",
+	Long: `Open the query file in $VISUAL, $EDITOR or vi. A query that does not exist
+yet is created from a template.`,
+	RunE: runEditCmd,
+	Args: cobra.ExactArgs(1),
+}
+
+func runEditCmd(_ *cobra.Command, args []string) error {
+	name := args[0]
+	path, err := queries.Path(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		q := &queries.Query{Name: name, Description: "", Args: []string{"-s", "e-1h"}}
+		if err := queries.Save(q, false); err != nil {
+			return err
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	if _, err := queries.Load(name); err != nil {
+		return fmt.Errorf("saved file is not a valid query, run 'lakerunner query edit %s' to fix it: %w", name, err)
+	}
+	return nil
+}
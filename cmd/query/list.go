@@ -0,0 +1,53 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved queries",
+	RunE:  runListCmd,
+	Args:  cobra.NoArgs,
+}
+
+func runListCmd(_ *cobra.Command, _ []string) error {
+	names, err := queries.List()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No saved queries. Save one with: lakerunner query save NAME -- [logs get flags]")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		q, err := queries.Load(name)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "%s\t(error: %v)\n", name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", name, q.Description)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,38 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var QueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Manage and run saved logs queries",
+	Long: `Saved queries capture a whole 'lakerunner logs get' invocation (filters or a
+raw --query, time range, columns, output format, order) under a name. Arguments
+may contain ${param} placeholders that are filled in at run time.
+
+Queries are stored one per file in ~/.lakerunner/queries/NAME.yaml.`,
+}
+
+func init() {
+	QueryCmd.AddCommand(ListCmd)
+	QueryCmd.AddCommand(ShowCmd)
+	QueryCmd.AddCommand(SaveCmd)
+	QueryCmd.AddCommand(DeleteCmd)
+	QueryCmd.AddCommand(EditCmd)
+	QueryCmd.AddCommand(RunCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lakerunner/cli/cmd/logs"
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var runParams []string
+
+var RunCmd = &cobra.Command{
+	Use:   "run NAME [-- extra logs get flags]",
+	Short: "Run a saved query",
+	Long: `Run a saved query through 'logs get'. Fill in ${param} placeholders with
+--param; flags after -- are appended and override the saved ones.`,
+	Example: `  lakerunner query run pod-errors --param pod=api-7f9
+  lakerunner query run pod-errors --param pod=api-7f9 -- -s e-24h -o json`,
+	RunE: runRunCmd,
+	Args: cobra.MinimumNArgs(1),
+}
+
+func init() {
+	RunCmd.Flags().StringArrayVarP(&runParams, "param", "P", []string{}, "Parameter value as NAME=VALUE (can be used multiple times)")
+}
+
+func runRunCmd(cmdObj *cobra.Command, args []string) error {
+	if dash := cmdObj.ArgsLenAtDash(); dash > 1 || (dash == -1 && len(args) > 1) {
+		return fmt.Errorf("put extra logs get flags after --, e.g. lakerunner query run %s -- -s e-24h", args[0])
+	}
+	q, err := queries.Load(args[0])
+	if err != nil {
+		return err
+	}
+	values, err := queries.ParseParams(runParams)
+	if err != nil {
+		return err
+	}
+	getArgs, err := q.Expand(values)
+	if err != nil {
+		return err
+	}
+	getArgs = append(getArgs, args[1:]...)
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Running: %s\n", commandLine(append([]string{"lakerunner", "logs", "get"}, getArgs...)))
+	}
+	return runLogsGet(getArgs)
+}
+
+// runLogsGet parses args with the logs get flag set and runs it. Global flags
+// such as --endpoint are shared with this command and keep their values.
+func runLogsGet(args []string) error {
+	get := logs.GetCmd
+	if err := get.ParseFlags(args); err != nil {
+		return fmt.Errorf("invalid saved query arguments: %w", err)
+	}
+	rest := get.Flags().Args()
+	if err := get.ValidateArgs(rest); err != nil {
+		return fmt.Errorf("invalid saved query arguments: %w", err)
+	}
+	return get.RunE(get, rest)
+}
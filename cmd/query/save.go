@@ -0,0 +1,77 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var (
+	saveDescription string
+	saveParams      []string
+	saveForce       bool
+)
+
+var SaveCmd = &cobra.Command{
+	Use:   "save NAME -- [logs get flags]",
+	Short: "Save a logs get invocation under a name",
+	Long: `Save the 'logs get' flags after -- under NAME. Use ${param} in any argument
+to fill it in at run time, and --param to give a default.`,
+	Example: `  lakerunner query save pod-errors --description "Errors from one pod" -- \
+    -f 'k8s_pod_name:${pod}' -l ERROR -s e-1h -c timestamp,message
+
+  lakerunner query save slow-checkout --param ms=1000 -- \
+    -a checkout --parse json --where 'duration_ms>${ms}' -o json`,
+	RunE: runSaveCmd,
+	Args: cobra.MinimumNArgs(2),
+}
+
+func init() {
+	SaveCmd.Flags().StringVarP(&saveDescription, "description", "d", "", "Description shown by 'query list'")
+	SaveCmd.Flags().StringArrayVar(&saveParams, "param", []string{}, "Default parameter value as NAME=VALUE (can be used multiple times)")
+	SaveCmd.Flags().BoolVar(&saveForce, "force", false, "Replace an existing query with the same name")
+}
+
+func runSaveCmd(cmdObj *cobra.Command, args []string) error {
+	if cmdObj.ArgsLenAtDash() != 1 {
+		return fmt.Errorf("put the logs get flags after --, e.g. lakerunner query save %s -- -l ERROR", args[0])
+	}
+	defaults, err := queries.ParseParams(saveParams)
+	if err != nil {
+		return err
+	}
+	q := &queries.Query{Name: args[0], Description: saveDescription, Args: args[1:]}
+	if len(defaults) > 0 {
+		q.Params = defaults
+	}
+	for name := range defaults {
+		if !slices.Contains(q.Placeholders(), name) {
+			return fmt.Errorf("default for %q does not match any ${...} placeholder", name)
+		}
+	}
+	if err := queries.Save(q, saveForce); err != nil {
+		return err
+	}
+
+	quiet, _ := cmdObj.Flags().GetBool("quiet")
+	if !quiet {
+		fmt.Printf("Saved query '%s'\n", q.Name)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/queries"
+	"github.com/spf13/cobra"
+)
+
+var ShowCmd = &cobra.Command{
+	Use:   "show NAME",
+	Short: "Show a saved query and the command it runs",
+	RunE:  runShowCmd,
+	Args:  cobra.ExactArgs(1),
+}
+
+func runShowCmd(_ *cobra.Command, args []string) error {
+	q, err := queries.Load(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Name: %s\n", q.Name)
+	if q.Description != "" {
+		fmt.Printf("Description: %s\n", q.Description)
+	}
+	if params := q.Placeholders(); len(params) > 0 {
+		fmt.Println("Parameters:")
+		for _, name := range params {
+			if def, ok := q.Params[name]; ok {
+				fmt.Printf("  %s (default %q)\n", name, def)
+			} else {
+				fmt.Printf("  %s (required)\n", name)
+			}
+		}
+	}
+	fmt.Printf("Command: %s\n", commandLine(append([]string{"lakerunner", "logs", "get"}, q.Args...)))
+	return nil
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@,+-]+$`)
+
+// commandLine joins args for display, single-quoting those a shell would split.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if shellSafe.MatchString(a) {
+			quoted[i] = a
+		} else {
+			quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(quoted, " ")
+}
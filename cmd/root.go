@@ -15,14 +15,19 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
 
 	"github.com/lakerunner/cli/cmd/aliases"
+	cacheCmd "github.com/lakerunner/cli/cmd/cache"
+	configCmd "github.com/lakerunner/cli/cmd/config"
 	"github.com/lakerunner/cli/cmd/demo"
 	"github.com/lakerunner/cli/cmd/logs"
 	presetsCmd "github.com/lakerunner/cli/cmd/presets"
+	"github.com/lakerunner/cli/cmd/query"
+	"github.com/lakerunner/cli/internal/presets"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -32,6 +37,17 @@ var rootCmd = &cobra.Command{
 	Short: "CLI tool to query Lakerunner",
 	Long:  `A CLI tool to interact with deployed lakerunner. It currently supports querying logs.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+			presets.SetConfigFile(configFile)
+		}
+		// Anything printed while completing would end up in the shell's suggestions.
+		completing := cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+		if quiet, _ := cmd.Flags().GetBool("quiet"); !quiet && !completing && cmd.Annotations[presets.AnnotationSkipWarnings] == "" {
+			for _, w := range presets.Warnings() {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
+		}
+
 		// Automatically disable colors on Windows or when not in a terminal
 		noColor, _ := cmd.Flags().GetBool("no-color")
 		if !noColor {
@@ -45,8 +61,20 @@ var rootCmd = &cobra.Command{
 	},
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the CLI and returns its exit code. Errors are printed here;
+// a command run by logs send has already reported its own failure, so only
+// its exit code is passed on.
+func Execute() int {
+	err := rootCmd.Execute()
+	if err == nil {
+		return 0
+	}
+	var status *logs.ExitStatus
+	if errors.As(err, &status) {
+		return status.Code
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return 1
 }
 
 func init() {
@@ -54,10 +82,17 @@ func init() {
 	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
 	rootCmd.PersistentFlags().String("endpoint", "", "API endpoint URL (overrides LAKERUNNER_QUERY_URL)")
 	rootCmd.PersistentFlags().String("api-key", "", "API key (overrides LAKERUNNER_API_KEY)")
+	rootCmd.PersistentFlags().String("config", "", "config file layered over ~/.lakerunner/config.yaml, .lakerunner.yaml and LAKERUNNER_CONFIG")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "bypass the local cache of tags, tag values and recent results")
 	rootCmd.PersistentFlags().Bool("insecure", false, "skip TLS certificate verification (for self-signed endpoints; overrides LAKERUNNER_INSECURE)")
 
 	rootCmd.AddCommand(logs.LogsCmd)
+	rootCmd.AddCommand(logs.WatchCmd)
+	rootCmd.AddCommand(logs.ImportCmd)
 	rootCmd.AddCommand(demo.DemoCmd)
 	rootCmd.AddCommand(presetsCmd.PresetsCmd)
 	rootCmd.AddCommand(aliases.AliasesCmd)
+	rootCmd.AddCommand(query.QueryCmd)
+	rootCmd.AddCommand(configCmd.ConfigCmd)
+	rootCmd.AddCommand(cacheCmd.CacheCmd)
 }
@@ -0,0 +1,120 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anomaly scores points of a time series by how far they deviate
+// from the rest of the series.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Method selects how deviation is measured.
+type Method string
+
+const (
+	// ZScore measures distance from the mean in standard deviations.
+	ZScore Method = "zscore"
+	// MAD measures distance from the median in (scaled) median absolute
+	// deviations. It is robust to the outliers it is looking for.
+	MAD Method = "mad"
+)
+
+// madScale makes the MAD comparable to a standard deviation for normal data.
+const madScale = 0.6745
+
+// meanAbsScale does the same for the mean absolute deviation, used when more
+// than half the points equal the median and the MAD is zero.
+const meanAbsScale = 0.7979
+
+// ParseMethod returns the Method named by s.
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(s)); m {
+	case ZScore, MAD:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid method %q: must be one of zscore, mad", s)
+}
+
+// Scores returns a signed deviation score for each value; positive scores are
+// above the baseline. A flat series scores zero everywhere.
+func Scores(values []float64, m Method) []float64 {
+	scores := make([]float64, len(values))
+	if len(values) == 0 {
+		return scores
+	}
+	centre, spread := Baseline(values, m)
+	if spread == 0 {
+		return scores
+	}
+	for i, v := range values {
+		scores[i] = (v - centre) / spread
+	}
+	return scores
+}
+
+// Baseline returns the centre and spread used by Scores: mean and standard
+// deviation for ZScore, median and scaled MAD for MAD.
+func Baseline(values []float64, m Method) (centre, spread float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	if m == ZScore {
+		mean := Mean(values)
+		var ss float64
+		for _, v := range values {
+			ss += (v - mean) * (v - mean)
+		}
+		return mean, math.Sqrt(ss / float64(len(values)))
+	}
+
+	med := Median(values)
+	dev := make([]float64, len(values))
+	for i, v := range values {
+		dev[i] = math.Abs(v - med)
+	}
+	if mad := Median(dev); mad > 0 {
+		return med, mad / madScale
+	}
+	return med, Mean(dev) / meanAbsScale
+}
+
+// Mean returns the arithmetic mean of values.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Median returns the median of values without modifying them.
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	s := slices.Clone(values)
+	slices.Sort(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
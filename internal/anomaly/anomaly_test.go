@@ -0,0 +1,89 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anomaly
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseMethod(t *testing.T) {
+	for _, s := range []string{"zscore", "MAD"} {
+		if _, err := ParseMethod(s); err != nil {
+			t.Errorf("ParseMethod(%q) error: %v", s, err)
+		}
+	}
+	if _, err := ParseMethod("iqr"); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if got := Median([]float64{5, 1, 3}); got != 3 {
+		t.Errorf("Median(odd) = %v", got)
+	}
+	if got := Median([]float64{4, 1, 3, 2}); got != 2.5 {
+		t.Errorf("Median(even) = %v", got)
+	}
+	values := []float64{3, 1, 2}
+	Median(values)
+	if values[0] != 3 {
+		t.Error("Median modified its input")
+	}
+}
+
+func TestScoresSpike(t *testing.T) {
+	values := []float64{10, 12, 9, 11, 10, 95, 11, 10}
+	for _, m := range []Method{ZScore, MAD} {
+		scores := Scores(values, m)
+		peak := 0
+		for i, s := range scores {
+			if s > scores[peak] {
+				peak = i
+			}
+		}
+		if peak != 5 {
+			t.Errorf("%s: peak at %d, want 5", m, peak)
+		}
+		if scores[5] < 2 {
+			t.Errorf("%s: spike score %v too low", m, scores[5])
+		}
+	}
+
+	// The spike inflates the standard deviation, so MAD flags it more strongly.
+	if Scores(values, MAD)[5] <= Scores(values, ZScore)[5] {
+		t.Error("expected MAD to score the spike above z-score")
+	}
+}
+
+func TestScoresFlat(t *testing.T) {
+	for _, s := range Scores([]float64{4, 4, 4, 4}, MAD) {
+		if s != 0 {
+			t.Errorf("flat series scored %v", s)
+		}
+	}
+}
+
+func TestScoresZeroMAD(t *testing.T) {
+	// More than half the points equal the median, so the MAD is zero and the
+	// mean absolute deviation is used instead.
+	scores := Scores([]float64{0, 0, 0, 0, 0, 40}, MAD)
+	if scores[5] <= 0 || math.IsInf(scores[5], 0) {
+		t.Errorf("spike score = %v, want finite positive", scores[5])
+	}
+	if scores[0] != 0 {
+		t.Errorf("baseline score = %v, want 0", scores[0])
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/lakerunner/cli/internal/cache"
+)
+
+// Cache lifetimes. Tag names and values change slowly; log result sets are
+// only kept long enough to re-render a query with different columns or output.
+const (
+	TagsCacheTTL = 10 * time.Minute
+	LogsCacheTTL = 2 * time.Minute
+
+	// Tag queries round their range down to this bucket in cache keys so
+	// that relative ranges such as e-1h, resolved seconds apart, share an
+	// entry; which tags exist barely depends on the exact bounds.
+	tagsCacheBucket = 5 * time.Minute
+
+	// LogsCacheBucket is the granularity callers snap relative log query
+	// ranges to so that re-runs share a cache entry. Log results are keyed
+	// by the exact range requested, which may hold different rows.
+	LogsCacheBucket = time.Minute
+
+	// maxCachedResponses keeps very large result sets out of the cache.
+	maxCachedResponses = 10000
+)
+
+// responseCache holds the on-disk caches used by a Client. A nil
+// *responseCache disables caching.
+type responseCache struct {
+	tags *cache.Cache
+	logs *cache.Cache
+	// logHit, if set, is called when log results are replayed from the cache.
+	logHit func(stored time.Time)
+}
+
+// EnableCache makes the client answer tag, tag value and log queries from
+// on-disk caches under dir (see cache.Root), refreshing them from the server
+// once they expire.
+func (c *Client) EnableCache(dir string) {
+	c.cache = &responseCache{
+		tags: cache.New(filepath.Join(dir, "tags"), TagsCacheTTL),
+		logs: cache.New(filepath.Join(dir, "logs"), LogsCacheTTL),
+	}
+}
+
+// CacheEnabled reports whether EnableCache has been called.
+func (c *Client) CacheEnabled() bool {
+	return c.cache != nil
+}
+
+// NotifyCacheHits makes the client call fn with the time the entry was stored
+// whenever it answers a log query from the cache, so callers can say that the
+// results may be behind the server. It has no effect before EnableCache.
+func (c *Client) NotifyCacheHits(fn func(stored time.Time)) {
+	if c.cache != nil {
+		c.cache.logHit = fn
+	}
+}
+
+func (rc *responseCache) tagStore() *cache.Cache {
+	if rc == nil {
+		return nil
+	}
+	return rc.tags
+}
+
+func (rc *responseCache) logStore() *cache.Cache {
+	if rc == nil {
+		return nil
+	}
+	return rc.logs
+}
+
+// storeFunc returns the streamResponses callback that saves a completed
+// stream under key, or nil when store is nil.
+func storeFunc(store *cache.Cache, key string) func([]LogsResponse) {
+	if store == nil {
+		return nil
+	}
+	return func(responses []LogsResponse) {
+		if len(responses) <= maxCachedResponses {
+			_ = store.Set(key, responses)
+		}
+	}
+}
+
+// cacheKey identifies a request to this client's endpoint with its API key,
+// so that keys for different orgs never share entries. Key hashes the parts,
+// so the API key is not written to disk.
+func (c *Client) cacheKey(kind string, parts ...string) string {
+	return cache.Key(append([]string{c.baseURL, c.apiKey, kind}, parts...)...)
+}
+
+// cacheBucket rounds an epoch-millisecond bound down to bucket.
+func cacheBucket(ms string, bucket time.Duration) string {
+	v, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return ms
+	}
+	return strconv.FormatInt(v-v%bucket.Milliseconds(), 10)
+}
+
+// cachedResponses replays the responses cached under key, if any.
+func (c *Client) cachedResponses(store *cache.Cache, key string) (<-chan LogsResponse, bool) {
+	var responses []LogsResponse
+	if store == nil {
+		return nil, false
+	}
+	stored, ok := store.Lookup(key, &responses)
+	if !ok {
+		return nil, false
+	}
+	if store == c.cache.logs && c.cache.logHit != nil {
+		c.cache.logHit(stored)
+	}
+	responseChan := make(chan LogsResponse, len(responses))
+	for _, r := range responses {
+		responseChan <- r
+	}
+	close(responseChan)
+	return responseChan, true
+}
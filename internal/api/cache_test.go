@@ -0,0 +1,176 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/config"
+)
+
+func cacheTestServer(requests *atomic.Int32, done bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/api/v1/logs/tags" {
+			_, _ = fmt.Fprint(w, `{"tags":["service","level"]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"type\":\"event\",\"data\":{\"timestamp_ns\":1760000000123456789,\"tags\":{\"message\":\"hello\"}}}\n\n")
+		if done {
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"done\"}\n\n")
+		} else {
+			// Drop the connection mid-stream, after the event.
+			w.(http.Flusher).Flush()
+			hj, _ := w.(http.Hijacker)
+			if conn, _, err := hj.Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		}
+	}))
+}
+
+// drainer returns a function that collects a query's responses, failing t on error.
+func drainer(t *testing.T) func(<-chan LogsResponse, error) []LogsResponse {
+	return func(ch <-chan LogsResponse, err error) []LogsResponse {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []LogsResponse
+		for r := range ch {
+			out = append(out, r)
+		}
+		return out
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	var requests atomic.Int32
+	srv := cacheTestServer(&requests, true)
+	defer srv.Close()
+
+	client := NewClient(&config.Config{LAKERUNNER_QUERY_URL: srv.URL, LAKERUNNER_API_KEY: "test"})
+	client.EnableCache(t.TempDir())
+	drain := drainer(t)
+	ctx := context.Background()
+	s, e := "1760000000000", "1760003600000"
+
+	first := drain(client.QueryLogs(ctx, `{service="api"}`, s, e, 100, true, nil))
+	second := drain(client.QueryLogs(ctx, `{service="api"}`, s, e, 100, true, nil))
+	if requests.Load() != 1 {
+		t.Errorf("server saw %d logs requests, want 1", requests.Load())
+	}
+	if len(first) != 1 || len(second) != 1 {
+		t.Fatalf("got %d and %d responses, want 1 each", len(first), len(second))
+	}
+	if ns, ok := second[0].Data["timestamp_ns"].(int64); !ok || ns != 1760000000123456789 {
+		t.Errorf("cached timestamp_ns = %#v, want int64 1760000000123456789", second[0].Data["timestamp_ns"])
+	}
+
+	// A range seconds later, even within the same minute, may hold other rows.
+	drain(client.QueryLogs(ctx, `{service="api"}`, "1760000005000", "1760003605000", 100, true, nil))
+	if requests.Load() != 2 {
+		t.Errorf("server saw %d requests, want 2", requests.Load())
+	}
+
+	// A different limit is a different result set.
+	drain(client.QueryLogs(ctx, `{service="api"}`, s, e, 10, true, nil))
+	if requests.Load() != 3 {
+		t.Errorf("server saw %d requests, want 3", requests.Load())
+	}
+
+	for range 2 {
+		responses := drain(client.QueryLogTags(ctx, "", s, e))
+		if tags, ok := responses[0].Data["tags"].([]string); !ok || len(tags) != 2 {
+			t.Errorf("tags = %#v, want []string of 2", responses[0].Data["tags"])
+		}
+	}
+	if requests.Load() != 4 {
+		t.Errorf("server saw %d requests, want 4", requests.Load())
+	}
+}
+
+func TestClientCachePerAPIKey(t *testing.T) {
+	var requests atomic.Int32
+	srv := cacheTestServer(&requests, true)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	drain := drainer(t)
+	var hits int
+	for _, key := range []string{"org-a", "org-b", "org-a"} {
+		client := NewClient(&config.Config{LAKERUNNER_QUERY_URL: srv.URL, LAKERUNNER_API_KEY: key})
+		client.EnableCache(dir)
+		client.NotifyCacheHits(func(time.Time) { hits++ })
+		drain(client.QueryLogs(context.Background(), "{}", "0", "1000", 100, true, nil))
+	}
+	if requests.Load() != 2 || hits != 1 {
+		t.Errorf("server saw %d requests with %d cache hits, want 2 and 1 (one per API key)", requests.Load(), hits)
+	}
+}
+
+func TestClientCacheSkipsIncompleteStreams(t *testing.T) {
+	var requests atomic.Int32
+	srv := cacheTestServer(&requests, false)
+	defer srv.Close()
+
+	client := NewClient(&config.Config{LAKERUNNER_QUERY_URL: srv.URL, LAKERUNNER_API_KEY: "test"})
+	client.EnableCache(t.TempDir())
+	drain := drainer(t)
+	for range 2 {
+		drain(client.QueryLogs(context.Background(), "{}", "0", "1000", 100, true, nil))
+	}
+	if requests.Load() != 2 {
+		t.Errorf("server saw %d requests, want 2 (truncated stream must not be cached)", requests.Load())
+	}
+}
+
+func TestClientCacheStoresStreamAtLimit(t *testing.T) {
+	var requests atomic.Int32
+	srv := cacheTestServer(&requests, false)
+	defer srv.Close()
+
+	client := NewClient(&config.Config{LAKERUNNER_QUERY_URL: srv.URL, LAKERUNNER_API_KEY: "test"})
+	client.EnableCache(t.TempDir())
+	for range 2 {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := client.QueryLogs(ctx, "{}", "0", "1000", 1, true, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Stop reading at the limit, as logs get does.
+		<-ch
+		cancel()
+	}
+	if requests.Load() != 1 {
+		t.Errorf("server saw %d requests, want 1 (a stream at its limit is complete)", requests.Load())
+	}
+}
+
+func TestCacheBucket(t *testing.T) {
+	if got := cacheBucket("1760000059999", time.Minute); got != "1760000040000" {
+		t.Errorf("cacheBucket = %s", got)
+	}
+	if got := cacheBucket("e-1h", time.Minute); got != "e-1h" {
+		t.Errorf("cacheBucket passes through non-numeric bounds, got %s", got)
+	}
+}
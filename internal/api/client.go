@@ -20,9 +20,11 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,8 +36,36 @@ type Client struct {
 	baseURL string
 	apiKey  string
 	client  *http.Client
+	cache   *responseCache
 }
 
+// StatusError is returned when the API responds with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsBadRequest reports whether err is a 400 or 422 rejection of the request
+// itself, e.g. a query the server cannot parse.
+func IsBadRequest(err error) bool {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.StatusCode == http.StatusBadRequest ||
+		se.StatusCode == http.StatusUnprocessableEntity
+}
+
+// IsNotImplemented reports whether err is a 501 answer: the server
+// understood the request but does not support what it asks for.
+func IsNotImplemented(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotImplemented
+}
 
 // NewClient creates a new API client with proper configuration
 func NewClient(cfg *config.Config) *Client {
@@ -89,6 +119,11 @@ func (c *Client) QueryLogs(
 		body["fields"] = fields
 	}
 
+	key := c.cacheKey("logs", q, s, e, strconv.Itoa(limit), strconv.FormatBool(reverse), strings.Join(fields, ","))
+	if ch, ok := c.cachedResponses(c.cache.logStore(), key); ok {
+		return ch, nil
+	}
+
 	jsonData, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -100,7 +135,7 @@ func (c *Client) QueryLogs(
 	}
 	c.setCommonHeaders(httpReq)
 
-	return c.streamResponses(ctx, httpReq)
+	return c.streamResponses(ctx, httpReq, storeFunc(c.cache.logStore(), key), limit)
 }
 
 // QueryLogTags makes a request to tags query and returns a json response.
@@ -115,6 +150,13 @@ func (c *Client) QueryLogTags(ctx context.Context, q, s, e string) (<-chan LogsR
 	if q != "" {
 		body["q"] = q
 	}
+
+	key := c.cacheKey("tags", q, cacheBucket(s, tagsCacheBucket), cacheBucket(e, tagsCacheBucket))
+	var tags []string
+	if store := c.cache.tagStore(); store != nil && store.Get(key, &tags) {
+		return tagsResponse(tags), nil
+	}
+
 	jsonData, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -133,7 +175,7 @@ func (c *Client) QueryLogTags(ctx context.Context, q, s, e string) (<-chan LogsR
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	var parsed struct {
 		Tags []string `json:"tags"`
@@ -141,14 +183,21 @@ func (c *Client) QueryLogTags(ctx context.Context, q, s, e string) (<-chan LogsR
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
 		return nil, fmt.Errorf("failed to decode tags: %w", err)
 	}
+	if store := c.cache.tagStore(); store != nil {
+		_ = store.Set(key, parsed.Tags)
+	}
+	return tagsResponse(parsed.Tags), nil
+}
 
+// tagsResponse wraps a tag list in the single response QueryLogTags yields.
+func tagsResponse(tags []string) <-chan LogsResponse {
 	responseChan := make(chan LogsResponse, 1)
 	go func() {
 		defer close(responseChan)
-		msg := map[string]interface{}{"tags": parsed.Tags}
+		msg := map[string]interface{}{"tags": tags}
 		responseChan <- LogsResponse{Type: "data", Data: msg}
 	}()
-	return responseChan, nil
+	return responseChan
 }
 
 // QueryLogTagValues makes a request to tag values query and returns a channel of responses
@@ -163,6 +212,11 @@ func (c *Client) QueryLogTagValues(ctx context.Context, tagName, q, s, e string)
 		body["q"] = q
 	}
 
+	key := c.cacheKey("tagvalues", tagName, q, cacheBucket(s, tagsCacheBucket), cacheBucket(e, tagsCacheBucket))
+	if ch, ok := c.cachedResponses(c.cache.tagStore(), key); ok {
+		return ch, nil
+	}
+
 	jsonData, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -174,11 +228,15 @@ func (c *Client) QueryLogTagValues(ctx context.Context, tagName, q, s, e string)
 	}
 	c.setCommonHeaders(httpReq)
 
-	return c.streamResponses(ctx, httpReq)
+	return c.streamResponses(ctx, httpReq, storeFunc(c.cache.tagStore(), key), 0)
 }
 
-// SSE streaming logic
-func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request) (<-chan LogsResponse, error) {
+// SSE streaming logic. When store is non-nil it receives every response once
+// the stream has ended normally, for caching. A stream that has delivered
+// limit events (when limit > 0) is complete too, since the server stops
+// there; it is stored before the last event is handed over, as callers stop
+// reading and cancel once they have the limit.
+func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request, store func([]LogsResponse), limit int) (<-chan LogsResponse, error) {
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -187,7 +245,7 @@ func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request) (<-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	responseChan := make(chan LogsResponse)
@@ -195,6 +253,14 @@ func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request) (<-
 		defer func() { _ = resp.Body.Close() }()
 		defer close(responseChan)
 
+		var received []LogsResponse
+		var events int
+		finish := func() {
+			if store != nil && ctx.Err() == nil {
+				store(received)
+			}
+		}
+
 		reader := bufio.NewReaderSize(resp.Body, 4096)
 		for {
 			select {
@@ -204,6 +270,7 @@ func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request) (<-
 				line, err := reader.ReadString('\n')
 				if err != nil {
 					if err == io.EOF {
+						finish()
 						return
 					}
 					return
@@ -221,16 +288,127 @@ func (c *Client) streamResponses(ctx context.Context, httpReq *http.Request) (<-
 						continue
 					}
 					if response.Type == "done" {
+						finish()
 						return
 					}
+					if store != nil {
+						received = append(received, response)
+					}
+					if response.Type == "event" {
+						events++
+					}
+					last := limit > 0 && events == limit
+					if last {
+						finish()
+					}
 					select {
 					case responseChan <- response:
 					case <-ctx.Done():
 						return
 					}
+					if last {
+						return
+					}
 				}
 			}
 		}
 	}()
 	return responseChan, nil
 }
+
+// ProbeResult describes one authenticated round trip to the API.
+type ProbeResult struct {
+	StatusCode int
+	Latency    time.Duration
+	// ServerTime is the response's Date header; zero when absent.
+	ServerTime time.Time
+	// LocalTime is the local clock at the midpoint of the request.
+	LocalTime time.Time
+	TLS       *tls.ConnectionState
+}
+
+// Probe makes a minimal tags request over the last minute and reports how the
+// server answered, without treating a non-200 status as an error.
+func (c *Client) Probe(ctx context.Context) (*ProbeResult, error) {
+	now := time.Now()
+	body, err := json.Marshal(map[string]string{
+		"s": fmt.Sprintf("%d", now.Add(-time.Minute).UnixMilli()),
+		"e": fmt.Sprintf("%d", now.UnixMilli()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/logs/tags", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setCommonHeaders(httpReq)
+
+	start := time.Now()
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	latency := time.Since(start)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	result := &ProbeResult{
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+		LocalTime:  start.Add(latency / 2),
+		TLS:        resp.TLS,
+	}
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		result.ServerTime = date
+	}
+	return result, nil
+}
+
+// QueryEstimate is the server's estimate of the work a logs query would do.
+type QueryEstimate struct {
+	Segments int64 `json:"segments"`
+	Bytes    int64 `json:"bytes"`
+	// Rows is the number of rows in those segments, when the server knows.
+	Rows int64 `json:"rows,omitempty"`
+}
+
+// estimatePath is a proposed endpoint, not part of the released query API:
+// only the mock in internal/apitest (`lakerunner demo serve`) implements it.
+const estimatePath = "/api/v1/logs/query/estimate"
+
+// EstimateLogs asks the server how much data a logs query over [s, e] would
+// scan, without running it, using the proposed estimatePath contract. A
+// server without the endpoint answers 404 or 405, and EstimateLogs returns
+// nil and no error; a 501 is returned as a StatusError for IsNotImplemented.
+func (c *Client) EstimateLogs(ctx context.Context, q, s, e string) (*QueryEstimate, error) {
+	body, err := json.Marshal(map[string]string{"q": q, "s": s, "e": e})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+estimatePath, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setCommonHeaders(httpReq)
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, nil
+	default:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	var estimate QueryEstimate
+	if err := json.NewDecoder(resp.Body).Decode(&estimate); err != nil {
+		return nil, fmt.Errorf("failed to decode estimate: %w", err)
+	}
+	return &estimate, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitest
+
+import (
+	"time"
+
+	"github.com/lakerunner/cli/internal/synth"
+)
+
+// Generate returns n synthetic entries spread evenly over [start, end). The
+// same arguments always give the same entries.
+func Generate(n int, start, end time.Time, seed uint64) []map[string]any {
+	records, err := synth.Generate(synth.Options{Start: start, End: end, Count: n, Seed: seed})
+	if err != nil {
+		return nil
+	}
+	entries := make([]map[string]any, 0, len(records))
+	for _, rec := range records {
+		entries = append(entries, map[string]any{
+			"timestamp":    rec.Time.UnixMilli(),
+			"timestamp_ns": rec.Time.UnixNano(),
+			"tags": map[string]any{
+				"service":            rec.Service,
+				"level":              rec.Level,
+				"message":            rec.Message,
+				"k8s_pod_name":       rec.Pod,
+				"k8s_namespace_name": "demo",
+			},
+		})
+	}
+	return entries
+}
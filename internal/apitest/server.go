@@ -0,0 +1,279 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apitest is an in-memory implementation of the Lakerunner query API
+// endpoints the CLI uses, for tests and `lakerunner demo serve`. Queries are
+// evaluated with the LogQL subset in internal/logql; anything beyond it, such
+// as parser stages, is rejected with 400 as a server without that support
+// would.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/lakerunner/cli/internal/logql"
+)
+
+// APIKeyHeader carries the API key on every request.
+const APIKeyHeader = "x-cardinalhq-api-key"
+
+// Request is a request the server received.
+type Request struct {
+	Path  string
+	Query string
+	// TagName is set for tag values requests.
+	TagName string
+}
+
+// Server serves log entries held in memory. Each entry has the shape the
+// query API streams: timestamp (epoch milliseconds), timestamp_ns and a tags
+// map.
+type Server struct {
+	// APIKey, when set, must be sent by clients; other requests get 401.
+	APIKey string
+
+	entries []map[string]any
+	mux     *http.ServeMux
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// New returns a server for entries.
+func New(entries []map[string]any) *Server {
+	s := &Server{entries: entries, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /api/v1/logs/query", s.handleQuery)
+	s.mux.HandleFunc("POST /api/v1/logs/tags", s.handleTags)
+	s.mux.HandleFunc("POST /api/v1/logs/tagvalues", s.handleTagValues)
+	s.mux.HandleFunc("POST /api/v1/logs/query/estimate", s.handleEstimate)
+	return s
+}
+
+// NewFromFile returns a server for the entries in an export, in any format
+// logfile.Read accepts.
+func NewFromFile(path string) (*Server, error) {
+	var entries []map[string]any
+	err := logfile.Read(path, func(data map[string]any) error {
+		entries = append(entries, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(entries), nil
+}
+
+// Len returns the number of entries served.
+func (s *Server) Len() int {
+	return len(s.entries)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.APIKey != "" && r.Header.Get(APIKeyHeader) != s.APIKey {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// queryRequest is the body of all three endpoints; tags and tag values
+// requests only use q, s and e.
+type queryRequest struct {
+	Q       string `json:"q"`
+	S       string `json:"s"`
+	E       string `json:"e"`
+	Limit   int    `json:"limit"`
+	Reverse bool   `json:"reverse"`
+}
+
+// match decodes the request and returns the entries it selects, oldest first.
+func (s *Server) match(w http.ResponseWriter, r *http.Request) ([]map[string]any, *queryRequest, bool) {
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Path: r.URL.Path, Query: req.Q, TagName: r.URL.Query().Get("tagName")})
+	s.mu.Unlock()
+
+	startMs, err1 := strconv.ParseInt(req.S, 10, 64)
+	endMs, err2 := strconv.ParseInt(req.E, 10, 64)
+	if err1 != nil || err2 != nil {
+		http.Error(w, fmt.Sprintf("invalid time range %q to %q: expected epoch milliseconds", req.S, req.E), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	query := &logql.Query{}
+	if req.Q != "" {
+		if query, err1 = logql.Parse(req.Q); err1 != nil {
+			http.Error(w, err1.Error(), http.StatusBadRequest)
+			return nil, nil, false
+		}
+	}
+
+	var matched []map[string]any
+	for _, data := range s.entries {
+		ms, _ := data["timestamp"].(int64)
+		tags, _ := data["tags"].(map[string]any)
+		if ms >= startMs && ms < endMs && query.Match(tags) {
+			matched = append(matched, data)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b map[string]any) int {
+		ta, _ := a["timestamp_ns"].(int64)
+		tb, _ := b["timestamp_ns"].(int64)
+		switch {
+		case ta < tb:
+			return -1
+		case ta > tb:
+			return 1
+		}
+		return 0
+	})
+	return matched, &req, true
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	matched, req, ok := s.match(w, r)
+	if !ok {
+		return
+	}
+	if req.Reverse {
+		slices.Reverse(matched)
+	}
+	if req.Limit > 0 && len(matched) > req.Limit {
+		matched = matched[:req.Limit]
+	}
+	sse := newEventWriter(w)
+	for i, data := range matched {
+		if !sse.send(map[string]any{"id": strconv.Itoa(i + 1), "type": "event", "data": data}) {
+			return
+		}
+	}
+	sse.done()
+}
+
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	matched, _, ok := s.match(w, r)
+	if !ok {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, data := range matched {
+		tags, _ := data["tags"].(map[string]any)
+		for k := range tags {
+			seen[k] = true
+		}
+	}
+	tags := slices.Sorted(maps.Keys(seen))
+	if tags == nil {
+		tags = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"tags": tags})
+}
+
+func (s *Server) handleTagValues(w http.ResponseWriter, r *http.Request) {
+	tagName := r.URL.Query().Get("tagName")
+	if tagName == "" {
+		http.Error(w, "missing tagName", http.StatusBadRequest)
+		return
+	}
+	matched, _, ok := s.match(w, r)
+	if !ok {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, data := range matched {
+		tags, _ := data["tags"].(map[string]any)
+		if v := logql.LabelValue(tags, tagName); v != "" {
+			seen[v] = true
+		}
+	}
+	sse := newEventWriter(w)
+	for _, v := range slices.Sorted(maps.Keys(seen)) {
+		if !sse.send(map[string]any{"type": "result", "data": map[string]any{"value": v}}) {
+			return
+		}
+	}
+	sse.done()
+}
+
+// eventWriter writes server-sent events, flushing each one.
+type eventWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newEventWriter(w http.ResponseWriter) *eventWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	return &eventWriter{w: w, flusher: flusher}
+}
+
+// send writes one event and reports whether the client is still there.
+func (e *eventWriter) send(v any) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(e.w, "data: %s\n\n", data); err != nil {
+		return false
+	}
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	return true
+}
+
+func (e *eventWriter) done() {
+	e.send(map[string]string{"type": "done"})
+}
+
+// handleEstimate mocks the proposed estimate endpoint, which released query
+// APIs do not serve. It reports what a query would scan as if the server pruned
+// perfectly: the matching entries, stored in one segment per hour, each
+// costing its JSON size.
+func (s *Server) handleEstimate(w http.ResponseWriter, r *http.Request) {
+	matched, _, ok := s.match(w, r)
+	if !ok {
+		return
+	}
+	hours := make(map[int64]bool)
+	var size int64
+	for _, data := range matched {
+		ms, _ := data["timestamp"].(int64)
+		hours[ms/time.Hour.Milliseconds()] = true
+		encoded, _ := json.Marshal(data)
+		size += int64(len(encoded))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"segments": len(hours), "bytes": size, "rows": len(matched)})
+}
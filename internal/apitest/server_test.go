@@ -0,0 +1,180 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/api"
+	"github.com/lakerunner/cli/internal/config"
+	"github.com/lakerunner/cli/internal/synth"
+)
+
+var (
+	testStart = time.Date(2025, 10, 15, 14, 0, 0, 0, time.UTC)
+	testEnd   = testStart.Add(time.Hour)
+	s         = strconv.FormatInt(testStart.UnixMilli(), 10)
+	e         = strconv.FormatInt(testEnd.UnixMilli(), 10)
+)
+
+func newTestClient(t *testing.T, srv *Server, key string) *api.Client {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return api.NewClient(&config.Config{LAKERUNNER_QUERY_URL: ts.URL, LAKERUNNER_API_KEY: key})
+}
+
+// collector returns a function draining a query's responses, so that it can
+// wrap the query call directly.
+func collector(t *testing.T) func(<-chan api.LogsResponse, error) []api.LogsResponse {
+	return func(ch <-chan api.LogsResponse, err error) []api.LogsResponse {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []api.LogsResponse
+		for r := range ch {
+			out = append(out, r)
+		}
+		return out
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	a := Generate(50, testStart, testEnd, 7)
+	b := Generate(50, testStart, testEnd, 7)
+	if len(a) != 50 {
+		t.Fatalf("generated %d entries", len(a))
+	}
+	for i := range a {
+		if a[i]["tags"].(map[string]any)["message"] != b[i]["tags"].(map[string]any)["message"] {
+			t.Fatalf("entry %d differs between runs", i)
+		}
+	}
+	if last := a[49]["timestamp"].(int64); last >= testEnd.UnixMilli() {
+		t.Errorf("entry at %d is past the end", last)
+	}
+}
+
+func TestQueryLogs(t *testing.T) {
+	client := newTestClient(t, New(Generate(500, testStart, testEnd, 1)), "")
+	ctx := context.Background()
+	collect := collector(t)
+
+	all := collect(client.QueryLogs(ctx, `{service=~".+"}`, s, e, 1000, true, nil))
+	if len(all) != 500 {
+		t.Fatalf("got %d entries, want 500", len(all))
+	}
+	if a, b := all[0].Data["timestamp"].(int64), all[1].Data["timestamp"].(int64); a < b {
+		t.Errorf("reverse order returned %d before %d", a, b)
+	}
+
+	errors := collect(client.QueryLogs(ctx, `{service="checkout", level="ERROR"} |= "timeout"`, s, e, 5, false, nil))
+	if len(errors) == 0 || len(errors) > 5 {
+		t.Fatalf("got %d entries, want 1 to 5", len(errors))
+	}
+	for _, r := range errors {
+		tags := r.Data["tags"].(map[string]any)
+		if tags["service"] != "checkout" || tags["level"] != "ERROR" {
+			t.Errorf("unexpected entry %v", tags)
+		}
+	}
+
+	mid := strconv.FormatInt(testStart.Add(30*time.Minute).UnixMilli(), 10)
+	if half := collect(client.QueryLogs(ctx, `{service=~".+"}`, s, mid, 1000, false, nil)); len(half) != 250 {
+		t.Errorf("half the window returned %d entries, want 250", len(half))
+	}
+}
+
+func TestQueryLogsRejectsParserStages(t *testing.T) {
+	client := newTestClient(t, New(nil), "")
+	_, err := client.QueryLogs(context.Background(), `{service="api"} | json | status >= 500`, s, e, 10, true, nil)
+	if !api.IsBadRequest(err) {
+		t.Errorf("expected a bad request error, got %v", err)
+	}
+}
+
+func TestTagsAndValues(t *testing.T) {
+	srv := New(Generate(200, testStart, testEnd, 1))
+	client := newTestClient(t, srv, "")
+	ctx := context.Background()
+	collect := collector(t)
+
+	resp := collect(client.QueryLogTags(ctx, "", s, e))
+	tags, _ := resp[0].Data["tags"].([]string)
+	if !slices.Equal(tags, []string{"k8s_namespace_name", "k8s_pod_name", "level", "message", "service"}) {
+		t.Errorf("tags = %q", tags)
+	}
+
+	var values []string
+	for _, r := range collect(client.QueryLogTagValues(ctx, "service", `{level="ERROR"}`, s, e)) {
+		values = append(values, r.Data["value"].(string))
+	}
+	if !slices.IsSorted(values) || !slices.Contains(values, "payments") || len(values) > len(synth.DefaultServices) {
+		t.Errorf("service values = %q", values)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 || reqs[1].TagName != "service" || reqs[1].Query != `{level="ERROR"}` {
+		t.Errorf("recorded requests = %+v", reqs)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	srv := New(nil)
+	srv.APIKey = "secret"
+	_, err := newTestClient(t, srv, "wrong").QueryLogs(context.Background(), `{service="api"}`, s, e, 10, true, nil)
+	if se, ok := err.(*api.StatusError); !ok || se.StatusCode != 401 {
+		t.Errorf("expected 401, got %v", err)
+	}
+	if _, err := newTestClient(t, srv, "secret").QueryLogs(context.Background(), `{service="api"}`, s, e, 10, true, nil); err != nil {
+		t.Errorf("valid key rejected: %v", err)
+	}
+}
+
+func TestEstimateLogs(t *testing.T) {
+	entries := Generate(200, testStart, testEnd.Add(time.Hour), 3)
+	client := newTestClient(t, New(entries), "")
+	end := strconv.FormatInt(testEnd.Add(time.Hour).UnixMilli(), 10)
+
+	estimate, err := client.EstimateLogs(context.Background(), `{service=~".+"}`, s, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate == nil || estimate.Segments != 2 || estimate.Rows != 200 || estimate.Bytes == 0 {
+		t.Errorf("estimate = %+v, want 2 segments of 200 rows", estimate)
+	}
+
+	// A server without the endpoint means no estimate, not an error.
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	plain := api.NewClient(&config.Config{LAKERUNNER_QUERY_URL: ts.URL})
+	if estimate, err := plain.EstimateLogs(context.Background(), "", s, e); estimate != nil || err != nil {
+		t.Errorf("EstimateLogs without the endpoint = %+v, %v", estimate, err)
+	}
+	mux.HandleFunc("POST /api/v1/logs/query/estimate", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "estimates are not implemented", http.StatusNotImplemented)
+	})
+	if _, err := plain.EstimateLogs(context.Background(), "", s, e); !api.IsNotImplemented(err) || api.IsBadRequest(err) {
+		t.Errorf("EstimateLogs answered 501 = %v, want a not-implemented error", err)
+	}
+}
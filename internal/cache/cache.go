@@ -0,0 +1,212 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache is a small on-disk store of JSON values that expire after a
+// fixed TTL. It keeps shell completion and repeated queries from hitting the
+// API every time.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache stores entries as one JSON file per key under Dir.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+	now func() time.Time
+}
+
+// entry is the on-disk form of a cached value.
+type entry struct {
+	Stored time.Time       `json:"stored"`
+	Value  json.RawMessage `json:"value"`
+}
+
+// New returns a cache under dir whose entries expire after ttl.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, now: time.Now}
+}
+
+// Root returns the directory all caches live under: $XDG_CACHE_HOME/lakerunner,
+// or the platform's user cache directory when XDG_CACHE_HOME is unset.
+func Root() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "lakerunner"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	return filepath.Join(dir, "lakerunner"), nil
+}
+
+// DefaultDir returns the directory of the cache called name under Root.
+func DefaultDir(name string) (string, error) {
+	root, err := Root()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, name), nil
+}
+
+// Key hashes parts into a file-name-safe key.
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get decodes the entry for key into v. It reports false when the entry is
+// missing, expired or unreadable.
+func (c *Cache) Get(key string, v any) bool {
+	_, ok := c.Lookup(key, v)
+	return ok
+}
+
+// Lookup is Get that also returns when the entry was stored.
+func (c *Cache) Lookup(key string, v any) (time.Time, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return time.Time{}, false
+	}
+	if c.now().Sub(e.Stored) > c.TTL {
+		return time.Time{}, false
+	}
+	if json.Unmarshal(e.Value, v) != nil {
+		return time.Time{}, false
+	}
+	return e.Stored, true
+}
+
+// Set stores v under key, replacing any previous entry. Entries may hold log
+// lines, so they are only readable by the user.
+func (c *Cache) Set(key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	data, err := json.Marshal(entry{Stored: c.now(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	path := c.path(key)
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Stats summarises one cache directory under Root.
+type Stats struct {
+	Name    string
+	Entries int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Usage reports the entries held by each cache directory under root.
+func Usage(root string) ([]Stats, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	var stats []Stats
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		st := Stats{Name: d.Name()}
+		entries, err := os.ReadDir(filepath.Join(root, d.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache directory: %w", err)
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !strings.HasSuffix(e.Name(), ".json") {
+				continue
+			}
+			st.Entries++
+			st.Bytes += info.Size()
+			if mod := info.ModTime(); st.Oldest.IsZero() || mod.Before(st.Oldest) {
+				st.Oldest = mod
+			}
+			if mod := info.ModTime(); mod.After(st.Newest) {
+				st.Newest = mod
+			}
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// Clear removes the named cache directories under root, or all of them when
+// no names are given.
+func Clear(root string, names ...string) error {
+	if len(names) == 0 {
+		if err := os.RemoveAll(root); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		return nil
+	}
+	for _, name := range names {
+		if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+			return fmt.Errorf("invalid cache name %q", name)
+		}
+		if err := os.RemoveAll(filepath.Join(root, name)); err != nil {
+			return fmt.Errorf("failed to clear %s cache: %w", name, err)
+		}
+	}
+	return nil
+}
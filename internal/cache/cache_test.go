@@ -0,0 +1,121 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetSet(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New(filepath.Join(t.TempDir(), "completion"), time.Minute)
+	c.now = func() time.Time { return now }
+
+	key := Key("https://lakerunner.example.com", "tagvalues", "service")
+	var got []string
+	if c.Get(key, &got) {
+		t.Fatal("expected miss on empty cache")
+	}
+	if err := c.Set(key, []string{"api", "checkout"}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Get(key, &got) || !slices.Equal(got, []string{"api", "checkout"}) {
+		t.Fatalf("Get = %v, want [api checkout]", got)
+	}
+	if stored, ok := c.Lookup(key, &got); !ok || !stored.Equal(now) {
+		t.Errorf("Lookup stored = %v, want %v", stored, now)
+	}
+	if info, err := os.Stat(c.path(key)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("entry mode = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if c.Get(key, &got) {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestGetCorrupt(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+	if err := os.WriteFile(c.path("k"), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var v []string
+	if c.Get("k", &v) {
+		t.Error("expected corrupt entry to miss")
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("a", "bc") == Key("ab", "c") {
+		t.Error("Key must keep part boundaries")
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key must be stable")
+	}
+}
+
+func TestRoot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg")
+	root, err := Root()
+	if err != nil || root != filepath.Join("/tmp/xdg", "lakerunner") {
+		t.Errorf("Root() = %q, %v", root, err)
+	}
+}
+
+func TestUsageAndClear(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"tags", "logs"} {
+		c := New(filepath.Join(root, name), time.Minute)
+		for _, key := range []string{"a", "b"} {
+			if err := c.Set(name+key, []string{key}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	stats, err := Usage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Usage returned %d caches, want 2", len(stats))
+	}
+	for _, st := range stats {
+		if st.Entries != 2 || st.Bytes == 0 || st.Oldest.IsZero() {
+			t.Errorf("unexpected stats %+v", st)
+		}
+	}
+
+	if err := Clear(root, "tags"); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := Usage(root); len(stats) != 1 || stats[0].Name != "logs" {
+		t.Errorf("after clearing tags: %+v", stats)
+	}
+	if err := Clear(root, "../x"); err == nil {
+		t.Error("expected error for invalid cache name")
+	}
+	if err := Clear(root); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err := Usage(root); err != nil || len(stats) != 0 {
+		t.Errorf("after clearing all: %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package direct reads Lakerunner's log segments straight from object
+// storage, for when the query API is unavailable. Segments are Parquet files
+// partitioned as <root>/<YYYYMMDD>/logs/<HH>/*.parquet, where the root is
+// usually db/<organization>/<collector>/ in the bucket.
+package direct
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logfile"
+)
+
+// maxRootDepth bounds how far below the given prefix partition roots are
+// looked for: enough to start from the bucket root of db/<org>/<collector>/.
+const maxRootDepth = 4
+
+var dateintRe = regexp.MustCompile(`^\d{8}$`)
+
+// Object is an object in the store.
+type Object struct {
+	Key  string
+	Size int64
+	// Hour is the start of the hour partition a segment is in, as set by
+	// Segments.
+	Hour time.Time
+}
+
+// ReadAtCloser is an open object.
+type ReadAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Store is the part of an object store the reader needs.
+type Store interface {
+	// List returns the objects directly below prefix and the prefixes, each
+	// ending in "/", one level down.
+	List(ctx context.Context, prefix string) ([]Object, []string, error)
+	Open(ctx context.Context, obj Object) (ReadAtCloser, error)
+}
+
+// Location is a parsed s3://bucket/prefix URL.
+type Location struct {
+	Bucket string
+	Prefix string
+}
+
+func (l Location) String() string {
+	return "s3://" + l.Bucket + "/" + l.Prefix
+}
+
+// ParseURL parses an s3://bucket/prefix URL. The prefix is returned with a
+// trailing slash unless it is empty.
+func ParseURL(s string) (Location, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return Location{}, fmt.Errorf("invalid location %q: expected s3://bucket/prefix", s)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return Location{Bucket: u.Host, Prefix: prefix}, nil
+}
+
+// partitionRoots returns the prefixes at or below prefix whose children are
+// dateint partitions.
+func partitionRoots(ctx context.Context, store Store, prefix string, depth int) ([]string, error) {
+	_, children, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+	for _, child := range children {
+		if dateintRe.MatchString(path.Base(child)) {
+			return []string{prefix}, nil
+		}
+	}
+	if depth == 0 {
+		return nil, nil
+	}
+	var roots []string
+	for _, child := range children {
+		found, err := partitionRoots(ctx, store, child, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, found...)
+	}
+	return roots, nil
+}
+
+// Segments lists the log segments below prefix whose hour partition overlaps
+// [start, end). Partitions are in UTC.
+func Segments(ctx context.Context, store Store, prefix string, start, end time.Time) ([]Object, error) {
+	roots, err := partitionRoots(ctx, store, prefix, maxRootDepth)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no date partitions found below %q", prefix)
+	}
+
+	start, end = start.UTC(), end.UTC()
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	var segments []Object
+	for _, root := range roots {
+		for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+			dayPrefix := root + day.Format("20060102") + "/logs/"
+			_, hours, err := store.List(ctx, dayPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", dayPrefix, err)
+			}
+			for _, hourPrefix := range hours {
+				hour, err := strconv.Atoi(path.Base(hourPrefix))
+				if err != nil || hour < 0 || hour > 23 {
+					continue
+				}
+				from := day.Add(time.Duration(hour) * time.Hour)
+				if !from.Before(end) || !from.Add(time.Hour).After(start) {
+					continue
+				}
+				objects, _, err := store.List(ctx, hourPrefix)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list %s: %w", hourPrefix, err)
+				}
+				for _, obj := range objects {
+					if logfile.IsParquet(obj.Key) {
+						obj.Hour = from
+						segments = append(segments, obj)
+					}
+				}
+			}
+		}
+	}
+	return segments, nil
+}
+
+// ByHour groups segments by hour partition, oldest hour first or, when
+// newestFirst is set, newest first. Rows of one hour are only in that hour's
+// segments, so a reader can stop after the hours it needs.
+func ByHour(segments []Object, newestFirst bool) [][]Object {
+	sorted := slices.Clone(segments)
+	slices.SortStableFunc(sorted, func(a, b Object) int {
+		if newestFirst {
+			return b.Hour.Compare(a.Hour)
+		}
+		return a.Hour.Compare(b.Hour)
+	})
+	var hours [][]Object
+	for i, obj := range sorted {
+		if i == 0 || !obj.Hour.Equal(sorted[i-1].Hour) {
+			hours = append(hours, nil)
+		}
+		hours[len(hours)-1] = append(hours[len(hours)-1], obj)
+	}
+	return hours
+}
+
+// Read calls fn for each row of segments that pred does not rule out.
+func Read(ctx context.Context, store Store, segments []Object, pred *logfile.Predicate, fn func(data map[string]any) error) error {
+	for _, obj := range segments {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := store.Open(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", obj.Key, err)
+		}
+		err = logfile.ReadParquetWhere(r, obj.Size, pred, fn)
+		_ = r.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", obj.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package direct
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/parquet-go/parquet-go"
+)
+
+// memStore is an in-memory Store.
+type memStore map[string][]byte
+
+type memObject struct{ *bytes.Reader }
+
+func (memObject) Close() error { return nil }
+
+func (m memStore) List(_ context.Context, prefix string) ([]Object, []string, error) {
+	var objects []Object
+	var prefixes []string
+	for key, data := range m {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if dir, _, nested := strings.Cut(rest, "/"); nested {
+			if p := prefix + dir + "/"; !slices.Contains(prefixes, p) {
+				prefixes = append(prefixes, p)
+			}
+			continue
+		}
+		objects = append(objects, Object{Key: key, Size: int64(len(data))})
+	}
+	sort.Strings(prefixes)
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, prefixes, nil
+}
+
+func (m memStore) Open(_ context.Context, obj Object) (ReadAtCloser, error) {
+	return memObject{bytes.NewReader(m[obj.Key])}, nil
+}
+
+type segmentRow struct {
+	Timestamp int64  `parquet:"chq_timestamp"`
+	Message   string `parquet:"log_message"`
+	Service   string `parquet:"resource_service_name"`
+}
+
+func segment(t *testing.T, rows ...segmentRow) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Location
+		ok   bool
+	}{
+		{"s3://lakerunner", Location{Bucket: "lakerunner"}, true},
+		{"s3://lakerunner/db/org/collector", Location{Bucket: "lakerunner", Prefix: "db/org/collector/"}, true},
+		{"s3://lakerunner/db/", Location{Bucket: "lakerunner", Prefix: "db/"}, true},
+		{"lakerunner/db", Location{}, false},
+		{"https://lakerunner/db", Location{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseURL(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	store := memStore{
+		"db/org/c1/20251015/logs/13/tbl_1.parquet":    nil,
+		"db/org/c1/20251015/logs/14/tbl_2.parquet":    nil,
+		"db/org/c1/20251015/logs/14/tbl_2.json":       nil,
+		"db/org/c1/20251015/metrics/14/tbl_3.parquet": nil,
+		"db/org/c1/20251016/logs/0/tbl_4.parquet":     nil,
+		"db/org/c2/20251015/logs/14/tbl_5.parquet":    nil,
+		"otel-raw/org/c1/file.json.gz":                nil,
+	}
+	start := time.Date(2025, 10, 15, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		prefix string
+		end    time.Time
+		want   []string
+	}{
+		{"", start.Add(time.Hour), []string{"db/org/c1/20251015/logs/14/tbl_2.parquet", "db/org/c2/20251015/logs/14/tbl_5.parquet"}},
+		{"db/org/c1/", start.Add(10 * time.Hour), []string{"db/org/c1/20251015/logs/14/tbl_2.parquet", "db/org/c1/20251016/logs/0/tbl_4.parquet"}},
+		{"db/org/c1/", start.Add(time.Minute), []string{"db/org/c1/20251015/logs/14/tbl_2.parquet"}},
+	}
+	for _, tt := range tests {
+		segments, err := Segments(context.Background(), store, tt.prefix, start, tt.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, s := range segments {
+			got = append(got, s.Key)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Segments(%q, %s) = %q, want %q", tt.prefix, tt.end, got, tt.want)
+		}
+	}
+
+	if _, err := Segments(context.Background(), store, "otel-raw/", start, start.Add(time.Hour)); err == nil {
+		t.Error("expected an error for a prefix without date partitions")
+	}
+}
+
+func TestRead(t *testing.T) {
+	store := memStore{
+		"r/20251015/logs/14/a.parquet": segment(t, segmentRow{1760537400000, "api one", "api"}, segmentRow{1760537401000, "api two", "api"}),
+		"r/20251015/logs/14/b.parquet": segment(t, segmentRow{1760537402000, "cart one", "cart"}),
+	}
+	segments, err := Segments(context.Background(), store, "r/", time.UnixMilli(1760537400000), time.UnixMilli(1760537500000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	pred := &logfile.Predicate{Values: map[string][]string{"service": {"cart"}}}
+	err = Read(context.Background(), store, segments, pred, func(data map[string]any) error {
+		got = append(got, data["tags"].(map[string]any)["message"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"cart one"}) {
+		t.Errorf("read %q, want only the cart segment", got)
+	}
+}
+
+func TestByHour(t *testing.T) {
+	store := memStore{
+		"db/org/c1/20251015/logs/14/tbl_1.parquet": nil,
+		"db/org/c1/20251015/logs/15/tbl_2.parquet": nil,
+		"db/org/c2/20251015/logs/14/tbl_3.parquet": nil,
+		"db/org/c1/20251016/logs/0/tbl_4.parquet":  nil,
+	}
+	start := time.Date(2025, 10, 15, 14, 0, 0, 0, time.UTC)
+	segments, err := Segments(context.Background(), store, "", start, start.Add(12*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hours := func(newestFirst bool) []string {
+		var got []string
+		for _, hour := range ByHour(segments, newestFirst) {
+			var keys []string
+			for _, obj := range hour {
+				if !obj.Hour.Equal(hour[0].Hour) {
+					t.Errorf("%s grouped with hour %s", obj.Key, hour[0].Hour)
+				}
+				keys = append(keys, obj.Key)
+			}
+			got = append(got, strings.Join(keys, ","))
+		}
+		return got
+	}
+	oldest := []string{
+		"db/org/c1/20251015/logs/14/tbl_1.parquet,db/org/c2/20251015/logs/14/tbl_3.parquet",
+		"db/org/c1/20251015/logs/15/tbl_2.parquet",
+		"db/org/c1/20251016/logs/0/tbl_4.parquet",
+	}
+	if got := hours(false); !slices.Equal(got, oldest) {
+		t.Errorf("ByHour(oldest first) = %q, want %q", got, oldest)
+	}
+	newest := slices.Clone(oldest)
+	slices.Reverse(newest)
+	if got := hours(true); !slices.Equal(got, newest) {
+		t.Errorf("ByHour(newest first) = %q, want %q", got, newest)
+	}
+}
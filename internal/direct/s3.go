@@ -0,0 +1,124 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package direct
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// DefaultEndpoint is used when neither --s3-endpoint nor an AWS endpoint
+// variable is set.
+const DefaultEndpoint = "s3.amazonaws.com"
+
+// S3Options configures the connection to S3 or an S3-compatible store.
+type S3Options struct {
+	// Endpoint is host[:port] or a URL; http:// disables TLS, as MinIO on
+	// localhost usually needs.
+	Endpoint string
+	// Insecure skips TLS certificate verification.
+	Insecure bool
+}
+
+// EndpointFromEnv returns the endpoint named by AWS_ENDPOINT_URL_S3 or
+// AWS_ENDPOINT_URL, or DefaultEndpoint.
+func EndpointFromEnv() string {
+	for _, name := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return DefaultEndpoint
+}
+
+// S3Store is a Store backed by an S3 bucket, which can also be written to.
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store connects to the bucket of loc. Credentials are taken from the
+// AWS_* or MINIO_* environment variables, the shared AWS credentials file,
+// or the instance role, in that order.
+func NewS3Store(loc Location, opts S3Options) (*S3Store, error) {
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = EndpointFromEnv()
+	}
+	secure := true
+	if rest, ok := strings.CutPrefix(endpoint, "http://"); ok {
+		endpoint, secure = rest, false
+	} else {
+		endpoint = strings.TrimPrefix(endpoint, "https://")
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+			&credentials.FileAWSCredentials{},
+			&credentials.IAM{},
+		}),
+		Secure:    secure,
+		Region:    os.Getenv("AWS_REGION"),
+		Transport: transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return &S3Store{client: client, bucket: loc.Bucket}, nil
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Object, []string, error) {
+	var objects []Object
+	var prefixes []string
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if info.Err != nil {
+			return nil, nil, info.Err
+		}
+		if strings.HasSuffix(info.Key, "/") {
+			prefixes = append(prefixes, info.Key)
+			continue
+		}
+		objects = append(objects, Object{Key: info.Key, Size: info.Size})
+	}
+	return objects, prefixes, nil
+}
+
+func (s *S3Store) Open(ctx context.Context, obj Object) (ReadAtCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, obj.Key, minio.GetObjectOptions{})
+}
+
+// Put uploads data to key.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fieldnames maps attribute names between their OpenTelemetry
+// spelling (k8s.pod.name) and the label form the query API uses for them
+// (k8s_pod_name). Only keys are ever translated: values, line filters and
+// regexes are matched exactly as given.
+package fieldnames
+
+import (
+	"slices"
+	"strings"
+)
+
+// Label returns the query API's label for an attribute name, which is the
+// name with dots replaced by underscores. Names already in label form are
+// returned unchanged.
+func Label(name string) string {
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+// Equal reports whether a and b name the same attribute in either spelling.
+func Equal(a, b string) bool {
+	return a == b || Label(a) == Label(b)
+}
+
+// Lookup returns the value of the attribute name in tags and the key it is
+// stored under. An exact match wins; otherwise name matches a key in either
+// spelling, so k8s.pod.name finds k8s_pod_name and the reverse. When several
+// keys share a label the first in sorted order is used.
+func Lookup(tags map[string]any, name string) (any, string, bool) {
+	if v, ok := tags[name]; ok {
+		return v, name, true
+	}
+	label := Label(name)
+	if v, ok := tags[label]; ok {
+		return v, label, true
+	}
+	var keys []string
+	for k := range tags {
+		if Label(k) == label {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, "", false
+	}
+	k := slices.Min(keys)
+	return tags[k], k, true
+}
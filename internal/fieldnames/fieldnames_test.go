@@ -0,0 +1,67 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fieldnames
+
+import "testing"
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"resource.service.name", "resource_service_name"},
+		{"log.level", "log_level"},
+		{"no_dots_here", "no_dots_here"},
+		{"a.b.c.d", "a_b_c_d"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Label(tt.input); got != tt.expected {
+			t.Errorf("Label(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tags := map[string]any{"k8s_pod_name": "api-1", "http.route": "/cart", "service": "cart"}
+	tests := []struct {
+		name  string
+		value any
+		key   string
+		found bool
+	}{
+		{"k8s_pod_name", "api-1", "k8s_pod_name", true},
+		{"k8s.pod.name", "api-1", "k8s_pod_name", true},
+		{"http_route", "/cart", "http.route", true},
+		{"http.route", "/cart", "http.route", true},
+		{"service", "cart", "service", true},
+		{"missing.key", nil, "", false},
+	}
+	for _, tt := range tests {
+		v, key, ok := Lookup(tags, tt.name)
+		if v != tt.value || key != tt.key || ok != tt.found {
+			t.Errorf("Lookup(%q) = %v, %q, %v; want %v, %q, %v", tt.name, v, key, ok, tt.value, tt.key, tt.found)
+		}
+	}
+	ambiguous := map[string]any{"a.b_c": 1, "a_b.c": 2, "a.b.c": 3}
+	for range 20 {
+		if v, key, _ := Lookup(ambiguous, "a_b_c"); v != 3 || key != "a.b.c" {
+			t.Fatalf("Lookup(a_b_c) = %v, %q; want the first key in sorted order", v, key)
+		}
+	}
+	if !Equal("k8s.pod.name", "k8s_pod_name") || Equal("k8s.pod", "k8s_pod_name") {
+		t.Error("Equal does not compare spellings")
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lineformat parses log lines in common archive formats (ndjson,
+// logfmt, syslog, Apache/nginx access logs and plain text with a leading
+// timestamp) into a timestamp, severity, body and attributes.
+package lineformat
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/logfile"
+	"github.com/lakerunner/cli/internal/logparse"
+)
+
+// Formats are the supported format names, in the order Detect tries them.
+var Formats = []string{"ndjson", "syslog", "combined", "logfmt", "text"}
+
+// Entry is one parsed log entry. Time is zero when the line carried none.
+type Entry struct {
+	Time       time.Time
+	Level      string
+	Body       string
+	Service    string
+	Attributes map[string]any
+}
+
+// Options tunes parsing.
+type Options struct {
+	// TimestampRegex finds the timestamp of text lines, in a group named ts
+	// or the whole match. It may also have level and msg groups.
+	TimestampRegex string
+	// TimeLayout is the Go layout of text timestamps; common layouts are
+	// tried when it is empty.
+	TimeLayout string
+	// Location is used for timestamps without a zone. Defaults to Local.
+	Location *time.Location
+	// Now dates BSD syslog lines, which have no year. Defaults to time.Now.
+	Now time.Time
+}
+
+// Parser parses lines of one format.
+type Parser struct {
+	format string
+	parse  func(line string) (Entry, bool)
+}
+
+// Format returns the name of the parser's format.
+func (p *Parser) Format() string { return p.format }
+
+// Parse parses line, returning false when it is not in the parser's format.
+func (p *Parser) Parse(line string) (Entry, bool) { return p.parse(line) }
+
+// Multiline reports whether lines that do not parse continue the previous
+// entry, as stack traces in text logs do.
+func (p *Parser) Multiline() bool { return p.format == "text" }
+
+// defaultTimestampRegex matches a timestamp at the start of a line, such as
+// 2026-10-15 14:00:00,123 or 2026-10-15T14:00:00.123Z, optionally bracketed.
+const defaultTimestampRegex = `^\[?(?P<ts>\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\]?`
+
+// textLayouts are tried in order for timestamps without --time-layout.
+var textLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+	time.ANSIC,
+	time.StampMicro,
+}
+
+// New returns a parser for format, one of Formats.
+func New(format string, opts Options) (*Parser, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	switch format {
+	case "ndjson":
+		return &Parser{format, func(line string) (Entry, bool) { return parseNDJSON(line, opts) }}, nil
+	case "logfmt":
+		return &Parser{format, func(line string) (Entry, bool) { return parseLogfmt(line, opts) }}, nil
+	case "syslog":
+		return &Parser{format, func(line string) (Entry, bool) { return parseSyslog(line, opts) }}, nil
+	case "combined":
+		return &Parser{format, func(line string) (Entry, bool) { return parseCombined(line) }}, nil
+	case "text":
+		expr := opts.TimestampRegex
+		if expr == "" {
+			expr = defaultTimestampRegex
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp regex %q: %w", expr, err)
+		}
+		return &Parser{format, func(line string) (Entry, bool) { return parseText(line, re, opts) }}, nil
+	}
+	return nil, fmt.Errorf("invalid format %q: expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Detect returns the format most of the sample lines parse as, trying
+// Formats in order; text matches anything.
+func Detect(sample []string, opts Options) string {
+	best, bestCount := "text", 0
+	for _, format := range Formats[:len(Formats)-1] {
+		p, _ := New(format, opts)
+		count := 0
+		for _, line := range sample {
+			if _, ok := p.Parse(line); ok {
+				count++
+			}
+		}
+		if count > bestCount && count*2 > len(sample) {
+			best, bestCount = format, count
+		}
+	}
+	return best
+}
+
+// Field names that hold the timestamp, level, body and service in JSON and
+// logfmt lines, most common first. logfmt keys are compared after replacing
+// characters other than letters, digits and underscores with underscores.
+var (
+	timeFields    = []string{"timestamp", "time", "ts", "@timestamp", "datetime", "date", "t"}
+	levelFields   = []string{"level", "lvl", "severity", "log.level", "loglevel", "severity_text"}
+	bodyFields    = []string{"message", "msg", "body", "log", "event"}
+	serviceFields = []string{"service", "service.name", "app", "application", "logger_name"}
+)
+
+// fromFields builds an entry from decoded fields, moving the first time,
+// level, body and service fields out of the attributes.
+func fromFields(fields map[string]any, sanitized bool, opts Options) Entry {
+	take := func(names []string) (any, bool) {
+		for _, name := range names {
+			if sanitized {
+				name = sanitize(name)
+			}
+			if v, ok := fields[name]; ok && v != nil && v != "" {
+				delete(fields, name)
+				return v, true
+			}
+		}
+		return nil, false
+	}
+	e := Entry{Attributes: fields}
+	if v, ok := take(timeFields); ok {
+		if t, ok := parseTimeValue(v, opts); ok {
+			e.Time = t
+		} else {
+			fields["timestamp"] = v
+		}
+	}
+	if v, ok := take(levelFields); ok {
+		e.Level = NormalizeLevel(fmt.Sprint(v))
+	}
+	if v, ok := take(bodyFields); ok {
+		e.Body = fmt.Sprint(v)
+	}
+	if v, ok := take(serviceFields); ok {
+		e.Service = fmt.Sprint(v)
+	}
+	return e
+}
+
+func sanitize(k string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+// parseTimeValue reads epoch seconds, milliseconds, microseconds or
+// nanoseconds (told apart by magnitude) or a formatted time.
+func parseTimeValue(v any, opts Options) (time.Time, bool) {
+	var f float64
+	switch tv := v.(type) {
+	case float64:
+		f = tv
+	case int64:
+		f = float64(tv)
+	case string:
+		n, err := strconv.ParseFloat(tv, 64)
+		if err != nil {
+			return parseTime(tv, "", opts.Location)
+		}
+		f = n
+	default:
+		return time.Time{}, false
+	}
+	switch {
+	case f >= 1e17:
+		return time.Unix(0, int64(f)), true
+	case f >= 1e14:
+		return time.UnixMicro(int64(f)), true
+	case f >= 1e11:
+		return time.UnixMilli(int64(f)), true
+	case f > 0:
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// parseTime parses s with layout, or with textLayouts when layout is empty.
+// Comma decimal separators, as in log4j's default layout, are accepted.
+func parseTime(s, layout string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, ','); i > 0 && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9' {
+		s = s[:i] + "." + s[i+1:]
+	}
+	layouts := textLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NormalizeLevel maps the many spellings of a severity to TRACE, DEBUG,
+// INFO, WARN, ERROR or FATAL. Unknown values are upper-cased.
+func NormalizeLevel(level string) string {
+	switch l := strings.ToUpper(strings.TrimSpace(level)); l {
+	case "TRACE", "FINEST", "FINER":
+		return "TRACE"
+	case "DEBUG", "DBG", "FINE", "D":
+		return "DEBUG"
+	case "INFO", "INF", "INFORMATION", "NOTICE", "I":
+		return "INFO"
+	case "WARN", "WARNING", "WRN", "W":
+		return "WARN"
+	case "ERROR", "ERR", "SEVERE", "E":
+		return "ERROR"
+	case "FATAL", "CRITICAL", "CRIT", "PANIC", "EMERG", "EMERGENCY", "ALERT", "F":
+		return "FATAL"
+	default:
+		return l
+	}
+}
+
+func parseNDJSON(line string, opts Options) (Entry, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return Entry{}, false
+	}
+	data, err := logfile.ParseLine(line)
+	if err != nil || data == nil {
+		return Entry{}, false
+	}
+	tags, _ := data["tags"].(map[string]any)
+	message, level, service, rest := logfile.SplitTags(tags)
+	for name, v := range map[string]string{"message": message, "level": level, "service": service} {
+		if v != "" {
+			rest[name] = v
+		}
+	}
+	e := fromFields(rest, false, opts)
+	if ns, ok := data["timestamp_ns"].(int64); ok {
+		e.Time = time.Unix(0, ns)
+	}
+	return e, true
+}
+
+func parseLogfmt(line string, opts Options) (Entry, bool) {
+	stage := logparse.Stage{Kind: logparse.KindLogfmt}
+	extracted := stage.Extract(line)
+	fields := make(map[string]any, len(extracted))
+	for k, v := range extracted {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	e := fromFields(fields, true, opts)
+	// Plain text has words but no pairs; require the fields logfmt loggers
+	// always write.
+	if e.Time.IsZero() && e.Level == "" && e.Body == "" {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// rfc5424 matches <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+// STRUCTURED-DATA [MSG].
+var rfc5424 = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|(?:\[(?:[^\]\\]|\\.)*\])+)(?: (.*))?$`)
+
+// rfc3164 matches the BSD format: [<PRI>]Mmm dd hh:mm:ss HOST TAG[PID]: MSG.
+var rfc3164 = regexp.MustCompile(`^(?:<(\d{1,3})>)?([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) (\S+) ([^\s:\[]+)(?:\[(\w+)\])?: ?(.*)$`)
+
+// sdElement matches one structured data element and its parameters.
+var (
+	sdElement = regexp.MustCompile(`\[([^\s\]]+)((?:\s+[^\s=\]]+="(?:[^"\\]|\\.)*")*)\]`)
+	sdParam   = regexp.MustCompile(`([^\s=\]]+)="((?:[^"\\]|\\.)*)"`)
+)
+
+// syslogLevels maps syslog severities 0 (emergency) to 7 (debug).
+var syslogLevels = []string{"FATAL", "FATAL", "FATAL", "ERROR", "WARN", "INFO", "INFO", "DEBUG"}
+
+func nilValue(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+func setPriority(e *Entry, pri string) {
+	n, err := strconv.Atoi(pri)
+	if err != nil || n > 191 {
+		return
+	}
+	e.Level = syslogLevels[n%8]
+	e.Attributes["syslog.facility"] = int64(n / 8)
+	e.Attributes["syslog.severity"] = int64(n % 8)
+}
+
+func parseSyslog(line string, opts Options) (Entry, bool) {
+	if m := rfc5424.FindStringSubmatch(line); m != nil {
+		t, err := time.Parse(time.RFC3339Nano, m[2])
+		if err != nil && m[2] != "-" {
+			return Entry{}, false
+		}
+		e := Entry{Time: t, Body: strings.TrimPrefix(m[8], "\ufeff"), Service: nilValue(m[4]), Attributes: map[string]any{}}
+		setPriority(&e, m[1])
+		for key, value := range map[string]string{"host.name": m[3], "process.pid": m[5], "syslog.msgid": m[6]} {
+			if v := nilValue(value); v != "" {
+				e.Attributes[key] = v
+			}
+		}
+		for _, el := range sdElement.FindAllStringSubmatch(m[7], -1) {
+			for _, p := range sdParam.FindAllStringSubmatch(el[2], -1) {
+				e.Attributes[el[1]+"."+p[1]] = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\]`, `]`).Replace(p[2])
+			}
+		}
+		return e, true
+	}
+	if m := rfc3164.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation(time.Stamp, m[2], opts.Location)
+		if err != nil {
+			return Entry{}, false
+		}
+		// BSD timestamps have no year: take the latest one not in the future.
+		t = t.AddDate(opts.Now.In(opts.Location).Year(), 0, 0)
+		if t.After(opts.Now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		e := Entry{Time: t, Body: m[6], Service: m[4], Level: "INFO", Attributes: map[string]any{"host.name": m[3]}}
+		if m[5] != "" {
+			e.Attributes["process.pid"] = m[5]
+		}
+		setPriority(&e, m[1])
+		return e, true
+	}
+	return Entry{}, false
+}
+
+// combinedLog matches the NCSA common and combined formats Apache and nginx
+// write by default.
+var combinedLog = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-)(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)")?`)
+
+func parseCombined(line string) (Entry, bool) {
+	m := combinedLog.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	t, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[4])
+	if err != nil {
+		return Entry{}, false
+	}
+	status, _ := strconv.ParseInt(m[6], 10, 64)
+	e := Entry{Time: t, Body: line, Level: "INFO", Attributes: map[string]any{
+		"client.address":            m[1],
+		"http.response.status_code": status,
+	}}
+	switch {
+	case status >= 500:
+		e.Level = "ERROR"
+	case status >= 400:
+		e.Level = "WARN"
+	}
+	if user := nilValue(m[3]); user != "" {
+		e.Attributes["user.name"] = user
+	}
+	if size, err := strconv.ParseInt(m[7], 10, 64); err == nil {
+		e.Attributes["http.response.body.size"] = size
+	}
+	if parts := strings.Fields(m[5]); len(parts) == 3 {
+		e.Attributes["http.request.method"] = parts[0]
+		e.Attributes["url.path"] = parts[1]
+		e.Attributes["network.protocol.version"] = strings.TrimPrefix(parts[2], "HTTP/")
+	}
+	if referer := nilValue(m[8]); referer != "" {
+		e.Attributes["http.request.header.referer"] = referer
+	}
+	if agent := nilValue(m[9]); agent != "" {
+		e.Attributes["user_agent.original"] = agent
+	}
+	return e, true
+}
+
+// levelWord finds a severity word in a text line.
+var levelWord = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|SEVERE|FATAL|CRITICAL)\b`)
+
+func parseText(line string, re *regexp.Regexp, opts Options) (Entry, bool) {
+	m := re.FindStringSubmatchIndex(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	group := func(name string) (string, bool) {
+		i := re.SubexpIndex(name)
+		if i < 0 || m[2*i] < 0 {
+			return "", false
+		}
+		return line[m[2*i]:m[2*i+1]], true
+	}
+	ts, ok := group("ts")
+	if !ok {
+		ts = line[m[0]:m[1]]
+	}
+	t, ok := parseTime(ts, opts.TimeLayout, opts.Location)
+	if !ok {
+		return Entry{}, false
+	}
+	e := Entry{Time: t, Body: line, Attributes: map[string]any{}}
+	if msg, ok := group("msg"); ok {
+		e.Body = msg
+	}
+	if level, ok := group("level"); ok {
+		e.Level = NormalizeLevel(level)
+	} else if w := levelWord.FindString(line[m[1]:]); w != "" {
+		e.Level = NormalizeLevel(w)
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" && !slices.Contains([]string{"ts", "level", "msg"}, name) {
+			if v, ok := group(name); ok {
+				e.Attributes[name] = v
+			}
+		}
+	}
+	return e, true
+}
@@ -0,0 +1,117 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lineformat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	utc := Options{Location: time.UTC, Now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		format string
+		line   string
+		want   Entry
+	}{
+		{"ndjson", `{"time":"2026-10-15T14:00:00.5Z","lvl":"warning","msg":"disk low","app":"api","free":"5%"}`,
+			Entry{Time: time.Date(2026, 10, 15, 14, 0, 0, 5e8, time.UTC), Level: "WARN", Body: "disk low", Service: "api", Attributes: map[string]any{"free": "5%"}}},
+		{"ndjson", `{"timestamp":1760536800000,"tags":{"log_message":"exported","resource_service_name":"cart"}}`,
+			Entry{Time: time.UnixMilli(1760536800000), Body: "exported", Service: "cart", Attributes: map[string]any{}}},
+		{"logfmt", `ts=1760536800.25 level=error msg="payment failed" order=42 log.origin=pay.go`,
+			Entry{Time: time.Unix(1760536800, 25e7), Level: "ERROR", Body: "payment failed", Attributes: map[string]any{"order": "42", "log_origin": "pay.go"}}},
+		{"syslog", `<165>1 2026-10-15T14:00:00.003Z web1 nginx 812 ID47 [exampleSDID@32473 iut="3" eventSource="App\"x\""] upstream timed out`,
+			Entry{Time: time.Date(2026, 10, 15, 14, 0, 0, 3e6, time.UTC), Level: "INFO", Body: "upstream timed out", Service: "nginx", Attributes: map[string]any{
+				"syslog.facility": int64(20), "syslog.severity": int64(5), "host.name": "web1", "process.pid": "812", "syslog.msgid": "ID47",
+				"exampleSDID@32473.iut": "3", "exampleSDID@32473.eventSource": `App"x"`,
+			}}},
+		{"syslog", `<11>1 2026-10-15T14:00:00Z - - - - -`,
+			Entry{Time: time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC), Level: "ERROR", Attributes: map[string]any{"syslog.facility": int64(1), "syslog.severity": int64(3)}}},
+		{"syslog", `Dec 31 23:59:58 db2 postgres[4411]: checkpoint complete`,
+			Entry{Time: time.Date(2025, 12, 31, 23, 59, 58, 0, time.UTC), Level: "INFO", Body: "checkpoint complete", Service: "postgres", Attributes: map[string]any{"host.name": "db2", "process.pid": "4411"}}},
+		{"combined", `203.0.113.9 - alice [15/Oct/2026:14:00:00 +0200] "GET /cart?id=1 HTTP/1.1" 503 512 "https://shop/" "curl/8.5"`,
+			Entry{Time: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC), Level: "ERROR",
+				Body: `203.0.113.9 - alice [15/Oct/2026:14:00:00 +0200] "GET /cart?id=1 HTTP/1.1" 503 512 "https://shop/" "curl/8.5"`,
+				Attributes: map[string]any{
+					"client.address": "203.0.113.9", "user.name": "alice", "http.response.status_code": int64(503), "http.response.body.size": int64(512),
+					"http.request.method": "GET", "url.path": "/cart?id=1", "network.protocol.version": "1.1",
+					"http.request.header.referer": "https://shop/", "user_agent.original": "curl/8.5",
+				}}},
+		{"combined", `10.0.0.1 - - [15/Oct/2026:14:00:00 +0000] "GET / HTTP/1.0" 200 -`,
+			Entry{Time: time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC), Level: "INFO", Body: `10.0.0.1 - - [15/Oct/2026:14:00:00 +0000] "GET / HTTP/1.0" 200 -`,
+				Attributes: map[string]any{"client.address": "10.0.0.1", "http.response.status_code": int64(200), "http.request.method": "GET", "url.path": "/", "network.protocol.version": "1.0"}}},
+		{"text", `2026-10-15 14:00:00,123 [main] WARN  c.e.Pool - pool exhausted`,
+			Entry{Time: time.Date(2026, 10, 15, 14, 0, 0, 123e6, time.UTC), Level: "WARN", Body: `2026-10-15 14:00:00,123 [main] WARN  c.e.Pool - pool exhausted`, Attributes: map[string]any{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			p, err := New(tt.format, utc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := p.Parse(tt.line)
+			if !ok {
+				t.Fatalf("%q did not parse", tt.line)
+			}
+			if !got.Time.Equal(tt.want.Time) || got.Level != tt.want.Level || got.Body != tt.want.Body || got.Service != tt.want.Service {
+				t.Errorf("got %v %q %q %q, want %v %q %q %q", got.Time, got.Level, got.Body, got.Service, tt.want.Time, tt.want.Level, tt.want.Body, tt.want.Service)
+			}
+			if len(got.Attributes) != len(tt.want.Attributes) {
+				t.Errorf("attributes = %v, want %v", got.Attributes, tt.want.Attributes)
+			}
+			for k, v := range tt.want.Attributes {
+				if got.Attributes[k] != v {
+					t.Errorf("attribute %s = %#v, want %#v", k, got.Attributes[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestTextRegex(t *testing.T) {
+	p, err := New("text", Options{
+		TimestampRegex: `^(?P<ts>\d{2}/\d{2}/\d{4} \d{2}:\d{2}:\d{2}) (?P<level>\w+) (?P<thread>\S+) (?P<msg>.*)`,
+		TimeLayout:     "01/02/2006 15:04:05",
+		Location:       time.UTC,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := p.Parse("10/15/2026 14:00:00 err worker-3 job failed")
+	if !ok || !e.Time.Equal(time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)) || e.Level != "ERROR" || e.Body != "job failed" || e.Attributes["thread"] != "worker-3" {
+		t.Errorf("parsed %+v, %v", e, ok)
+	}
+	if _, ok := p.Parse("\tat com.example.Job.run(Job.java:42)"); ok || !p.Multiline() {
+		t.Error("continuation lines should not parse")
+	}
+	if _, err := New("text", Options{TimestampRegex: "("}); err == nil {
+		t.Error("expected an invalid regex error")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string][]string{
+		"ndjson":   {`{"msg":"a"}`, `{"msg":"b"}`},
+		"logfmt":   {`time=2026-10-15T14:00:00Z level=info msg=a`, `time=2026-10-15T14:00:01Z level=info msg=b`},
+		"syslog":   {`<34>1 2026-10-15T14:00:00Z h app - - - a`, `Oct 15 14:00:00 h app: b`},
+		"combined": {`1.2.3.4 - - [15/Oct/2026:14:00:00 +0000] "GET / HTTP/1.1" 200 1 "-" "x"`},
+		"text":     {`2026-10-15 14:00:00 INFO started`, `plain words with a=b`, `more words`},
+	}
+	for want, sample := range tests {
+		if got := Detect(sample, Options{}); got != want {
+			t.Errorf("Detect(%q) = %s, want %s", sample, got, want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logfile reads log entries saved to disk, as newline-delimited JSON
+// or Parquet, into the shape the query API streams: a map holding timestamp
+// (epoch milliseconds), timestamp_ns and a tags map, and writes such entries
+// as Parquet in Lakerunner's column layout.
+package logfile
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column names that hold the timestamp, message, level and service in
+// exports and in Lakerunner's own files, most specific first.
+var (
+	nanosKeys  = []string{"timestamp_ns", "_cardinalhq_tsns", "chq_tsns"}
+	millisKeys = []string{"timestamp", "_cardinalhq_timestamp", "_cardinalhq.timestamp", "chq_timestamp"}
+	canonical  = map[string][]string{
+		"message": {"_cardinalhq_message", "_cardinalhq.message", "log_message"},
+		"level":   {"_cardinalhq_level", "_cardinalhq.level", "log_level"},
+		"service": {"resource_service_name", "resource.service.name"},
+	}
+)
+
+// textTimeLayouts are the timestamp forms written by `logs get -o json`.
+var textTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+// IsParquet reports whether path names a Parquet file.
+func IsParquet(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".parquet")
+}
+
+// Read calls fn for each entry in the file at path. Files ending in .parquet
+// are read as Parquet, anything else as newline-delimited JSON, gunzipped
+// first when it ends in .gz.
+func Read(path string, fn func(data map[string]any) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if IsParquet(path) {
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		return ReadParquet(f, info.Size(), fn)
+	}
+	var r io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+	return ReadNDJSON(r, fn)
+}
+
+// ReadNDJSON calls fn for each line of r. A line may be a query API event
+// ({"type":"event","data":{...}}), its data object ({"timestamp":...,
+// "tags":{...}}) or a flat row as written by `logs get -o json`.
+func ReadNDJSON(r io.Reader, fn func(data map[string]any) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data, err := ParseLine(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if data == nil {
+			continue
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+	return nil
+}
+
+// ParseLine parses one line of newline-delimited JSON into entry data, or
+// returns nil for blank lines and stream control messages such as
+// {"type":"done"}.
+func ParseLine(line string) (map[string]any, error) {
+	text := strings.TrimSpace(line)
+	if text == "" {
+		return nil, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if data, ok := obj["data"].(map[string]any); ok {
+		obj = data
+	} else if _, isEvent := obj["type"]; isEvent {
+		return nil, nil
+	}
+	return normalize(obj), nil
+}
+
+// normalize turns a decoded object into entry data. Objects with a tags map
+// keep it; otherwise every field other than the timestamps becomes a tag.
+func normalize(obj map[string]any) map[string]any {
+	tags, ok := obj["tags"].(map[string]any)
+	if !ok {
+		tags = make(map[string]any, len(obj))
+		for k, v := range obj {
+			tags[k] = v
+		}
+	}
+	data := map[string]any{"tags": tags}
+
+	var ns int64
+	var found bool
+	for _, key := range nanosKeys {
+		if ns, found = toNanos(obj[key], time.Nanosecond); found {
+			break
+		}
+	}
+	if !found {
+		for _, key := range millisKeys {
+			if ns, found = toNanos(obj[key], time.Millisecond); found {
+				break
+			}
+		}
+	}
+	for _, key := range slices.Concat(nanosKeys, millisKeys) {
+		delete(tags, key)
+	}
+	if found {
+		data["timestamp"] = ns / int64(time.Millisecond)
+		data["timestamp_ns"] = ns
+	}
+
+	for k, v := range tags {
+		tags[k] = fromJSONNumber(v)
+	}
+	for name, keys := range canonical {
+		if _, ok := tags[name]; ok {
+			continue
+		}
+		for _, key := range keys {
+			if v, ok := tags[key]; ok {
+				tags[name] = v
+				break
+			}
+		}
+	}
+	return data
+}
+
+// toNanos converts a timestamp value to epoch nanoseconds. Integers are taken
+// in unit unless their magnitude shows a finer one; strings may also be
+// formatted times.
+func toNanos(v any, unit time.Duration) (int64, bool) {
+	var n int64
+	switch tv := v.(type) {
+	case nil:
+		return 0, false
+	case time.Time:
+		return tv.UnixNano(), true
+	case int64:
+		n = tv
+	case int32:
+		n = int64(tv)
+	case float64:
+		n = int64(tv)
+	case json.Number:
+		i, err := tv.Int64()
+		if err != nil {
+			f, err := tv.Float64()
+			if err != nil {
+				return 0, false
+			}
+			i = int64(f)
+		}
+		n = i
+	case string:
+		if i, err := strconv.ParseInt(tv, 10, 64); err == nil {
+			n = i
+			break
+		}
+		for _, layout := range textTimeLayouts {
+			if t, err := time.ParseInLocation(layout, tv, time.Local); err == nil {
+				return t.UnixNano(), true
+			}
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+	// Epoch milliseconds are ~1e12 today, microseconds ~1e15, nanoseconds ~1e18.
+	switch {
+	case n >= 1e17:
+		return n, true
+	case n >= 1e14:
+		return n * int64(time.Microsecond), true
+	case n >= 1e11:
+		return n * int64(time.Millisecond), true
+	}
+	return n * int64(unit), true
+}
+
+// fromJSONNumber converts json.Number values, including nested ones, to
+// float64 as encoding/json would by default.
+func fromJSONNumber(v any) any {
+	switch tv := v.(type) {
+	case json.Number:
+		f, _ := tv.Float64()
+		return f
+	case map[string]any:
+		for k, e := range tv {
+			tv[k] = fromJSONNumber(e)
+		}
+	case []any:
+		for i, e := range tv {
+			tv[i] = fromJSONNumber(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,243 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func collect(t *testing.T, read func(fn func(map[string]any) error) error) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	if err := read(func(data map[string]any) error {
+		out = append(out, data)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestReadNDJSON(t *testing.T) {
+	local := time.Date(2026, 10, 15, 14, 0, 0, 123000000, time.Local)
+	input := strings.Join([]string{
+		`{"id":"1","type":"event","data":{"timestamp":1760536800000,"timestamp_ns":1760536800123456789,"tags":{"service":"api","message":"one","status":500}}}`,
+		`{"type":"done"}`,
+		``,
+		`{"timestamp":1760536801000,"tags":{"resource_service_name":"cart","log_message":"two"}}`,
+		`{"timestamp":"` + local.Format("2006-01-02 15:04:05.000") + `","level":"ERROR","service":"web","message":"three"}`,
+	}, "\n")
+
+	entries := collect(t, func(fn func(map[string]any) error) error {
+		return ReadNDJSON(strings.NewReader(input), fn)
+	})
+	if len(entries) != 3 {
+		t.Fatalf("read %d entries, want 3", len(entries))
+	}
+
+	first := entries[0]
+	if first["timestamp_ns"] != int64(1760536800123456789) || first["timestamp"] != int64(1760536800123) {
+		t.Errorf("first timestamps = %v, %v", first["timestamp"], first["timestamp_ns"])
+	}
+	if tags := first["tags"].(map[string]any); tags["status"] != float64(500) {
+		t.Errorf("numbers should decode as float64, got %#v", tags["status"])
+	}
+
+	second := entries[1]["tags"].(map[string]any)
+	if second["service"] != "cart" || second["message"] != "two" {
+		t.Errorf("canonical tags not filled in: %v", second)
+	}
+
+	third := entries[2]
+	if third["timestamp_ns"] != local.UnixNano() {
+		t.Errorf("text timestamp = %v, want %d", third["timestamp_ns"], local.UnixNano())
+	}
+	tags := third["tags"].(map[string]any)
+	if _, ok := tags["timestamp"]; ok || tags["level"] != "ERROR" || tags["message"] != "three" {
+		t.Errorf("flat row tags = %v", tags)
+	}
+}
+
+func TestReadNDJSONInvalid(t *testing.T) {
+	err := ReadNDJSON(strings.NewReader("{\"timestamp\":1}\n{oops\n"), func(map[string]any) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected line 2 error, got %v", err)
+	}
+}
+
+func TestReadGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(`{"timestamp":1760536800000,"tags":{"message":"zipped"}}` + "\n"))
+	_ = gz.Close()
+	path := filepath.Join(t.TempDir(), "export.ndjson.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries := collect(t, func(fn func(map[string]any) error) error { return Read(path, fn) })
+	if len(entries) != 1 || entries[0]["tags"].(map[string]any)["message"] != "zipped" {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+type parquetRow struct {
+	Timestamp int64   `parquet:"_cardinalhq_timestamp"`
+	Message   string  `parquet:"_cardinalhq_message"`
+	Level     string  `parquet:"_cardinalhq_level"`
+	Service   *string `parquet:"resource_service_name,optional"`
+	Status    int32   `parquet:"status"`
+}
+
+func TestReadParquet(t *testing.T) {
+	api := "api"
+	rows := []parquetRow{
+		{Timestamp: 1760536800000, Message: "one", Level: "ERROR", Service: &api, Status: 500},
+		{Timestamp: 1760536801000, Message: "two", Level: "INFO", Status: 200},
+	}
+	path := filepath.Join(t.TempDir(), "logs.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parquet.Write(f, rows); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	entries := collect(t, func(fn func(map[string]any) error) error { return Read(path, fn) })
+	if len(entries) != 2 {
+		t.Fatalf("read %d entries, want 2", len(entries))
+	}
+	if entries[0]["timestamp"] != int64(1760536800000) {
+		t.Errorf("timestamp = %v", entries[0]["timestamp"])
+	}
+	tags := entries[0]["tags"].(map[string]any)
+	if tags["message"] != "one" || tags["level"] != "ERROR" || tags["service"] != "api" || tags["status"] != int64(500) {
+		t.Errorf("tags = %v", tags)
+	}
+	if _, ok := entries[1]["tags"].(map[string]any)["service"]; ok {
+		t.Errorf("null column should be absent: %v", entries[1]["tags"])
+	}
+}
+
+type segmentRow struct {
+	Timestamp int64  `parquet:"chq_timestamp"`
+	Message   string `parquet:"log_message"`
+	Level     string `parquet:"log_level"`
+	Service   string `parquet:"resource_service_name"`
+}
+
+func TestReadParquetWhere(t *testing.T) {
+	// One row group per slice.
+	groups := [][]segmentRow{
+		{{1760536800000, "a1", "INFO", "api"}, {1760536801000, "a2", "INFO", "api"}},
+		{{1760536802000, "c1", "ERROR", "cart"}, {1760536803000, "c2", "WARN", "cart"}},
+		{{1760536900000, "a3", "ERROR", "api"}, {1760536901000, "a4", "ERROR", "api"}},
+	}
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[segmentRow](&buf)
+	for _, g := range groups {
+		if _, err := w.Write(g); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ms := int64(time.Millisecond)
+	tests := []struct {
+		name string
+		pred *Predicate
+		want string
+	}{
+		{"none", nil, "a1 a2 c1 c2 a3 a4"},
+		{"service", &Predicate{Values: map[string][]string{"service": {"cart"}}}, "c1 c2"},
+		{"services", &Predicate{Values: map[string][]string{"service": {"api", "zzz"}}}, "a1 a2 a3 a4"},
+		{"level", &Predicate{Values: map[string][]string{"level": {"DEBUG"}}}, ""},
+		// Groups are skipped, not filtered: c2 comes along with c1.
+		{"level in range", &Predicate{Values: map[string][]string{"level": {"ERROR"}}}, "c1 c2 a3 a4"},
+		{"start", &Predicate{StartNs: 1760536802500 * ms}, "c1 c2 a3 a4"},
+		{"end", &Predicate{EndNs: 1760536802000 * ms}, "a1 a2"},
+		{"window", &Predicate{StartNs: 1760536801500 * ms, EndNs: 1760536850000 * ms}, "c1 c2"},
+		{"empty value", &Predicate{Values: map[string][]string{"service": {""}}}, "a1 a2 c1 c2 a3 a4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := collect(t, func(fn func(map[string]any) error) error {
+				return ReadParquetWhere(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tt.pred, fn)
+			})
+			var got []string
+			for _, e := range entries {
+				got = append(got, e["tags"].(map[string]any)["message"].(string))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("read %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	input := strings.Join([]string{
+		`{"timestamp_ns":1760536800123456789,"tags":{"message":"one","level":"ERROR","service":"api","k8s.pod.name":"api-1","status":500}}`,
+		`{"timestamp":1760536801000,"tags":{"log_message":"two","resource_service_name":"cart"}}`,
+	}, "\n")
+	entries := collect(t, func(fn func(map[string]any) error) error {
+		return ReadNDJSON(strings.NewReader(input), fn)
+	})
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var columns []string
+	for _, path := range f.Schema().Columns() {
+		columns = append(columns, strings.Join(path, "."))
+	}
+	want := "chq_timestamp chq_tsns k8s_pod_name log_level log_message resource_service_name status"
+	if got := strings.Join(columns, " "); got != want {
+		t.Errorf("columns = %s, want %s", got, want)
+	}
+
+	read := collect(t, func(fn func(map[string]any) error) error {
+		return ReadParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()), fn)
+	})
+	if len(read) != 2 || read[0]["timestamp_ns"] != int64(1760536800123456789) || read[1]["timestamp"] != int64(1760536801000) {
+		t.Fatalf("read back %v", read)
+	}
+	first, second := read[0]["tags"].(map[string]any), read[1]["tags"].(map[string]any)
+	if first["message"] != "one" || first["service"] != "api" || first["status"] != "500" || first["k8s_pod_name"] != "api-1" {
+		t.Errorf("first tags = %v", first)
+	}
+	if second["message"] != "two" || second["service"] != "cart" {
+		t.Errorf("second tags = %v", second)
+	}
+}
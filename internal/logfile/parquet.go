@@ -0,0 +1,122 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// parquetColumn describes how to decode one leaf column.
+type parquetColumn struct {
+	name string
+	// unit is set for INT64 columns annotated as timestamps.
+	unit time.Duration
+}
+
+// ReadParquet calls fn for each row of a Parquet file with a flat schema.
+// Nested columns are named by joining their path with underscores.
+func ReadParquet(r io.ReaderAt, size int64, fn func(data map[string]any) error) error {
+	return ReadParquetWhere(r, size, nil, fn)
+}
+
+// ReadParquetWhere is ReadParquet, skipping the row groups whose column
+// statistics show they hold no rows matching pred. Rows of the groups that
+// are read are passed to fn unfiltered.
+func ReadParquetWhere(r io.ReaderAt, size int64, pred *Predicate, fn func(data map[string]any) error) error {
+	f, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	schema := f.Schema()
+	var columns []parquetColumn
+	for _, path := range schema.Columns() {
+		col := parquetColumn{name: strings.Join(path, "_")}
+		if leaf, ok := schema.Lookup(path...); ok {
+			if lt := leaf.Node.Type().LogicalType(); lt != nil {
+				if ts, ok := lt.Value.(*format.TimestampType); ok && ts.Unit.Value != nil {
+					col.unit = ts.Unit.Value.Duration()
+				}
+			}
+		}
+		columns = append(columns, col)
+	}
+
+	meta := f.Metadata()
+	rows := make([]parquet.Row, 256)
+	for i, rg := range f.RowGroups() {
+		if pred != nil && i < len(meta.RowGroups) && !pred.keep(columns, meta.RowGroups[i].Columns) {
+			continue
+		}
+		if err := readRowGroup(rg, columns, rows, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRowGroup(rg parquet.RowGroup, columns []parquetColumn, rows []parquet.Row, fn func(data map[string]any) error) error {
+	reader := rg.Rows()
+	defer func() { _ = reader.Close() }()
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			obj := make(map[string]any, len(row))
+			for _, v := range row {
+				if v.IsNull() || v.Column() < 0 || v.Column() >= len(columns) {
+					continue
+				}
+				col := columns[v.Column()]
+				obj[col.name] = parquetValue(v, col)
+			}
+			if err := fn(normalize(obj)); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read parquet rows: %w", err)
+		}
+	}
+}
+
+func parquetValue(v parquet.Value, col parquetColumn) any {
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32:
+		return int64(v.Int32())
+	case parquet.Int64:
+		if col.unit > 0 {
+			return time.Unix(0, v.Int64()*int64(col.unit))
+		}
+		return v.Int64()
+	case parquet.Float:
+		return float64(v.Float())
+	case parquet.Double:
+		return v.Double()
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return string(v.ByteArray())
+	}
+	return v.String()
+}
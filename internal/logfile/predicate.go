@@ -0,0 +1,117 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logfile
+
+import (
+	"encoding/binary"
+	"slices"
+	"time"
+
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// Predicate describes the rows a reader is after, so that whole row groups
+// can be skipped on their column statistics.
+type Predicate struct {
+	// StartNs and EndNs bound the entry timestamp to [StartNs, EndNs). Zero
+	// leaves that side unbounded.
+	StartNs, EndNs int64
+	// Values maps a tag such as service or level to the values it may take.
+	Values map[string][]string
+}
+
+// keep reports whether a row group may hold matching rows. A group is only
+// skipped on the column normalize would take the tag from, and only when
+// that column has statistics and no nulls.
+func (p *Predicate) keep(columns []parquetColumn, chunks []format.ColumnChunk) bool {
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		index[col.name] = i
+	}
+	// first returns the chunk of the first of keys present in the file.
+	first := func(keys []string) (parquetColumn, *format.ColumnMetaData, bool) {
+		for _, key := range keys {
+			if i, ok := index[key]; ok && i < len(chunks) {
+				meta := &chunks[i].MetaData
+				if meta.Statistics.NullCount > 0 {
+					return parquetColumn{}, nil, false
+				}
+				return columns[i], meta, true
+			}
+		}
+		return parquetColumn{}, nil, false
+	}
+
+	if p.StartNs != 0 || p.EndNs != 0 {
+		col, meta, ok := first(nanosKeys)
+		unit := time.Nanosecond
+		if !ok {
+			col, meta, ok = first(millisKeys)
+			unit = time.Millisecond
+		}
+		if ok && meta.Type == format.Int64 {
+			if minNs, maxNs, ok := int64Range(meta.Statistics); ok {
+				minNs, maxNs = col.toNanos(minNs, unit), col.toNanos(maxNs, unit)
+				if (p.EndNs != 0 && minNs >= p.EndNs) || (p.StartNs != 0 && maxNs < p.StartNs) {
+					return false
+				}
+			}
+		}
+	}
+
+	for tag, values := range p.Values {
+		if len(values) == 0 || slices.Contains(values, "") {
+			// Missing tags read as empty strings, which no statistics rule out.
+			continue
+		}
+		_, meta, ok := first(append([]string{tag}, canonical[tag]...))
+		if !ok || meta.Type != format.ByteArray {
+			continue
+		}
+		stats := meta.Statistics
+		if stats.MinValue == nil || stats.MaxValue == nil {
+			continue
+		}
+		lo, hi := string(stats.MinValue), string(stats.MaxValue)
+		if !slices.ContainsFunc(values, func(v string) bool { return v >= lo && v <= hi }) {
+			return false
+		}
+	}
+	return true
+}
+
+// toNanos converts a raw statistics value of the column to nanoseconds the
+// way normalize converts the row values.
+func (col parquetColumn) toNanos(v int64, unit time.Duration) int64 {
+	if col.unit > 0 {
+		return v * int64(col.unit)
+	}
+	ns, _ := toNanos(v, unit)
+	return ns
+}
+
+// int64Range decodes the min and max of an INT64 column chunk.
+func int64Range(stats format.Statistics) (int64, int64, bool) {
+	lo, hi := stats.MinValue, stats.MaxValue
+	if lo == nil || hi == nil {
+		// Older writers only set the deprecated fields, which are correct
+		// for signed integers.
+		lo, hi = stats.Min, stats.Max
+	}
+	if len(lo) != 8 || len(hi) != 8 {
+		return 0, 0, false
+	}
+	return int64(binary.LittleEndian.Uint64(lo)), int64(binary.LittleEndian.Uint64(hi)), true
+}
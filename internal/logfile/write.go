@@ -0,0 +1,118 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logfile
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// columnNames maps the canonical tags to the columns Lakerunner stores them
+// in.
+var columnNames = map[string]string{
+	"message": "log_message",
+	"level":   "log_level",
+	"service": "resource_service_name",
+}
+
+// SplitTags separates the message, level and service tags from the rest.
+// Columns those were filled in from, such as log_message, are dropped.
+func SplitTags(tags map[string]any) (message, level, service string, rest map[string]any) {
+	rest = make(map[string]any, len(tags))
+	for k, v := range tags {
+		if _, ok := columnNames[k]; !ok && !isAlias(k, tags) {
+			rest[k] = v
+		}
+	}
+	str := func(name string) string {
+		if v, ok := tags[name]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	return str("message"), str("level"), str("service"), rest
+}
+
+// WriteParquet writes entries, in the shape Read produces, as one Parquet
+// file with Lakerunner's column names: chq_timestamp (epoch milliseconds),
+// chq_tsns, log_message, log_level, resource_service_name, and one string
+// column per other tag, with dots in its name replaced by underscores.
+func WriteParquet(w io.Writer, entries []map[string]any) error {
+	rows := make([]map[string]any, 0, len(entries))
+	group := parquet.Group{
+		"chq_timestamp": parquet.Leaf(parquet.Int64Type),
+		"chq_tsns":      parquet.Leaf(parquet.Int64Type),
+	}
+	set := func(row map[string]any, name string, v any) {
+		if v == nil || v == "" {
+			return
+		}
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		row[name] = s
+		if _, ok := group[name]; !ok {
+			group[name] = parquet.Optional(parquet.String())
+		}
+	}
+	for _, entry := range entries {
+		ns, _ := entry["timestamp_ns"].(int64)
+		if ns == 0 {
+			ms, _ := entry["timestamp"].(int64)
+			ns = ms * int64(time.Millisecond)
+		}
+		row := map[string]any{"chq_timestamp": ns / int64(time.Millisecond), "chq_tsns": ns}
+		tags, _ := entry["tags"].(map[string]any)
+		message, level, service, rest := SplitTags(tags)
+		set(row, columnNames["message"], message)
+		set(row, columnNames["level"], level)
+		set(row, columnNames["service"], service)
+		for k, v := range rest {
+			name := strings.ReplaceAll(k, ".", "_")
+			if !slices.Contains(slices.Concat(nanosKeys, millisKeys), name) {
+				set(row, name, v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	writer := parquet.NewWriter(w, parquet.NewSchema("logs", group))
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write parquet row: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write parquet file: %w", err)
+	}
+	return nil
+}
+
+// isAlias reports whether tag is one of the columns a canonical tag present
+// in tags was filled in from.
+func isAlias(tag string, tags map[string]any) bool {
+	for name, keys := range canonical {
+		if _, ok := tags[name]; ok && slices.Contains(keys, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/fieldnames"
+	"github.com/lakerunner/cli/internal/logql"
+)
+
+// Supported label filter operators, longest first so parsing is unambiguous.
+var filterOps = []string{">=", "<=", "==", "!=", "=~", "!~", ">", "<", "="}
+
+// labelNameRegex matches a LogQL label name, which a filter key must be once
+// dots are translated.
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Filter is a LogQL label filter such as `| status >= 500` or `| method="GET"`.
+type Filter struct {
+	Key     string
+	Op      string
+	Value   string
+	Numeric bool
+
+	re *regexp.Regexp
+}
+
+// ParseFilter parses a --where expression like `status>=500` or `path=~"/api/.*"`.
+// Comparisons (<, <=, >, >=) and unquoted numeric values compare numerically.
+// Dotted field names are translated to their label form, so http.status
+// filters on http_status.
+func ParseFilter(expr string) (Filter, error) {
+	expr = strings.TrimSpace(expr)
+	keyEnd := strings.IndexAny(expr, "=!<>")
+	if keyEnd <= 0 {
+		return Filter{}, fmt.Errorf("invalid filter %q: expected <field><op><value>", expr)
+	}
+	f := Filter{Key: fieldnames.Label(strings.TrimSpace(expr[:keyEnd]))}
+	if !labelNameRegex.MatchString(f.Key) {
+		return Filter{}, fmt.Errorf("invalid filter %q: field %q must be letters, digits, dots and underscores, not starting with a digit", expr, f.Key)
+	}
+	rest := expr[keyEnd:]
+	for _, op := range filterOps {
+		if strings.HasPrefix(rest, op) {
+			f.Op = op
+			break
+		}
+	}
+	if f.Op == "" {
+		return Filter{}, fmt.Errorf("invalid operator in filter %q", expr)
+	}
+	if f.Op == "==" {
+		f.Op = "="
+		rest = rest[1:]
+	}
+	raw := strings.TrimSpace(rest[len(f.Op):])
+	f.Value = unquote(raw)
+	quoted := f.Value != raw
+
+	switch f.Op {
+	case ">", ">=", "<", "<=":
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return Filter{}, fmt.Errorf("invalid filter %q: %s requires a numeric value", expr, f.Op)
+		}
+		f.Numeric = true
+	case "=", "!=":
+		if _, err := strconv.ParseFloat(f.Value, 64); err == nil && !quoted {
+			f.Numeric = true
+		}
+	case "=~", "!~":
+		re, err := regexp.Compile("^(?:" + f.Value + ")$")
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid regex in filter %q: %w", expr, err)
+		}
+		f.re = re
+	}
+	return f, nil
+}
+
+// LogQL renders the filter as a LogQL pipeline expression.
+func (f Filter) LogQL() string {
+	if f.Numeric {
+		op := f.Op
+		if op == "=" {
+			op = "=="
+		}
+		return fmt.Sprintf("| %s %s %s", f.Key, op, f.Value)
+	}
+	if f.re != nil {
+		return fmt.Sprintf("| %s%s%s", f.Key, f.Op, logql.QuoteRegex(f.Value))
+	}
+	return fmt.Sprintf("| %s%s%s", f.Key, f.Op, logql.Quote(f.Value))
+}
+
+// Match reports whether the filter holds for the given fields. Regex filters are
+// anchored to the whole value, as in LogQL.
+func (f Filter) Match(fields map[string]any) bool {
+	var val string
+	if v, _, ok := fieldnames.Lookup(fields, f.Key); ok && v != nil {
+		val = fmt.Sprintf("%v", v)
+	}
+	if f.Numeric {
+		want, _ := strconv.ParseFloat(f.Value, 64)
+		got, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return f.Op == "!="
+		}
+		switch f.Op {
+		case "=":
+			return got == want
+		case "!=":
+			return got != want
+		case ">":
+			return got > want
+		case ">=":
+			return got >= want
+		case "<":
+			return got < want
+		case "<=":
+			return got <= want
+		}
+		return false
+	}
+	switch f.Op {
+	case "=":
+		return val == f.Value
+	case "!=":
+		return val != f.Value
+	case "=~":
+		return f.re.MatchString(val)
+	case "!~":
+		return !f.re.MatchString(val)
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStage(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Stage
+		logql   string
+		wantErr bool
+	}{
+		{spec: "json", want: Stage{Kind: KindJSON}, logql: "| json"},
+		{spec: "LOGFMT", want: Stage{Kind: KindLogfmt}, logql: "| logfmt"},
+		{
+			spec:  `pattern:"<ip> - <_> <status>"`,
+			want:  Stage{Kind: KindPattern, Pattern: "<ip> - <_> <status>"},
+			logql: `| pattern "<ip> - <_> <status>"`,
+		},
+		{spec: "pattern:<a> <b>", want: Stage{Kind: KindPattern, Pattern: "<a> <b>"}, logql: `| pattern "<a> <b>"`},
+		{spec: `pattern:<a> "<b>"\`, want: Stage{Kind: KindPattern, Pattern: `<a> "<b>"\`}, logql: `| pattern "<a> \"<b>\"\\"`},
+		{spec: "pattern:", wantErr: true},
+		{spec: "pattern:<_> foo", wantErr: true},
+		{spec: "pattern:<a><b>", wantErr: true},
+		{spec: "json:x", wantErr: true},
+		{spec: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseStage(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStage(%q) expected error, got %+v", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStage(%q) unexpected error: %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStage(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+			if got.LogQL() != tt.logql {
+				t.Errorf("LogQL() = %q, want %q", got.LogQL(), tt.logql)
+			}
+		})
+	}
+}
+
+func TestStageExtract(t *testing.T) {
+	tests := []struct {
+		name  string
+		stage Stage
+		line  string
+		want  map[string]string
+	}{
+		{
+			name:  "json flat",
+			stage: Stage{Kind: KindJSON},
+			line:  `{"status":503,"method":"GET","ok":false}`,
+			want:  map[string]string{"status": "503", "method": "GET", "ok": "false"},
+		},
+		{
+			name:  "json nested keys joined, arrays skipped",
+			stage: Stage{Kind: KindJSON},
+			line:  `{"http":{"req.path":"/api","code":200},"tags":["a"],"err":null}`,
+			want:  map[string]string{"http_req_path": "/api", "http_code": "200", "err": ""},
+		},
+		{
+			name:  "json invalid",
+			stage: Stage{Kind: KindJSON},
+			line:  `GetCartAsync called with userId={userId}`,
+			want:  nil,
+		},
+		{
+			name:  "logfmt",
+			stage: Stage{Kind: KindLogfmt},
+			line:  `level=info msg="request done" status=200 took=5ms cached`,
+			want:  map[string]string{"level": "info", "msg": "request done", "status": "200", "took": "5ms", "cached": ""},
+		},
+		{
+			name:  "logfmt escaped quote",
+			stage: Stage{Kind: KindLogfmt},
+			line:  `err="bad \"thing\"" k.v=1`,
+			want:  map[string]string{"err": `bad "thing"`, "k_v": "1"},
+		},
+		{
+			name:  "pattern",
+			stage: Stage{Kind: KindPattern, Pattern: "<ip> - <_> [<_>] \"<method> <path> <_>\" <status> <size>"},
+			line:  `10.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			want:  map[string]string{"ip": "10.0.0.1", "method": "GET", "path": "/apache_pb.gif", "status": "200", "size": "2326"},
+		},
+		{
+			name:  "pattern no match",
+			stage: Stage{Kind: KindPattern, Pattern: "<a> -> <b>"},
+			line:  "nothing to see",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.stage.Extract(tt.line)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageApplyCollision(t *testing.T) {
+	tags := map[string]any{"message": `level=debug user=bob`, "level": "INFO"}
+	Stage{Kind: KindLogfmt}.Apply(tags)
+	if tags["level"] != "INFO" {
+		t.Errorf("existing level overwritten: %v", tags["level"])
+	}
+	if tags["level_extracted"] != "debug" {
+		t.Errorf("level_extracted = %v, want debug", tags["level_extracted"])
+	}
+	if tags["user"] != "bob" {
+		t.Errorf("user = %v, want bob", tags["user"])
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		logql   string
+		wantErr bool
+	}{
+		{expr: "status>=500", logql: "| status >= 500"},
+		{expr: "status == 404", logql: "| status == 404"},
+		{expr: "status!=200", logql: "| status != 200"},
+		{expr: `method="GET"`, logql: `| method="GET"`},
+		{expr: `code="500"`, logql: `| code="500"`},
+		{expr: `path=~"/api/.*"`, logql: `| path=~"/api/.*"`},
+		{expr: "user!~bob|alice", logql: `| user!~"bob|alice"`},
+		{expr: `msg='say "hi"'`, logql: `| msg="say \"hi\""`},
+		{expr: `path=C:\tmp`, logql: `| path="C:\\tmp"`},
+		{expr: `ip=~"10\..*"`, logql: "| ip=~`10\\..*`"},
+		{expr: "latency<abc", wantErr: true},
+		{expr: "=5", wantErr: true},
+		{expr: "status", wantErr: true},
+		{expr: "path=~(", wantErr: true},
+		{expr: "http.status>=500", logql: "| http_status >= 500"},
+		{expr: "foo-bar=1", wantErr: true},
+		{expr: "1x=1", wantErr: true},
+		{expr: `"status"=1`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseFilter(%q) expected error, got %+v", tt.expr, f)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) unexpected error: %v", tt.expr, err)
+			}
+			if f.LogQL() != tt.logql {
+				t.Errorf("LogQL() = %q, want %q", f.LogQL(), tt.logql)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	fields := map[string]any{"status": "503", "method": "GET", "path": "/api/cart", "latency": 12.5}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"status>=500", true},
+		{"status<500", false},
+		{"status=503", true},
+		{"latency>10", true},
+		{`method="GET"`, true},
+		{`method!="GET"`, false},
+		{`path=~"/api/.*"`, true},
+		{`path=~"api"`, false}, // regex is anchored
+		{`path!~"/health"`, true},
+		{"missing>0", false},
+		{"missing!=1", true},
+		{`missing=""`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q): %v", tt.expr, err)
+			}
+			if got := f.Match(fields); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logparse implements the LogQL parser stages (json, logfmt, pattern)
+// and label filters the CLI can push to the server, along with client-side
+// equivalents used when the server does not support them.
+package logparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/logql"
+)
+
+// Stage kinds
+const (
+	KindJSON    = "json"
+	KindLogfmt  = "logfmt"
+	KindPattern = "pattern"
+)
+
+// Stage is a single LogQL parser stage such as `| json` or `| pattern "<ip> <_>"`.
+type Stage struct {
+	Kind    string
+	Pattern string
+}
+
+// ParseStage parses a --parse value: json, logfmt or pattern:"<expr>".
+func ParseStage(spec string) (Stage, error) {
+	spec = strings.TrimSpace(spec)
+	kind, rest, hasArg := strings.Cut(spec, ":")
+	switch strings.ToLower(kind) {
+	case KindJSON, KindLogfmt:
+		if hasArg {
+			return Stage{}, fmt.Errorf("parser %q takes no arguments", kind)
+		}
+		return Stage{Kind: strings.ToLower(kind)}, nil
+	case KindPattern:
+		pattern := unquote(strings.TrimSpace(rest))
+		if pattern == "" {
+			return Stage{}, fmt.Errorf(`pattern parser requires an expression, e.g. pattern:"<ip> - <_> <status>"`)
+		}
+		if len(patternNames(pattern)) == 0 {
+			return Stage{}, fmt.Errorf("pattern %q has no named captures", pattern)
+		}
+		nodes := compilePattern(pattern)
+		for i := 1; i < len(nodes); i++ {
+			if nodes[i].literal == "" && nodes[i-1].literal == "" {
+				return Stage{}, fmt.Errorf("pattern %q has consecutive captures; separate them with a literal", pattern)
+			}
+		}
+		return Stage{Kind: KindPattern, Pattern: pattern}, nil
+	default:
+		return Stage{}, fmt.Errorf("invalid parser %q: must be one of json, logfmt, pattern:\"<expr>\"", spec)
+	}
+}
+
+// LogQL renders the stage as a LogQL pipeline expression.
+func (s Stage) LogQL() string {
+	if s.Kind == KindPattern {
+		return "| pattern " + logql.Quote(s.Pattern)
+	}
+	return "| " + s.Kind
+}
+
+// Extract parses line and returns the extracted fields. Lines the stage cannot
+// parse yield no fields, matching LogQL which keeps the line but adds an error label.
+func (s Stage) Extract(line string) map[string]string {
+	switch s.Kind {
+	case KindJSON:
+		return extractJSON(line)
+	case KindLogfmt:
+		return extractLogfmt(line)
+	case KindPattern:
+		return extractPattern(s.Pattern, line)
+	}
+	return nil
+}
+
+// Apply runs the stage against the message in tags and merges the extracted
+// fields into tags. As in LogQL, a field that collides with an existing one is
+// stored with an "_extracted" suffix.
+func (s Stage) Apply(tags map[string]any) {
+	msg, _ := tags["message"].(string)
+	Merge(tags, s.Extract(msg))
+}
+
+// Merge adds extracted fields to tags, suffixing colliding keys with "_extracted".
+func Merge(tags map[string]any, fields map[string]string) {
+	for k, v := range fields {
+		if _, exists := tags[k]; exists {
+			k += "_extracted"
+		}
+		tags[k] = v
+	}
+}
+
+func extractJSON(line string) map[string]string {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil
+	}
+	out := make(map[string]string)
+	flattenJSON("", obj, out)
+	return out
+}
+
+// flattenJSON joins nested object keys with "_" and skips arrays, as the LogQL
+// json parser does.
+func flattenJSON(prefix string, obj map[string]any, out map[string]string) {
+	for k, v := range obj {
+		key := sanitizeKey(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch val := v.(type) {
+		case map[string]any:
+			flattenJSON(key, val, out)
+		case []any:
+			// arrays are not extracted
+		case nil:
+			out[key] = ""
+		case string:
+			out[key] = val
+		default:
+			out[key] = fmt.Sprintf("%v", val)
+		}
+	}
+}
+
+func extractLogfmt(line string) map[string]string {
+	out := make(map[string]string)
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			i++
+			continue
+		}
+		if i >= len(line) || line[i] == ' ' {
+			out[sanitizeKey(key)] = ""
+			continue
+		}
+		i++ // skip '='
+		var val string
+		if i < len(line) && line[i] == '"' {
+			var b strings.Builder
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				b.WriteByte(line[i])
+				i++
+			}
+			i++ // skip closing quote
+			val = b.String()
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			val = line[start:i]
+		}
+		out[sanitizeKey(key)] = val
+	}
+	return out
+}
+
+// patternNode is either a literal or a capture (name set; "_" discards).
+type patternNode struct {
+	literal string
+	name    string
+}
+
+func compilePattern(pattern string) []patternNode {
+	var nodes []patternNode
+	for pattern != "" {
+		open := strings.IndexByte(pattern, '<')
+		if open < 0 {
+			nodes = append(nodes, patternNode{literal: pattern})
+			break
+		}
+		end := strings.IndexByte(pattern[open:], '>')
+		if end < 0 {
+			nodes = append(nodes, patternNode{literal: pattern})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, patternNode{literal: pattern[:open]})
+		}
+		nodes = append(nodes, patternNode{name: pattern[open+1 : open+end]})
+		pattern = pattern[open+end+1:]
+	}
+	return nodes
+}
+
+func patternNames(pattern string) []string {
+	var names []string
+	for _, n := range compilePattern(pattern) {
+		if n.name != "" && n.name != "_" {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+// extractPattern matches line against a LogQL pattern expression. Each capture
+// consumes text up to the next literal; a trailing capture takes the rest of the line.
+func extractPattern(pattern, line string) map[string]string {
+	nodes := compilePattern(pattern)
+	out := make(map[string]string)
+	rest := []byte(line)
+	for i, n := range nodes {
+		if n.literal != "" {
+			idx := bytes.Index(rest, []byte(n.literal))
+			if idx < 0 {
+				return nil
+			}
+			rest = rest[idx+len(n.literal):]
+			continue
+		}
+		var value []byte
+		if i+1 < len(nodes) && nodes[i+1].literal != "" {
+			idx := bytes.Index(rest, []byte(nodes[i+1].literal))
+			if idx < 0 {
+				return nil
+			}
+			value = rest[:idx]
+			rest = rest[idx:]
+		} else {
+			value = rest
+			rest = nil
+		}
+		if n.name != "_" {
+			out[n.name] = string(value)
+		}
+	}
+	return out
+}
+
+// sanitizeKey replaces characters that are not valid in a LogQL label name.
+func sanitizeKey(k string) string {
+	b := []byte(k)
+	for i, c := range b {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// unquote strips one level of matching single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
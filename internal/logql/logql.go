@@ -0,0 +1,300 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logql evaluates the subset of LogQL the CLI builds from its filter
+// flags, a stream selector followed by line filters, for example
+//
+//	{service="api", level=~"ERROR|WARN"} |= "timeout" !~ `retry \d+`
+//
+// so that saved exports can be queried without a server.
+package logql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/fieldnames"
+)
+
+// Matcher is one label matcher of a stream selector.
+type Matcher struct {
+	Label string
+	Op    string // =, !=, =~ or !~
+	Value string
+
+	re *regexp.Regexp
+}
+
+// LineFilter is a filter on the log line (the message tag).
+type LineFilter struct {
+	Op    string // |=, !=, |~ or !~
+	Value string
+
+	re *regexp.Regexp
+}
+
+// Query is a parsed stream selector with its line filters.
+type Query struct {
+	Matchers    []Matcher
+	LineFilters []LineFilter
+}
+
+// Parse parses q. Anything beyond a selector and line filters, such as parser
+// stages or metric queries, is rejected.
+func Parse(q string) (*Query, error) {
+	p := &parser{src: q}
+	query, err := p.query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse LogQL %q: %w", q, err)
+	}
+	return query, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// consume advances past the first of tokens found at the current position.
+func (p *parser) consume(tokens ...string) (string, bool) {
+	p.skipSpace()
+	for _, t := range tokens {
+		if strings.HasPrefix(p.src[p.pos:], t) {
+			p.pos += len(t)
+			return t, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+	if _, ok := p.consume("{"); !ok {
+		return nil, p.errorf("expected '{'")
+	}
+	if _, ok := p.consume("}"); !ok {
+		for {
+			m, err := p.matcher()
+			if err != nil {
+				return nil, err
+			}
+			q.Matchers = append(q.Matchers, m)
+			if _, ok := p.consume("}"); ok {
+				break
+			}
+			if _, ok := p.consume(","); !ok {
+				return nil, p.errorf("expected ',' or '}'")
+			}
+		}
+	}
+
+	for {
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			return q, nil
+		}
+		op, ok := p.consume("|=", "!=", "|~", "!~")
+		if !ok {
+			return nil, p.errorf("unsupported expression %q: only stream selectors and line filters can be evaluated locally", p.src[p.pos:])
+		}
+		value, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		f := LineFilter{Op: op, Value: value}
+		if op == "|~" || op == "!~" {
+			if f.re, err = regexp.Compile(value); err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", value, err)
+			}
+		}
+		q.LineFilters = append(q.LineFilters, f)
+	}
+}
+
+func (p *parser) matcher() (Matcher, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && isLabelChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return Matcher{}, p.errorf("expected label name")
+	}
+	m := Matcher{Label: p.src[start:p.pos]}
+	op, ok := p.consume("=~", "!~", "!=", "=")
+	if !ok {
+		return Matcher{}, p.errorf("expected =, !=, =~ or !~ after %q", m.Label)
+	}
+	m.Op = op
+	value, err := p.str()
+	if err != nil {
+		return Matcher{}, err
+	}
+	m.Value = value
+	if op == "=~" || op == "!~" {
+		// Label regexes are anchored, as in Loki.
+		if m.re, err = regexp.Compile("^(?:" + value + ")$"); err != nil {
+			return Matcher{}, fmt.Errorf("invalid regex %q: %w", value, err)
+		}
+	}
+	return m, nil
+}
+
+func isLabelChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// str reads a double-quoted or backquoted string. Double-quoted strings that
+// are not valid Go escapes (the CLI inserts regexes such as \d+ verbatim) are
+// taken literally.
+func (p *parser) str() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return "", p.errorf("expected string")
+	}
+	quote := p.src[p.pos]
+	if quote != '"' && quote != '`' {
+		return "", p.errorf("expected string")
+	}
+	start := p.pos + 1
+	for i := start; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			raw := p.src[start:i]
+			p.pos = i + 1
+			if quote == '`' {
+				return raw, nil
+			}
+			s, err := strconv.Unquote(`"` + raw + `"`)
+			if err != nil {
+				return "", p.errorf("invalid escape in string %q; use a backtick string for regexes", raw)
+			}
+			return s, nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// Quote renders s as a LogQL string literal. LogQL strings follow Go's
+// escaping rules, so values are matched exactly as given.
+func Quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// QuoteRegex renders a regular expression as a LogQL string literal. One
+// with backslashes becomes a backtick string, so \d reads as written rather
+// than doubled, unless it also contains a backtick.
+func QuoteRegex(re string) string {
+	if strings.Contains(re, `\`) && !strings.Contains(re, "`") {
+		return "`" + re + "`"
+	}
+	return strconv.Quote(re)
+}
+
+// Match reports whether an entry with these tags satisfies the query. Line
+// filters apply to the message tag; missing labels match as empty strings.
+func (q *Query) Match(tags map[string]any) bool {
+	for _, m := range q.Matchers {
+		if !m.match(LabelValue(tags, m.Label)) {
+			return false
+		}
+	}
+	line, _ := tags["message"].(string)
+	for _, f := range q.LineFilters {
+		if !f.match(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns the values label can take in a matching entry when the
+// selector pins them down, as label="v" or label=~"v1|v2" do.
+func (q *Query) Values(label string) ([]string, bool) {
+	for _, m := range q.Matchers {
+		if m.Label != label {
+			continue
+		}
+		switch m.Op {
+		case "=":
+			return []string{m.Value}, true
+		case "=~":
+			values := strings.Split(m.Value, "|")
+			if !slices.ContainsFunc(values, func(v string) bool { return v == "" || regexp.QuoteMeta(v) != v }) {
+				return values, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (m Matcher) match(v string) bool {
+	switch m.Op {
+	case "=":
+		return v == m.Value
+	case "!=":
+		return v != m.Value
+	case "=~":
+		return m.re.MatchString(v)
+	default:
+		return !m.re.MatchString(v)
+	}
+}
+
+func (f LineFilter) match(line string) bool {
+	switch f.Op {
+	case "|=":
+		return strings.Contains(line, f.Value)
+	case "!=":
+		return !strings.Contains(line, f.Value)
+	case "|~":
+		return f.re.MatchString(line)
+	default:
+		return !f.re.MatchString(line)
+	}
+}
+
+// LabelValue returns the value of label in tags as a string. Labels use
+// underscores where attribute names use dots, so resource_service_name also
+// finds a resource.service.name tag.
+func LabelValue(tags map[string]any, label string) string {
+	v, _, ok := fieldnames.Lookup(tags, label)
+	if !ok || v == nil {
+		return ""
+	}
+	switch tv := v.(type) {
+	case string:
+		return tv
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
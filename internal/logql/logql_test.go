@@ -0,0 +1,142 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`{service=~"api|cart", level="ERROR", resource_k8s_namespace_name!="dev"} |= "timeout" != "retry" |~ "took \\d+ms" !~ ` + "`(?i)health`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Matchers) != 3 || len(q.LineFilters) != 4 {
+		t.Fatalf("parsed %d matchers and %d line filters", len(q.Matchers), len(q.LineFilters))
+	}
+	if m := q.Matchers[2]; m.Label != "resource_k8s_namespace_name" || m.Op != "!=" || m.Value != "dev" {
+		t.Errorf("matcher = %+v", m)
+	}
+	if f := q.LineFilters[2]; f.Value != `took \d+ms` {
+		t.Errorf("verbatim regex = %q", f.Value)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`service="api"`, "expected '{'"},
+		{`{service}`, "expected =, !="},
+		{`{service="api"`, "expected ',' or '}'"},
+		{`{service="api} |= "x"`, "expected ',' or '}'"},
+		{`{service="api"} | json`, "only stream selectors and line filters"},
+		{`{service=~"("}`, "invalid regex"},
+		{`{service="api"} |= "x`, "unterminated string"},
+		{`{service="api"} |~ "\d+"`, "invalid escape"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%s) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for _, s := range []string{`plain`, `a "quoted" value`, `C:\tmp`, `\d+ms`, "back`tick \\d"} {
+		for _, quoted := range []string{Quote(s), QuoteRegex(s)} {
+			q, err := Parse(`{service="api"} |= ` + quoted)
+			if err != nil {
+				t.Errorf("Parse(%s): %v", quoted, err)
+				continue
+			}
+			if got := q.LineFilters[0].Value; got != s {
+				t.Errorf("%s parsed as %q, want %q", quoted, got, s)
+			}
+		}
+	}
+	if got := QuoteRegex(`\d+`); got != "`\\d+`" {
+		t.Errorf("QuoteRegex = %s, want a backtick string", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tags := map[string]any{
+		"service":               "checkout",
+		"level":                 "ERROR",
+		"resource.cluster.name": "prod-1",
+		"status":                float64(503),
+		"message":               "upstream timeout after 3 retries",
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`{}`, true},
+		{`{service=~".+"}`, true},
+		{`{service="checkout"}`, true},
+		{`{service="check"}`, false},
+		{`{service=~"check"}`, false},
+		{`{service=~"check.*|cart"}`, true},
+		{`{service!~"checkout"}`, false},
+		{`{resource_cluster_name="prod-1"}`, true},
+		{`{status="503"}`, true},
+		{`{missing=""}`, true},
+		{`{missing!=""}`, false},
+		{`{level="ERROR"} |= "timeout"`, true},
+		{`{level="ERROR"} != "timeout"`, false},
+		{"{level=\"ERROR\"} |~ `after \\d+ retries`", true},
+		{`{level="ERROR"} !~ "(?i)UPSTREAM"`, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", tt.query, err)
+		}
+		if got := q.Match(tags); got != tt.want {
+			t.Errorf("%s matched = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		query string
+		label string
+		want  []string
+		ok    bool
+	}{
+		{`{service="api"}`, "service", []string{"api"}, true},
+		{`{service=~"api|cart"}`, "service", []string{"api", "cart"}, true},
+		{`{service=~".+"}`, "service", nil, false},
+		{`{service=~"api|"}`, "service", nil, false},
+		{`{service!="api"}`, "service", nil, false},
+		{`{service="api", level="ERROR"}`, "level", []string{"ERROR"}, true},
+		{`{service="api"}`, "level", nil, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := q.Values(tt.label)
+		if ok != tt.ok || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s Values(%q) = %q, %v; want %q, %v", tt.query, tt.label, got, ok, tt.want, tt.ok)
+		}
+	}
+}
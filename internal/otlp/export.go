@@ -0,0 +1,151 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPExporter sends logs to an OTLP/HTTP receiver such as a collector,
+// retrying the failures the OTLP specification marks as transient.
+type HTTPExporter struct {
+	// Endpoint is the receiver's base URL, e.g. http://localhost:4318, or the
+	// full URL of its logs path.
+	Endpoint string
+	Headers  map[string]string
+	Client   *http.Client
+	// Retries is how many times a failed export is retried.
+	Retries int
+	// Backoff is the delay before the first retry; it doubles on each
+	// attempt, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// permanentError marks a response that retrying will not fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// LogsURL returns the URL logs are POSTed to.
+func (e *HTTPExporter) LogsURL() string {
+	url := strings.TrimSuffix(e.Endpoint, "/")
+	if strings.HasSuffix(url, "/v1/logs") {
+		return url
+	}
+	return url + "/v1/logs"
+}
+
+// ExportLogs sends logs as one gzipped protobuf request.
+func (e *HTTPExporter) ExportLogs(ctx context.Context, logs []ResourceLogs) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(MarshalLogs(logs)); err != nil {
+		return fmt.Errorf("failed to compress logs: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress logs: %w", err)
+	}
+
+	backoff := e.Backoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	maxBackoff := e.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := e.post(ctx, buf.Bytes())
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= e.Retries || ctx.Err() != nil {
+			return err
+		}
+		wait := max(backoff, retryAfter)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// post makes one attempt. On a retryable failure it also returns how long
+// the server asked the client to wait, if it did.
+func (e *HTTPExporter) post(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.LogsURL(), bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("User-Agent", "lakerunner-cli/1.0")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send logs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("receiver returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(seconds) * time.Second, err
+	}
+	return 0, &permanentError{err}
+}
+
+// ObjectKey names an object the way the OpenTelemetry collector's S3
+// exporter lays them out:
+// PREFIX/ORG/COLLECTOR/year=YYYY/month=MM/day=DD/hour=HH/minute=MM/SIGNAL_NANOS.EXT.
+// Empty segments are left out.
+func ObjectKey(prefix, org, collector, signal string, t time.Time, ext string) string {
+	t = t.UTC()
+	dir := fmt.Sprintf("year=%04d/month=%02d/day=%02d/hour=%02d/minute=%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute())
+	name := fmt.Sprintf("%s_%d.%s", signal, t.UnixNano(), ext)
+	var parts []string
+	for _, part := range []string{strings.Trim(prefix, "/"), org, collector, dir, name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,381 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp encodes OpenTelemetry log and metric payloads in the OTLP
+// protobuf and JSON formats, as an OpenTelemetry collector's file and S3
+// exporters write them. Only the fields the CLI produces are modelled.
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Severity numbers of the OpenTelemetry log data model.
+var severityNumbers = map[string]int32{
+	"TRACE": 1,
+	"DEBUG": 5,
+	"INFO":  9,
+	"WARN":  13,
+	"ERROR": 17,
+	"FATAL": 21,
+}
+
+// SeverityNumber returns the severity number for a level name, or 0
+// (unspecified) for unknown names.
+func SeverityNumber(level string) int32 {
+	return severityNumbers[level]
+}
+
+// KeyValue is an attribute. Value is a string, int64, float64 or bool.
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// LogRecord is one log line.
+type LogRecord struct {
+	Time         time.Time
+	SeverityText string
+	Body         string
+	Attributes   []KeyValue
+	TraceID      [16]byte
+	SpanID       [8]byte
+}
+
+// ResourceLogs are the records of one resource, such as a service instance.
+type ResourceLogs struct {
+	Resource []KeyValue
+	Scope    string
+	Records  []LogRecord
+}
+
+// MetricKind selects the data type of a metric.
+type MetricKind int
+
+const (
+	Gauge MetricKind = iota
+	// Sum is a monotonic cumulative counter.
+	Sum
+)
+
+// DataPoint is one value of a metric.
+type DataPoint struct {
+	Attributes []KeyValue
+	Start      time.Time
+	Time       time.Time
+	Value      float64
+}
+
+// Metric is a named series of data points.
+type Metric struct {
+	Name   string
+	Unit   string
+	Kind   MetricKind
+	Points []DataPoint
+}
+
+// ResourceMetrics are the metrics of one resource.
+type ResourceMetrics struct {
+	Resource []KeyValue
+	Scope    string
+	Metrics  []Metric
+}
+
+// Field numbers from opentelemetry-proto.
+const (
+	fieldResourceLogs    = 1 // LogsData
+	fieldResourceMetrics = 1 // MetricsData
+	fieldResource        = 1 // ResourceLogs, ResourceMetrics
+	fieldScopeEntries    = 2 // ResourceLogs.scope_logs, ResourceMetrics.scope_metrics
+	fieldScope           = 1 // ScopeLogs, ScopeMetrics
+	fieldScopeItems      = 2 // ScopeLogs.log_records, ScopeMetrics.metrics
+	fieldAttributes      = 1 // Resource
+	fieldScopeName       = 1 // InstrumentationScope
+
+	fieldKey   = 1 // KeyValue
+	fieldValue = 2
+
+	fieldStringValue = 1 // AnyValue
+	fieldBoolValue   = 2
+	fieldIntValue    = 3
+	fieldDoubleValue = 4
+
+	fieldLogTime           = 1 // LogRecord
+	fieldLogSeverityNumber = 2
+	fieldLogSeverityText   = 3
+	fieldLogBody           = 5
+	fieldLogAttributes     = 6
+	fieldLogTraceID        = 9
+	fieldLogSpanID         = 10
+	fieldLogObservedTime   = 11
+
+	fieldMetricName  = 1 // Metric
+	fieldMetricUnit  = 3
+	fieldMetricGauge = 5
+	fieldMetricSum   = 7
+
+	fieldDataPoints       = 1 // Gauge, Sum
+	fieldSumTemporality   = 2
+	fieldSumMonotonic     = 3
+	temporalityCumulative = 2
+	fieldPointStartTime   = 2 // NumberDataPoint
+	fieldPointTime        = 3
+	fieldPointDoubleValue = 4
+	fieldPointAttributes  = 7
+)
+
+// message appends a length-delimited field built by fn.
+func message(b []byte, num protowire.Number, fn func([]byte) []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, fn(nil))
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+func appendKeyValues(b []byte, num protowire.Number, kvs []KeyValue) []byte {
+	for _, kv := range kvs {
+		b = message(b, num, func(b []byte) []byte {
+			b = appendString(b, fieldKey, kv.Key)
+			return message(b, fieldValue, func(b []byte) []byte { return appendAnyValue(b, kv.Value) })
+		})
+	}
+	return b
+}
+
+func appendAnyValue(b []byte, v any) []byte {
+	switch tv := v.(type) {
+	case string:
+		b = protowire.AppendTag(b, fieldStringValue, protowire.BytesType)
+		return protowire.AppendString(b, tv)
+	case bool:
+		b = protowire.AppendTag(b, fieldBoolValue, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(tv))
+	case int64:
+		b = protowire.AppendTag(b, fieldIntValue, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(tv))
+	case int:
+		return appendAnyValue(b, int64(tv))
+	case float64:
+		b = protowire.AppendTag(b, fieldDoubleValue, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(tv))
+	}
+	return appendAnyValue(b, fmt.Sprint(v))
+}
+
+func appendScope(b []byte, name string) []byte {
+	return message(b, fieldScope, func(b []byte) []byte { return appendString(b, fieldScopeName, name) })
+}
+
+// MarshalLogs encodes an ExportLogsServiceRequest (equivalently LogsData).
+func MarshalLogs(logs []ResourceLogs) []byte {
+	var b []byte
+	for _, rl := range logs {
+		b = message(b, fieldResourceLogs, func(b []byte) []byte {
+			b = message(b, fieldResource, func(b []byte) []byte { return appendKeyValues(b, fieldAttributes, rl.Resource) })
+			return message(b, fieldScopeEntries, func(b []byte) []byte {
+				b = appendScope(b, rl.Scope)
+				for _, rec := range rl.Records {
+					b = message(b, fieldScopeItems, func(b []byte) []byte { return appendLogRecord(b, rec) })
+				}
+				return b
+			})
+		})
+	}
+	return b
+}
+
+func appendLogRecord(b []byte, rec LogRecord) []byte {
+	b = appendFixed64(b, fieldLogTime, uint64(rec.Time.UnixNano()))
+	if n := SeverityNumber(rec.SeverityText); n != 0 {
+		b = protowire.AppendTag(b, fieldLogSeverityNumber, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(n))
+	}
+	b = appendString(b, fieldLogSeverityText, rec.SeverityText)
+	b = message(b, fieldLogBody, func(b []byte) []byte { return appendAnyValue(b, rec.Body) })
+	b = appendKeyValues(b, fieldLogAttributes, rec.Attributes)
+	if rec.TraceID != [16]byte{} {
+		b = protowire.AppendTag(b, fieldLogTraceID, protowire.BytesType)
+		b = protowire.AppendBytes(b, rec.TraceID[:])
+	}
+	if rec.SpanID != [8]byte{} {
+		b = protowire.AppendTag(b, fieldLogSpanID, protowire.BytesType)
+		b = protowire.AppendBytes(b, rec.SpanID[:])
+	}
+	return appendFixed64(b, fieldLogObservedTime, uint64(rec.Time.UnixNano()))
+}
+
+// MarshalMetrics encodes an ExportMetricsServiceRequest (equivalently
+// MetricsData).
+func MarshalMetrics(metrics []ResourceMetrics) []byte {
+	var b []byte
+	for _, rm := range metrics {
+		b = message(b, fieldResourceMetrics, func(b []byte) []byte {
+			b = message(b, fieldResource, func(b []byte) []byte { return appendKeyValues(b, fieldAttributes, rm.Resource) })
+			return message(b, fieldScopeEntries, func(b []byte) []byte {
+				b = appendScope(b, rm.Scope)
+				for _, m := range rm.Metrics {
+					b = message(b, fieldScopeItems, func(b []byte) []byte { return appendMetric(b, m) })
+				}
+				return b
+			})
+		})
+	}
+	return b
+}
+
+func appendMetric(b []byte, m Metric) []byte {
+	b = appendString(b, fieldMetricName, m.Name)
+	b = appendString(b, fieldMetricUnit, m.Unit)
+	field := protowire.Number(fieldMetricGauge)
+	if m.Kind == Sum {
+		field = fieldMetricSum
+	}
+	return message(b, field, func(b []byte) []byte {
+		for _, p := range m.Points {
+			b = message(b, fieldDataPoints, func(b []byte) []byte { return appendDataPoint(b, p) })
+		}
+		if m.Kind == Sum {
+			b = protowire.AppendTag(b, fieldSumTemporality, protowire.VarintType)
+			b = protowire.AppendVarint(b, temporalityCumulative)
+			b = protowire.AppendTag(b, fieldSumMonotonic, protowire.VarintType)
+			b = protowire.AppendVarint(b, 1)
+		}
+		return b
+	})
+}
+
+func appendDataPoint(b []byte, p DataPoint) []byte {
+	if !p.Start.IsZero() {
+		b = appendFixed64(b, fieldPointStartTime, uint64(p.Start.UnixNano()))
+	}
+	b = appendFixed64(b, fieldPointTime, uint64(p.Time.UnixNano()))
+	b = protowire.AppendTag(b, fieldPointDoubleValue, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(p.Value))
+	return appendKeyValues(b, fieldPointAttributes, p.Attributes)
+}
+
+// The OTLP/JSON mapping: lowerCamelCase names, 64-bit integers as strings,
+// trace and span IDs as hex.
+type (
+	jsonObject = map[string]any
+	jsonList   = []any
+)
+
+func jsonKeyValues(kvs []KeyValue) jsonList {
+	out := jsonList{}
+	for _, kv := range kvs {
+		out = append(out, jsonObject{"key": kv.Key, "value": jsonAnyValue(kv.Value)})
+	}
+	return out
+}
+
+func jsonAnyValue(v any) jsonObject {
+	switch tv := v.(type) {
+	case string:
+		return jsonObject{"stringValue": tv}
+	case bool:
+		return jsonObject{"boolValue": tv}
+	case int64:
+		return jsonObject{"intValue": strconv.FormatInt(tv, 10)}
+	case int:
+		return jsonObject{"intValue": strconv.Itoa(tv)}
+	case float64:
+		return jsonObject{"doubleValue": tv}
+	}
+	return jsonObject{"stringValue": fmt.Sprint(v)}
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// MarshalLogsJSON encodes logs as OTLP/JSON.
+func MarshalLogsJSON(logs []ResourceLogs) ([]byte, error) {
+	resourceLogs := jsonList{}
+	for _, rl := range logs {
+		records := jsonList{}
+		for _, rec := range rl.Records {
+			r := jsonObject{
+				"timeUnixNano":         nanos(rec.Time),
+				"observedTimeUnixNano": nanos(rec.Time),
+				"severityText":         rec.SeverityText,
+				"body":                 jsonAnyValue(rec.Body),
+				"attributes":           jsonKeyValues(rec.Attributes),
+			}
+			if n := SeverityNumber(rec.SeverityText); n != 0 {
+				r["severityNumber"] = n
+			}
+			if rec.TraceID != [16]byte{} {
+				r["traceId"] = hex.EncodeToString(rec.TraceID[:])
+			}
+			if rec.SpanID != [8]byte{} {
+				r["spanId"] = hex.EncodeToString(rec.SpanID[:])
+			}
+			records = append(records, r)
+		}
+		resourceLogs = append(resourceLogs, jsonObject{
+			"resource":  jsonObject{"attributes": jsonKeyValues(rl.Resource)},
+			"scopeLogs": jsonList{jsonObject{"scope": jsonObject{"name": rl.Scope}, "logRecords": records}},
+		})
+	}
+	return json.Marshal(jsonObject{"resourceLogs": resourceLogs})
+}
+
+// MarshalMetricsJSON encodes metrics as OTLP/JSON.
+func MarshalMetricsJSON(metrics []ResourceMetrics) ([]byte, error) {
+	resourceMetrics := jsonList{}
+	for _, rm := range metrics {
+		ms := jsonList{}
+		for _, m := range rm.Metrics {
+			points := jsonList{}
+			for _, p := range m.Points {
+				point := jsonObject{"timeUnixNano": nanos(p.Time), "asDouble": p.Value, "attributes": jsonKeyValues(p.Attributes)}
+				if !p.Start.IsZero() {
+					point["startTimeUnixNano"] = nanos(p.Start)
+				}
+				points = append(points, point)
+			}
+			metric := jsonObject{"name": m.Name, "unit": m.Unit}
+			if m.Kind == Sum {
+				metric["sum"] = jsonObject{"dataPoints": points, "aggregationTemporality": temporalityCumulative, "isMonotonic": true}
+			} else {
+				metric["gauge"] = jsonObject{"dataPoints": points}
+			}
+			ms = append(ms, metric)
+		}
+		resourceMetrics = append(resourceMetrics, jsonObject{
+			"resource":     jsonObject{"attributes": jsonKeyValues(rm.Resource)},
+			"scopeMetrics": jsonList{jsonObject{"scope": jsonObject{"name": rm.Scope}, "metrics": ms}},
+		})
+	}
+	return json.Marshal(jsonObject{"resourceMetrics": resourceMetrics})
+}
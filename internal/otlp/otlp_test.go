@@ -0,0 +1,230 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decode splits a message into its fields, keyed by field number.
+func decode(t *testing.T, b []byte) map[protowire.Number][]any {
+	t.Helper()
+	fields := make(map[protowire.Number][]any)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v any
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		if n < 0 {
+			t.Fatalf("bad field %d: %v", num, protowire.ParseError(n))
+		}
+		fields[num] = append(fields[num], v)
+		b = b[n:]
+	}
+	return fields
+}
+
+func sub(t *testing.T, fields map[protowire.Number][]any, num protowire.Number) map[protowire.Number][]any {
+	t.Helper()
+	if len(fields[num]) == 0 {
+		t.Fatalf("field %d missing", num)
+	}
+	return decode(t, fields[num][0].([]byte))
+}
+
+var ts = time.Date(2025, 10, 15, 14, 0, 0, 0, time.UTC)
+
+func TestMarshalLogs(t *testing.T) {
+	data := MarshalLogs([]ResourceLogs{{
+		Resource: []KeyValue{{"service.name", "checkout"}, {"replicas", int64(2)}},
+		Scope:    "demo",
+		Records: []LogRecord{{
+			Time:         ts,
+			SeverityText: "ERROR",
+			Body:         "payment timeout",
+			Attributes:   []KeyValue{{"retry", true}, {"ratio", 0.5}},
+			TraceID:      [16]byte{1},
+			SpanID:       [8]byte{2},
+		}},
+	}})
+
+	resourceLogs := sub(t, decode(t, data), 1)
+	resource := sub(t, resourceLogs, 1)
+	attr := decode(t, resource[1][0].([]byte))
+	if string(attr[1][0].([]byte)) != "service.name" || string(sub(t, attr, 2)[1][0].([]byte)) != "checkout" {
+		t.Errorf("first resource attribute = %v", attr)
+	}
+	if v := decode(t, decode(t, resource[1][1].([]byte))[2][0].([]byte)); v[3][0] != uint64(2) {
+		t.Errorf("int attribute = %v", v)
+	}
+
+	scopeLogs := sub(t, resourceLogs, 2)
+	if string(sub(t, scopeLogs, 1)[1][0].([]byte)) != "demo" {
+		t.Error("scope name not encoded")
+	}
+	rec := sub(t, scopeLogs, 2)
+	if rec[1][0] != uint64(ts.UnixNano()) || rec[11][0] != uint64(ts.UnixNano()) {
+		t.Errorf("timestamps = %v, %v", rec[1], rec[11])
+	}
+	if rec[2][0] != uint64(17) || string(rec[3][0].([]byte)) != "ERROR" {
+		t.Errorf("severity = %v %s", rec[2], rec[3])
+	}
+	if string(sub(t, rec, 5)[1][0].([]byte)) != "payment timeout" {
+		t.Error("body not encoded")
+	}
+	if len(rec[6]) != 2 || len(rec[9][0].([]byte)) != 16 || len(rec[10][0].([]byte)) != 8 {
+		t.Errorf("attributes or IDs missing: %v", rec)
+	}
+	if v := decode(t, decode(t, rec[6][1].([]byte))[2][0].([]byte)); math.Float64frombits(v[4][0].(uint64)) != 0.5 {
+		t.Errorf("double attribute = %v", v)
+	}
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	data := MarshalMetrics([]ResourceMetrics{{
+		Resource: []KeyValue{{"service.name", "cart"}},
+		Scope:    "demo",
+		Metrics: []Metric{
+			{Name: "app.log.records", Kind: Sum, Points: []DataPoint{{Start: ts, Time: ts.Add(time.Minute), Value: 42, Attributes: []KeyValue{{"level", "INFO"}}}}},
+			{Name: "process.cpu.utilization", Unit: "1", Points: []DataPoint{{Time: ts, Value: 0.25}}},
+		},
+	}})
+	scopeMetrics := sub(t, sub(t, decode(t, data), 1), 2)
+	metrics := scopeMetrics[2]
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics", len(metrics))
+	}
+	counter := decode(t, metrics[0].([]byte))
+	sum := sub(t, counter, 7)
+	if sum[2][0] != uint64(2) || sum[3][0] != uint64(1) {
+		t.Errorf("sum temporality/monotonic = %v %v", sum[2], sum[3])
+	}
+	point := sub(t, sum, 1)
+	if math.Float64frombits(point[4][0].(uint64)) != 42 || point[2][0] != uint64(ts.UnixNano()) || len(point[7]) != 1 {
+		t.Errorf("sum point = %v", point)
+	}
+	gauge := decode(t, metrics[1].([]byte))
+	if string(gauge[3][0].([]byte)) != "1" || math.Float64frombits(sub(t, sub(t, gauge, 5), 1)[4][0].(uint64)) != 0.25 {
+		t.Errorf("gauge = %v", gauge)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	data, err := MarshalLogsJSON([]ResourceLogs{{
+		Resource: []KeyValue{{"service.name", "checkout"}},
+		Records:  []LogRecord{{Time: ts, SeverityText: "WARN", Body: "slow", TraceID: [16]byte{0xab}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []struct {
+					TimeUnixNano   string
+					SeverityNumber int
+					TraceID        string `json:"traceId"`
+					Body           struct{ StringValue string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	rec := doc.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if rec.TimeUnixNano != "1760536800000000000" || rec.SeverityNumber != 13 || !strings.HasPrefix(rec.TraceID, "ab00") || rec.Body.StringValue != "slow" {
+		t.Errorf("record = %+v", rec)
+	}
+
+	data, err = MarshalMetricsJSON([]ResourceMetrics{{Metrics: []Metric{{Name: "c", Kind: Sum, Points: []DataPoint{{Time: ts, Value: 1}}}}}})
+	if err != nil || !strings.Contains(string(data), `"aggregationTemporality":2`) {
+		t.Errorf("metrics JSON = %s (%v)", data, err)
+	}
+}
+
+func TestHTTPExporter(t *testing.T) {
+	var calls int
+	var got []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Authorization") != "Bearer x" || r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("request %s with headers %v", r.URL.Path, r.Header)
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ = io.ReadAll(gz)
+	}))
+	defer srv.Close()
+
+	logs := []ResourceLogs{{Scope: "test", Records: []LogRecord{{Time: time.Unix(1, 0), Body: "hello"}}}}
+	e := &HTTPExporter{Endpoint: srv.URL + "/", Headers: map[string]string{"Authorization": "Bearer x"}, Retries: 2, Backoff: time.Millisecond}
+	if err := e.ExportLogs(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || !bytes.Equal(got, MarshalLogs(logs)) {
+		t.Errorf("calls = %d, body matches = %v", calls, bytes.Equal(got, MarshalLogs(logs)))
+	}
+
+	calls = 0
+	e.Retries = 1
+	if err := e.ExportLogs(context.Background(), logs); err == nil || calls != 2 {
+		t.Errorf("expected failure after 2 attempts, got %v after %d", err, calls)
+	}
+}
+
+func TestHTTPExporterPermanentError(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	e := &HTTPExporter{Endpoint: srv.URL + "/v1/logs", Retries: 5, Backoff: time.Millisecond}
+	err := e.ExportLogs(context.Background(), nil)
+	if err == nil || calls != 1 || !strings.Contains(err.Error(), "bad payload") {
+		t.Errorf("err = %v after %d calls", err, calls)
+	}
+}
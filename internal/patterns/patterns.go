@@ -0,0 +1,163 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package patterns clusters log messages into templates, Drain-style: variable
+// parts such as numbers, UUIDs, IPs and hex IDs are masked, then messages with
+// the same shape are merged and their differing tokens replaced by a wildcard.
+package patterns
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholders substituted for masked values.
+const (
+	Wildcard = "<*>"
+	MaskUUID = "<UUID>"
+	MaskIP   = "<IP>"
+	MaskHex  = "<HEX>"
+	MaskNum  = "<NUM>"
+)
+
+// DefaultThreshold is the fraction of matching tokens needed to join a pattern.
+const DefaultThreshold = 0.5
+
+var (
+	uuidRe = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	ipRe   = regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`)
+	hexRe  = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	numRe  = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+)
+
+// Mask replaces UUIDs, IPs, hex IDs and numbers in msg with placeholders.
+// Digits inside identifiers (k8s, http2, v1) are left alone.
+func Mask(msg string) string {
+	msg = uuidRe.ReplaceAllString(msg, MaskUUID)
+	msg = ipRe.ReplaceAllString(msg, MaskIP)
+	msg = hexRe.ReplaceAllStringFunc(msg, func(m string) string {
+		switch {
+		case strings.Trim(m, "0123456789") == "":
+			return MaskNum
+		case strings.HasPrefix(m, "0x") || strings.ContainsAny(m, "0123456789"):
+			return MaskHex
+		}
+		return m
+	})
+	var b strings.Builder
+	last := 0
+	for _, loc := range numRe.FindAllStringIndex(msg, -1) {
+		if loc[0] > 0 && isIdentChar(msg[loc[0]-1]) {
+			continue
+		}
+		b.WriteString(msg[last:loc[0]])
+		b.WriteString(MaskNum)
+		last = loc[1]
+	}
+	b.WriteString(msg[last:])
+	return b.String()
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Pattern is a message template and the number of messages it matched.
+type Pattern struct {
+	ID     int
+	Tokens []string
+	Count  int64
+}
+
+// Template returns the pattern as a single string.
+func (p *Pattern) Template() string {
+	return strings.Join(p.Tokens, " ")
+}
+
+// Miner incrementally clusters messages into patterns.
+type Miner struct {
+	threshold float64
+	groups    map[string][]*Pattern
+	patterns  []*Pattern
+}
+
+// NewMiner creates a Miner; threshold is the fraction of tokens that must match
+// for a message to join an existing pattern (DefaultThreshold if <= 0).
+func NewMiner(threshold float64) *Miner {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Miner{threshold: threshold, groups: make(map[string][]*Pattern)}
+}
+
+// groupKey routes messages by token count and first token, as in Drain's
+// fixed-depth parse tree. Masked first tokens share one route.
+func groupKey(tokens []string) string {
+	first := ""
+	if len(tokens) > 0 {
+		first = tokens[0]
+		if strings.Contains(first, "<") {
+			first = Wildcard
+		}
+	}
+	return strconv.Itoa(len(tokens)) + "\x00" + first
+}
+
+// similarity is the fraction of positions where the template matches tokens.
+func similarity(template, tokens []string) float64 {
+	if len(tokens) == 0 {
+		return 1
+	}
+	same := 0
+	for i, t := range template {
+		if t == tokens[i] || t == Wildcard {
+			same++
+		}
+	}
+	return float64(same) / float64(len(tokens))
+}
+
+// Add clusters msg and returns the pattern it was assigned to. The returned
+// pointer stays valid as the template is generalised by later messages.
+func (m *Miner) Add(msg string) *Pattern {
+	tokens := strings.Fields(Mask(msg))
+	key := groupKey(tokens)
+
+	var best *Pattern
+	bestSim := -1.0
+	for _, p := range m.groups[key] {
+		if sim := similarity(p.Tokens, tokens); sim > bestSim {
+			best, bestSim = p, sim
+		}
+	}
+	if best == nil || bestSim < m.threshold {
+		best = &Pattern{ID: len(m.patterns) + 1, Tokens: tokens}
+		m.groups[key] = append(m.groups[key], best)
+		m.patterns = append(m.patterns, best)
+	} else {
+		for i, t := range best.Tokens {
+			if t != tokens[i] {
+				best.Tokens[i] = Wildcard
+			}
+		}
+	}
+	best.Count++
+	return best
+}
+
+// Patterns returns every pattern in creation order.
+func (m *Miner) Patterns() []*Pattern {
+	return m.patterns
+}
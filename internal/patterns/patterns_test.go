@@ -0,0 +1,94 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patterns
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"GetCartAsync called with userId=123", "GetCartAsync called with userId=<NUM>"},
+		{"request 550e8400-e29b-41d4-a716-446655440000 done", "request <UUID> done"},
+		{"connect to 10.0.0.12:5432 refused", "connect to <IP> refused"},
+		{"trace 4bf92f3577b34da6 sampled", "trace <HEX> sampled"},
+		{"pointer 0x7ffd1c", "pointer <HEX>"},
+		{"took 12.5ms", "took <NUM>ms"},
+		{"k8s http2 v1 unchanged", "k8s http2 v1 unchanged"},
+		{"deadbeefcafe is a word", "deadbeefcafe is a word"},
+		{"order 12345678 shipped", "order <NUM> shipped"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.input); got != tt.expected {
+			t.Errorf("Mask(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestMinerClusters(t *testing.T) {
+	m := NewMiner(0)
+	a := m.Add("user alice logged in from 10.0.0.1")
+	b := m.Add("user bob logged in from 10.0.0.2")
+	c := m.Add("cache miss for key 42")
+	d := m.Add("cache miss for key 7")
+
+	if a != b {
+		t.Error("expected login messages to share a pattern")
+	}
+	if c != d {
+		t.Error("expected cache messages to share a pattern")
+	}
+	if a == c {
+		t.Error("expected distinct patterns for different messages")
+	}
+	if got, want := a.Template(), "user <*> logged in from <IP>"; got != want {
+		t.Errorf("Template() = %q, want %q", got, want)
+	}
+	if got, want := c.Template(), "cache miss for key <NUM>"; got != want {
+		t.Errorf("Template() = %q, want %q", got, want)
+	}
+	if a.Count != 2 || c.Count != 2 {
+		t.Errorf("counts = %d, %d; want 2, 2", a.Count, c.Count)
+	}
+	if len(m.Patterns()) != 2 {
+		t.Errorf("Patterns() has %d entries, want 2", len(m.Patterns()))
+	}
+}
+
+func TestMinerThreshold(t *testing.T) {
+	m := NewMiner(0.9)
+	a := m.Add("alpha beta gamma delta")
+	b := m.Add("alpha beta gamma epsilon")
+	if a == b {
+		t.Error("expected a strict threshold to keep messages apart")
+	}
+
+	m = NewMiner(0.5)
+	a = m.Add("alpha beta gamma delta")
+	b = m.Add("alpha beta gamma epsilon")
+	if a != b {
+		t.Error("expected a loose threshold to merge messages")
+	}
+}
+
+func TestMinerTokenCount(t *testing.T) {
+	m := NewMiner(0)
+	a := m.Add("one two three")
+	b := m.Add("one two three four")
+	if a == b {
+		t.Error("messages with different token counts must not merge")
+	}
+}
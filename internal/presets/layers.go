@@ -0,0 +1,288 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package presets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigName is the team-shared config file looked up from the CWD upwards.
+const ProjectConfigName = ".lakerunner.yaml"
+
+// ConfigEnvVar names a config file layered above the user and project files.
+const ConfigEnvVar = "LAKERUNNER_CONFIG"
+
+// Layer sources, in increasing precedence.
+const (
+	SourceUser    = "user"
+	SourceProject = "project"
+	SourceEnv     = ConfigEnvVar
+	SourceFlag    = "--config"
+	SourceInclude = "include"
+)
+
+// Layer is one config file taking part in the merge.
+type Layer struct {
+	Path   string
+	Source string
+	// Required layers were named explicitly and must exist.
+	Required bool
+}
+
+// configFile is the on-disk shape of a single config file.
+type configFile struct {
+	Include   []string            `yaml:"include"`
+	Presets   map[string][]string `yaml:"presets"`
+	Aliases   map[string]string   `yaml:"aliases"`
+	Redaction *redactionFile      `yaml:"redaction"`
+}
+
+// redactionFile is the redaction block of one file. Fields left out are
+// nil, so they inherit the value from lower layers.
+type redactionFile struct {
+	Enabled   *bool         `yaml:"enabled"`
+	Endpoints []string      `yaml:"endpoints"`
+	Detectors []string      `yaml:"detectors"`
+	Fields    []string      `yaml:"fields"`
+	Rules     []redact.Rule `yaml:"rules"`
+}
+
+// configFlag holds the --config value once the root command has parsed it.
+var configFlag string
+
+// SetConfigFile sets the file named by --config.
+func SetConfigFile(path string) {
+	configFlag = path
+}
+
+// configFlagFromArgs finds --config in args. Alias flags are registered from
+// the config while commands are built, before cobra has parsed anything, so
+// the flag has to be picked out of the raw arguments.
+func configFlagFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--config="); ok {
+			return v
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// findProjectConfig walks up from dir looking for ProjectConfigName.
+func findProjectConfig(dir string) string {
+	for {
+		path := filepath.Join(dir, ProjectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Layers returns the config files to merge, lowest precedence first:
+// ~/.lakerunner/config.yaml, the nearest .lakerunner.yaml, $LAKERUNNER_CONFIG
+// and --config. Included files are expanded by Load.
+func Layers() []Layer {
+	var layers []Layer
+	if path := configPath(); path != "" {
+		layers = append(layers, Layer{Path: path, Source: SourceUser})
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if path := findProjectConfig(wd); path != "" {
+			layers = append(layers, Layer{Path: path, Source: SourceProject})
+		}
+	}
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		layers = append(layers, Layer{Path: expandHome(path), Source: SourceEnv, Required: true})
+	}
+	flag := configFlag
+	if flag == "" {
+		flag = configFlagFromArgs(os.Args[1:])
+	}
+	if flag != "" {
+		layers = append(layers, Layer{Path: expandHome(flag), Source: SourceFlag, Required: true})
+	}
+	return layers
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// loadLayers merges layers into a Config, recording where each entry came from.
+func loadLayers(layers []Layer) (*Config, error) {
+	cfg := &Config{
+		Presets: make(map[string][]string),
+		Aliases: make(map[string]string),
+		Origins: make(map[string]string),
+	}
+	for _, l := range layers {
+		if err := cfg.mergeFile(l, l.Source == SourceProject, nil); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(l.Path); err == nil {
+			cfg.Sources = append(cfg.Sources, l)
+		}
+	}
+	return cfg, nil
+}
+
+// mergeFile merges one file over cfg. Its includes are merged first so the
+// including file's own entries take precedence over them. project marks the
+// project file and its includes, which anyone with commit access can edit.
+func (cfg *Config) mergeFile(l Layer, project bool, stack []string) error {
+	path, err := filepath.Abs(l.Path)
+	if err != nil {
+		path = l.Path
+	}
+	if slices.Contains(stack, path) {
+		return fmt.Errorf("config include cycle: %s -> %s", strings.Join(stack, " -> "), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !l.Required {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := ValidateFile(path, data); err != nil {
+		return err
+	}
+	var f configFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	stack = append(stack, path)
+	for _, inc := range f.Include {
+		incPath := expandHome(inc)
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(path), incPath)
+		}
+		if err := cfg.mergeFile(Layer{Path: incPath, Source: SourceInclude, Required: true}, project, stack); err != nil {
+			return err
+		}
+		cfg.Sources = append(cfg.Sources, Layer{Path: incPath, Source: SourceInclude, Required: true})
+	}
+
+	for name, filters := range f.Presets {
+		cfg.Presets[name] = filters
+		cfg.Origins[OriginKey("presets", name)] = path
+	}
+	for alias, full := range f.Aliases {
+		cfg.Aliases[alias] = full
+		cfg.Origins[OriginKey("aliases", alias)] = path
+	}
+	if f.Redaction != nil {
+		cfg.mergeRedaction(*f.Redaction, path, project)
+	}
+	return nil
+}
+
+// mergeRedaction merges a redaction block field by field: each field a file
+// sets replaces the lower layers' value. A project file may only strengthen
+// redaction: it cannot turn it off once on, its endpoints and rules are added
+// to the ones already set, and its detectors and fields only add to a list
+// that is already narrowed, since an empty list means all of them. Anything
+// it would weaken is ignored with a warning.
+func (cfg *Config) mergeRedaction(r redactionFile, path string, project bool) {
+	rc := &cfg.Redaction
+	if r.Enabled != nil {
+		if !*r.Enabled && rc.Enabled && project {
+			warnf("%s sets redaction.enabled: false; keeping redaction on as set in %s (pass --redact=false to turn it off)", path, cfg.Origins[OriginKey("redaction", "enabled")])
+		} else {
+			rc.Enabled = *r.Enabled
+			cfg.Origins[OriginKey("redaction", "enabled")] = path
+		}
+	}
+	if !project {
+		if r.Endpoints != nil {
+			rc.Endpoints = r.Endpoints
+		}
+		if r.Detectors != nil {
+			rc.Detectors = r.Detectors
+		}
+		if r.Fields != nil {
+			rc.Fields = r.Fields
+		}
+		if r.Rules != nil {
+			rc.Rules = r.Rules
+		}
+		cfg.Origins["redaction"] = path
+		return
+	}
+
+	rc.Endpoints = appendNew(rc.Endpoints, r.Endpoints)
+	rc.Detectors = widen(path, "detectors", rc.Detectors, r.Detectors)
+	rc.Fields = widen(path, "fields", rc.Fields, r.Fields)
+	for _, rule := range r.Rules {
+		if slices.ContainsFunc(rc.Rules, func(existing redact.Rule) bool { return existing.Name == rule.Name }) {
+			warnf("%s redefines redaction rule %q; a project file can only add rules, keeping the existing one", path, rule.Name)
+			continue
+		}
+		rc.Rules = append(rc.Rules, rule)
+	}
+	cfg.Origins["redaction"] = path
+}
+
+// widen merges a project file's detectors or fields into list. An empty list
+// means all of them and is kept as it is.
+func widen(path, name string, list, add []string) []string {
+	if add == nil {
+		return list
+	}
+	if len(list) == 0 {
+		warnf("%s narrows redaction.%s; a project file can only add to them, keeping all", path, name)
+		return list
+	}
+	return appendNew(list, add)
+}
+
+// appendNew appends the values of add that list does not hold yet.
+func appendNew(list, add []string) []string {
+	for _, v := range add {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// OriginKey is the Config.Origins key for a preset or alias.
+func OriginKey(section, name string) string {
+	return section + "." + name
+}
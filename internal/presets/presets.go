@@ -18,15 +18,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/lakerunner/cli/internal/redact"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Presets map[string][]string `yaml:"presets"`
-	Aliases map[string]string   `yaml:"aliases"`
+	Presets   map[string][]string `yaml:"presets"`
+	Aliases   map[string]string   `yaml:"aliases"`
+	Redaction redact.Config       `yaml:"redaction"`
+
+	// Origins maps "presets.NAME", "aliases.NAME", "redaction.enabled" and
+	// "redaction" (the last file with a redaction block) to the file that
+	// set them; Sources lists the files merged, lowest precedence first.
+	Origins map[string]string `yaml:"-"`
+	Sources []Layer           `yaml:"-"`
 }
 
 func configPath() string {
@@ -37,33 +45,10 @@ func configPath() string {
 	return filepath.Join(home, ".lakerunner", "config.yaml")
 }
 
+// Load merges the layered config files returned by Layers. Later layers
+// override earlier ones per preset, per alias and per redaction field.
 func Load() (*Config, error) {
-	path := configPath()
-	if path == "" {
-		return &Config{Presets: make(map[string][]string), Aliases: make(map[string]string)}, nil
-	}
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Config{Presets: make(map[string][]string), Aliases: make(map[string]string)}, nil
-		}
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
-	}
-
-	if cfg.Presets == nil {
-		cfg.Presets = make(map[string][]string)
-	}
-	if cfg.Aliases == nil {
-		cfg.Aliases = make(map[string]string)
-	}
-
-	return &cfg, nil
+	return loadLayers(Layers())
 }
 
 // ResolveFilters expands any aliased keys in the given filters.
@@ -90,13 +75,39 @@ func ResolveFilters(filters []string) ([]string, error) {
 	return resolved, nil
 }
 
+// AnnotationSkipWarnings marks commands that report Warnings themselves.
+const AnnotationSkipWarnings = "lakerunner/skip-config-warnings"
+
+// warnings holds problems found while registering alias flags; see Warnings.
+var warnings []string
+
+func warnf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !slices.Contains(warnings, msg) {
+		warnings = append(warnings, msg)
+	}
+}
+
+// Warnings returns the problems RegisterAliasFlags ran into: a config that
+// failed to load, a project file trying to turn redaction off, or aliases
+// skipped because they collide with built-in flags.
+// Flags are registered while commands are built, so these are reported later.
+func Warnings() []string {
+	return warnings
+}
+
 // RegisterAliasFlags registers user-defined aliases as CLI flags on the given command.
 // Single-char aliases become short flags (e.g., -i for resource_installation).
 // Multi-char aliases become long flags (e.g., --svc for service_name).
+// Aliases that collide with the command's own flags are skipped with a warning.
 // Returns a map of fullKey -> value pointer for use with CollectAliasFilters.
 func RegisterAliasFlags(cmd *cobra.Command) map[string]*string {
 	cfg, err := Load()
-	if err != nil || len(cfg.Aliases) == 0 {
+	if err != nil {
+		warnf("alias flags are unavailable: %v", err)
+		return nil
+	}
+	if len(cfg.Aliases) == 0 {
 		return nil
 	}
 	values := make(map[string]*string)
@@ -107,13 +118,19 @@ func RegisterAliasFlags(cmd *cobra.Command) map[string]*string {
 
 		if len(alias) == 1 {
 			// Single-char alias: register as short flag with full key as long name
-			if cmd.Flags().ShorthandLookup(alias) != nil || cmd.Flags().Lookup(longName) != nil {
+			if f := cmd.Flags().ShorthandLookup(alias); f != nil {
+				warnf("alias %q (%s) collides with built-in flag -%s (--%s) and is skipped; use -f %s:VALUE", alias, fullKey, alias, f.Name, fullKey)
+				continue
+			}
+			if cmd.Flags().Lookup(longName) != nil {
+				warnf("alias %q (%s) collides with built-in flag --%s and is skipped; use -f %s:VALUE", alias, fullKey, longName, fullKey)
 				continue
 			}
 			cmd.Flags().StringVarP(val, longName, alias, "", desc)
 		} else {
 			// Multi-char alias: register as long flag
 			if cmd.Flags().Lookup(alias) != nil {
+				warnf("alias %q (%s) collides with built-in flag --%s and is skipped; use -f %s:VALUE", alias, fullKey, alias, fullKey)
 				continue
 			}
 			cmd.Flags().StringVar(val, alias, "", desc)
@@ -142,7 +159,14 @@ func GetFilters(presetName string) ([]string, error) {
 
 	filters, ok := cfg.Presets[presetName]
 	if !ok {
-		return nil, fmt.Errorf("preset '%s' not found in %s", presetName, configPath())
+		var paths []string
+		for _, l := range cfg.Sources {
+			paths = append(paths, l.Path)
+		}
+		if len(paths) == 0 {
+			paths = append(paths, configPath())
+		}
+		return nil, fmt.Errorf("preset '%s' not found in %s", presetName, strings.Join(paths, ", "))
 	}
 
 	return filters, nil
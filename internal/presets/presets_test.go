@@ -0,0 +1,375 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package presets
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lakerunner/cli/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "home", "config.yaml")
+	project := filepath.Join(dir, "repo", ProjectConfigName)
+	shared := filepath.Join(dir, "repo", "shared", "team.yaml")
+	explicit := filepath.Join(dir, "explicit.yaml")
+
+	writeFile(t, user, `
+presets:
+  mine: [service:me]
+  payments: [service:old]
+aliases:
+  i: resource_installation
+  svc: service_name
+`)
+	writeFile(t, project, `
+include: [shared/team.yaml]
+presets:
+  payments: [service:payments, env:prod]
+`)
+	writeFile(t, shared, `
+presets:
+  payments: [service:shared]
+  team: [team:core]
+aliases:
+  svc: resource_service_name
+redaction:
+  enabled: true
+`)
+	writeFile(t, explicit, `
+aliases:
+  i: installation
+`)
+
+	cfg, err := loadLayers([]Layer{
+		{Path: user, Source: SourceUser},
+		{Path: project, Source: SourceProject},
+		{Path: explicit, Source: SourceFlag, Required: true},
+	})
+	if err != nil {
+		t.Fatalf("loadLayers() error: %v", err)
+	}
+
+	// The project file beats both the user file and its own include.
+	if got := cfg.Presets["payments"]; !slices.Equal(got, []string{"service:payments", "env:prod"}) {
+		t.Errorf("payments = %v", got)
+	}
+	if cfg.Origins["presets.payments"] != project {
+		t.Errorf("payments origin = %q", cfg.Origins["presets.payments"])
+	}
+	if cfg.Origins["presets.team"] != shared || cfg.Origins["presets.mine"] != user {
+		t.Errorf("origins = %v", cfg.Origins)
+	}
+	if cfg.Aliases["svc"] != "resource_service_name" || cfg.Aliases["i"] != "installation" {
+		t.Errorf("aliases = %v", cfg.Aliases)
+	}
+	if cfg.Origins["aliases.i"] != explicit {
+		t.Errorf("alias i origin = %q", cfg.Origins["aliases.i"])
+	}
+	if !cfg.Redaction.Enabled || cfg.Origins["redaction"] != shared {
+		t.Errorf("redaction = %+v from %q", cfg.Redaction, cfg.Origins["redaction"])
+	}
+
+	var sources []string
+	for _, l := range cfg.Sources {
+		sources = append(sources, l.Source)
+	}
+	if want := []string{SourceUser, SourceInclude, SourceProject, SourceFlag}; !slices.Equal(sources, want) {
+		t.Errorf("sources = %v, want %v", sources, want)
+	}
+}
+
+func TestLoadLayersRedaction(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "config.yaml")
+	env := filepath.Join(dir, "env.yaml")
+	writeFile(t, user, `
+redaction:
+  enabled: true
+  endpoints: [https://prod.example.com]
+  fields: [message]
+  rules:
+    - name: order
+      pattern: 'ORD-\d+'
+`)
+	writeFile(t, env, `
+redaction:
+  detectors: [email]
+`)
+	load := func(t *testing.T, project string, layers ...Layer) (*Config, []string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), ProjectConfigName)
+		writeFile(t, path, project)
+		warnings = nil
+		t.Cleanup(func() { warnings = nil })
+		layers = append([]Layer{{Path: user, Source: SourceUser}, {Path: path, Source: SourceProject}}, layers...)
+		cfg, err := loadLayers(layers)
+		if err != nil {
+			t.Fatalf("loadLayers() error: %v", err)
+		}
+		return cfg, Warnings()
+	}
+	userRule := redact.Rule{Name: "order", Pattern: `ORD-\d+`}
+
+	tests := []struct {
+		name     string
+		project  string
+		want     redact.Config
+		warnings []string
+	}{
+		{
+			name:     "cannot turn it off",
+			project:  "redaction:\n  enabled: false\n",
+			want:     redact.Config{Enabled: true, Endpoints: []string{"https://prod.example.com"}, Fields: []string{"message"}, Rules: []redact.Rule{userRule}},
+			warnings: []string{"sets redaction.enabled: false"},
+		},
+		{
+			name:     "cannot narrow detectors",
+			project:  "redaction:\n  detectors: [email]\n",
+			want:     redact.Config{Enabled: true, Endpoints: []string{"https://prod.example.com"}, Fields: []string{"message"}, Rules: []redact.Rule{userRule}},
+			warnings: []string{"narrows redaction.detectors"},
+		},
+		{
+			name:    "adds fields and endpoints",
+			project: "redaction:\n  fields: [user_id]\n  endpoints: [https://staging.example.com]\n",
+			want: redact.Config{
+				Enabled:   true,
+				Endpoints: []string{"https://prod.example.com", "https://staging.example.com"},
+				Fields:    []string{"message", "user_id"},
+				Rules:     []redact.Rule{userRule},
+			},
+		},
+		{
+			name:    "adds rules but cannot replace them",
+			project: "redaction:\n  rules:\n    - name: order\n      pattern: nothing\n    - name: ticket\n      pattern: 'TCK-\\d+'\n",
+			want: redact.Config{
+				Enabled:   true,
+				Endpoints: []string{"https://prod.example.com"},
+				Fields:    []string{"message"},
+				Rules:     []redact.Rule{userRule, {Name: "ticket", Pattern: `TCK-\d+`}},
+			},
+			warnings: []string{`redefines redaction rule "order"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, got := load(t, tt.project)
+			if !reflect.DeepEqual(cfg.Redaction, tt.want) {
+				t.Errorf("redaction = %+v, want %+v", cfg.Redaction, tt.want)
+			}
+			if len(got) != len(tt.warnings) {
+				t.Fatalf("Warnings() = %q, want %d", got, len(tt.warnings))
+			}
+			for i, w := range tt.warnings {
+				if !strings.Contains(got[i], ProjectConfigName+" "+w) {
+					t.Errorf("warning %q does not mention %q", got[i], w)
+				}
+			}
+		})
+	}
+
+	t.Run("includes of the project file", func(t *testing.T) {
+		shared := filepath.Join(t.TempDir(), "team.yaml")
+		writeFile(t, shared, "redaction:\n  enabled: false\n  fields: []\n")
+		cfg, got := load(t, "include: ["+shared+"]\n")
+		if !cfg.Redaction.Enabled || !slices.Equal(cfg.Redaction.Fields, []string{"message"}) || len(got) != 1 {
+			t.Errorf("redaction = %+v, warnings %q", cfg.Redaction, got)
+		}
+	})
+
+	t.Run("files the user names may weaken it", func(t *testing.T) {
+		cfg, got := load(t, "", Layer{Path: env, Source: SourceEnv, Required: true})
+		if !slices.Equal(cfg.Redaction.Detectors, []string{"email"}) || cfg.Origins["redaction"] != env || len(got) != 0 {
+			t.Errorf("redaction = %+v from %q, warnings %q", cfg.Redaction, cfg.Origins["redaction"], got)
+		}
+		writeFile(t, env, "redaction:\n  enabled: false\n")
+		if cfg, _ := load(t, "", Layer{Path: env, Source: SourceEnv, Required: true}); cfg.Redaction.Enabled {
+			t.Error("$LAKERUNNER_CONFIG could not turn redaction off")
+		}
+	})
+}
+
+func TestLoadLayersMissing(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := loadLayers([]Layer{{Path: filepath.Join(dir, "absent.yaml"), Source: SourceUser}})
+	if err != nil || len(cfg.Sources) != 0 {
+		t.Errorf("optional missing layer: %v, %v", cfg, err)
+	}
+	if _, err := loadLayers([]Layer{{Path: filepath.Join(dir, "absent.yaml"), Source: SourceFlag, Required: true}}); err == nil {
+		t.Error("expected error for missing --config file")
+	}
+}
+
+func TestLoadLayersIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "include: [a.yaml]\n")
+	_, err := loadLayers([]Layer{{Path: filepath.Join(dir, "a.yaml"), Source: SourceProject}})
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected include cycle error, got %v", err)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, ProjectConfigName)
+	writeFile(t, project, "presets: {}\n")
+	deep := filepath.Join(dir, "a", "b", "c")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := findProjectConfig(deep); got != project {
+		t.Errorf("findProjectConfig() = %q, want %q", got, project)
+	}
+}
+
+func TestConfigFlagFromArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"logs", "get", "--config", "team.yaml"}, "team.yaml"},
+		{[]string{"--config=team.yaml", "presets", "list"}, "team.yaml"},
+		{[]string{"logs", "get", "-l", "ERROR"}, ""},
+		{[]string{"query", "save", "x", "--", "--config", "nope"}, ""},
+	}
+	for _, tt := range tests {
+		if got := configFlagFromArgs(tt.args); got != tt.want {
+			t.Errorf("configFlagFromArgs(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{"valid", `
+include: [team.yaml]
+presets:
+  payments: [service:payments]
+  empty:
+aliases:
+  svc: service_name
+redaction:
+  enabled: true
+  detectors: [email]
+  rules:
+    - name: order
+      pattern: 'ORD-\d+'
+`, nil},
+		{"empty sections", "presets:\naliases:\n", nil},
+		{"unknown top-level key", "preset:\n  a: [b:c]\n", []string{`cfg.yaml:1:1: unknown key "preset" in config file`}},
+		{"bad filter", "presets:\n  payments: [payments]\n", []string{`cfg.yaml:2:14: preset "payments": filter "payments" is not in key:value form`}},
+		{"filters not a list", "presets:\n  payments: service:payments\n", []string{`cfg.yaml:2:13: preset "payments" must be a list`}},
+		{"bad alias name", "aliases:\n  1x: service_name\n", []string{`cfg.yaml:2:3: alias "1x" must start with a letter`}},
+		{"unknown detector", "redaction:\n  detectors: [email, ssn]\n", []string{`cfg.yaml:2:22: unknown detector "ssn"`}},
+		{"bad enabled", "redaction:\n  enabled: yes please\n", []string{`cfg.yaml:2:12: redaction.enabled must be true or false`}},
+		{"bad rule", "redaction:\n  rules:\n    - name: x\n      pattern: '('\n    - name: y\n", []string{
+			`cfg.yaml:4:16: invalid pattern`,
+			`cfg.yaml:5:7: redaction rule has no pattern`,
+		}},
+		{"several problems", "presets:\n  a: [b]\naliases:\n  -x: y\n", []string{
+			`cfg.yaml:2:7: preset "a"`,
+			`cfg.yaml:4:3: alias "-x"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFile("cfg.yaml", []byte(tt.yaml))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q", tt.want)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(lines), len(tt.want), err)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("error %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadLayersRejectsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "presets:\n  payments: [payments]\n")
+	_, err := loadLayers([]Layer{{Path: path, Source: SourceUser}})
+	if err == nil || !strings.Contains(err.Error(), path+":2:") {
+		t.Fatalf("expected positioned validation error, got %v", err)
+	}
+}
+
+func TestRegisterAliasFlagsCollisions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ConfigEnvVar, "")
+	t.Chdir(home)
+	writeFile(t, filepath.Join(home, ".lakerunner", "config.yaml"), `
+aliases:
+  l: log_level
+  app: service_name
+  i: resource_installation
+`)
+	warnings = nil
+	t.Cleanup(func() { warnings = nil })
+
+	cmd := &cobra.Command{Use: "get"}
+	cmd.Flags().StringP("level", "l", "", "")
+	cmd.Flags().String("app", "", "")
+	values := RegisterAliasFlags(cmd)
+
+	if _, ok := values["resource_installation"]; !ok || len(values) != 1 {
+		t.Errorf("registered aliases = %v, want only resource_installation", slices.Collect(maps.Keys(values)))
+	}
+	got := Warnings()
+	slices.Sort(got)
+	want := []string{
+		`alias "app" (service_name) collides with built-in flag --app and is skipped; use -f service_name:VALUE`,
+		`alias "l" (log_level) collides with built-in flag -l (--level) and is skipped; use -f log_level:VALUE`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Warnings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a schema problem at a position in a config file.
+type ValidationError struct {
+	Path   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Msg)
+}
+
+var aliasNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// schema lists the keys allowed in each mapping of a config file.
+var (
+	topLevelKeys  = []string{"include", "presets", "aliases", "redaction"}
+	redactionKeys = []string{"enabled", "endpoints", "detectors", "fields", "rules"}
+	ruleKeys      = []string{"name", "pattern", "replacement", "fields"}
+)
+
+// validator collects every problem in a document rather than stopping at the first.
+type validator struct {
+	path string
+	errs []error
+}
+
+func (v *validator) errorf(n *yaml.Node, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Path: v.path, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format, args...)})
+}
+
+// ValidateFile parses data and checks it against the config schema. The
+// returned error joins one ValidationError per problem.
+func ValidateFile(path string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	v := &validator{path: path}
+	if len(doc.Content) > 0 {
+		v.document(doc.Content[0])
+	}
+	return errors.Join(v.errs...)
+}
+
+func (v *validator) document(n *yaml.Node) {
+	if !v.isKind(n, yaml.MappingNode, "config file") {
+		return
+	}
+	v.mapping(n, "config file", topLevelKeys, func(key string, value *yaml.Node) {
+		switch key {
+		case "include":
+			v.stringList(value, "include", func(s string, item *yaml.Node) {
+				if s == "" {
+					v.errorf(item, "include path is empty")
+				}
+			})
+		case "presets":
+			v.presets(value)
+		case "aliases":
+			v.aliases(value)
+		case "redaction":
+			v.redaction(value)
+		}
+	})
+}
+
+func (v *validator) presets(n *yaml.Node) {
+	if !v.isKind(n, yaml.MappingNode, "presets") {
+		return
+	}
+	v.mapping(n, "presets", nil, func(name string, value *yaml.Node) {
+		v.stringList(value, fmt.Sprintf("preset %q", name), func(filter string, item *yaml.Node) {
+			if key, _, ok := strings.Cut(filter, ":"); !ok || key == "" {
+				v.errorf(item, "preset %q: filter %q is not in key:value form", name, filter)
+			}
+		})
+	})
+}
+
+func (v *validator) aliases(n *yaml.Node) {
+	if !v.isKind(n, yaml.MappingNode, "aliases") {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if !aliasNameRe.MatchString(key.Value) {
+			v.errorf(key, "alias %q must start with a letter and contain only letters, digits, '_' and '-'", key.Value)
+		}
+		if value.Kind != yaml.ScalarNode || value.Value == "" {
+			v.errorf(value, "alias %q must map to a tag name", key.Value)
+		}
+	}
+}
+
+func (v *validator) redaction(n *yaml.Node) {
+	if !v.isKind(n, yaml.MappingNode, "redaction") {
+		return
+	}
+	v.mapping(n, "redaction", redactionKeys, func(key string, value *yaml.Node) {
+		switch key {
+		case "enabled":
+			if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!bool" {
+				v.errorf(value, "redaction.enabled must be true or false")
+			}
+		case "endpoints", "fields":
+			v.stringList(value, "redaction."+key, nil)
+		case "detectors":
+			v.stringList(value, "redaction.detectors", func(d string, item *yaml.Node) {
+				if !slices.Contains(redact.AllDetectors, d) {
+					v.errorf(item, "unknown detector %q (expected one of %s)", d, strings.Join(redact.AllDetectors, ", "))
+				}
+			})
+		case "rules":
+			if !v.isKind(value, yaml.SequenceNode, "redaction.rules") {
+				return
+			}
+			for _, rule := range value.Content {
+				v.rule(rule)
+			}
+		}
+	})
+}
+
+func (v *validator) rule(n *yaml.Node) {
+	if !v.isKind(n, yaml.MappingNode, "redaction rule") {
+		return
+	}
+	var hasPattern bool
+	v.mapping(n, "redaction rule", ruleKeys, func(key string, value *yaml.Node) {
+		switch key {
+		case "pattern":
+			hasPattern = true
+			if _, err := regexp.Compile(value.Value); err != nil {
+				v.errorf(value, "invalid pattern: %v", err)
+			}
+		case "fields":
+			v.stringList(value, "redaction rule fields", nil)
+		}
+	})
+	if !hasPattern {
+		v.errorf(n, "redaction rule has no pattern")
+	}
+}
+
+// mapping walks the key/value pairs of n, rejecting keys not in allowed
+// (when allowed is non-nil).
+func (v *validator) mapping(n *yaml.Node, what string, allowed []string, fn func(key string, value *yaml.Node)) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if allowed != nil && !slices.Contains(allowed, key.Value) {
+			v.errorf(key, "unknown key %q in %s (expected %s)", key.Value, what, strings.Join(allowed, ", "))
+			continue
+		}
+		fn(key.Value, value)
+	}
+}
+
+// stringList checks that n is a list of strings, calling fn for each item.
+func (v *validator) stringList(n *yaml.Node, what string, fn func(s string, item *yaml.Node)) {
+	if !v.isKind(n, yaml.SequenceNode, what) {
+		return
+	}
+	for _, item := range n.Content {
+		if item.Kind != yaml.ScalarNode {
+			v.errorf(item, "%s: expected a string", what)
+			continue
+		}
+		if fn != nil {
+			fn(item.Value, item)
+		}
+	}
+}
+
+func (v *validator) isKind(n *yaml.Node, kind yaml.Kind, what string) bool {
+	if n.Kind == kind {
+		return true
+	}
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null" {
+		// An empty section such as "presets:" is allowed.
+		return false
+	}
+	expected := map[yaml.Kind]string{yaml.MappingNode: "a mapping", yaml.SequenceNode: "a list", yaml.ScalarNode: "a value"}[kind]
+	v.errorf(n, "%s must be %s", what, expected)
+	return false
+}
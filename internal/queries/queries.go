@@ -0,0 +1,214 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package queries stores named `logs get` invocations with ${param}
+// placeholders, one YAML file per query in ~/.lakerunner/queries.
+package queries
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Query is a saved `logs get` invocation.
+type Query struct {
+	Name        string            `yaml:"-"`
+	Description string            `yaml:"description,omitempty"`
+	Params      map[string]string `yaml:"params,omitempty"`
+	Args        []string          `yaml:"args"`
+}
+
+var (
+	nameRe        = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	placeholderRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// Dir returns the directory holding saved queries.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".lakerunner", "queries"), nil
+}
+
+// ValidateName rejects names that are not safe as file names.
+func ValidateName(name string) error {
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("invalid query name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Path returns the file a query is stored in.
+func Path(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// Load reads a saved query.
+func Load(name string) (*Query, error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("query '%s' not found in %s", name, filepath.Dir(path))
+		}
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+	var q Query
+	if err := yaml.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse query %s: %w", path, err)
+	}
+	q.Name = name
+	return &q, nil
+}
+
+// List returns the names of all saved queries, sorted.
+func List() ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read queries directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".yaml"); ok && !e.IsDir() && ValidateName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Save writes q, refusing to replace an existing query unless overwrite is set.
+func Save(q *Query, overwrite bool) error {
+	path, err := Path(q.Name)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("query '%s' already exists (use --force to replace it)", q.Name)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create queries directory: %w", err)
+	}
+	data, err := yaml.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write query: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a saved query.
+func Delete(name string) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("query '%s' not found", name)
+		}
+		return fmt.Errorf("failed to delete query: %w", err)
+	}
+	return nil
+}
+
+// Placeholders returns the ${param} names used in the query's args, sorted.
+func (q *Query) Placeholders() []string {
+	var names []string
+	for _, arg := range q.Args {
+		for _, m := range placeholderRe.FindAllStringSubmatch(arg, -1) {
+			if !slices.Contains(names, m[1]) {
+				names = append(names, m[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand substitutes ${param} placeholders using values, falling back to the
+// query's defaults. Unknown values and placeholders without a value are errors.
+func (q *Query) Expand(values map[string]string) ([]string, error) {
+	used := q.Placeholders()
+	for name := range values {
+		if !slices.Contains(used, name) {
+			return nil, fmt.Errorf("unknown parameter %q for query '%s' (has: %s)", name, q.Name, strings.Join(used, ", "))
+		}
+	}
+	resolved := make(map[string]string, len(used))
+	var missing []string
+	for _, name := range used {
+		if v, ok := values[name]; ok {
+			resolved[name] = v
+		} else if v, ok := q.Params[name]; ok {
+			resolved[name] = v
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing value for parameter(s) %s of query '%s' (use --param NAME=VALUE)", strings.Join(missing, ", "), q.Name)
+	}
+
+	out := make([]string, len(q.Args))
+	for i, arg := range q.Args {
+		out[i] = placeholderRe.ReplaceAllStringFunc(arg, func(m string) string {
+			return resolved[placeholderRe.FindStringSubmatch(m)[1]]
+		})
+	}
+	return out, nil
+}
+
+// ParseParams turns NAME=VALUE pairs into a map.
+func ParseParams(pairs []string) (map[string]string, error) {
+	out := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid parameter %q: expected NAME=VALUE", p)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
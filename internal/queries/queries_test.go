@@ -0,0 +1,117 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queries
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	q := &Query{
+		Name:   "pod-errors",
+		Params: map[string]string{"level": "ERROR"},
+		Args:   []string{"-f", "k8s_pod_name:${pod}", "-l", "${level}", "-M", "${pod} crashed"},
+	}
+	if got, want := q.Placeholders(), []string{"level", "pod"}; !slices.Equal(got, want) {
+		t.Errorf("Placeholders() = %v, want %v", got, want)
+	}
+
+	got, err := q.Expand(map[string]string{"pod": "api-7f9"})
+	if err != nil {
+		t.Fatalf("Expand() error: %v", err)
+	}
+	want := []string{"-f", "k8s_pod_name:api-7f9", "-l", "ERROR", "-M", "api-7f9 crashed"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expand() = %v, want %v", got, want)
+	}
+
+	got, err = q.Expand(map[string]string{"pod": "p", "level": "WARN"})
+	if err != nil || got[3] != "WARN" {
+		t.Errorf("Expand() with override = %v, %v", got, err)
+	}
+
+	if _, err := q.Expand(nil); err == nil || !strings.Contains(err.Error(), "pod") {
+		t.Errorf("Expand() missing param error = %v", err)
+	}
+	if _, err := q.Expand(map[string]string{"pod": "p", "typo": "x"}); err == nil {
+		t.Error("expected error for unknown parameter")
+	}
+}
+
+func TestSaveLoadListDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if names, err := List(); err != nil || len(names) != 0 {
+		t.Fatalf("List() on empty dir = %v, %v", names, err)
+	}
+	q := &Query{Name: "errors", Description: "all errors", Args: []string{"-l", "ERROR", "-s", "e-1h"}}
+	if err := Save(q, false); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := Save(q, false); err == nil {
+		t.Error("expected error saving over an existing query without overwrite")
+	}
+	if err := Save(&Query{Name: "b-query", Args: []string{"-a", "x"}}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load("errors")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.Name != "errors" || got.Description != "all errors" || !slices.Equal(got.Args, q.Args) {
+		t.Errorf("Load() = %+v", got)
+	}
+
+	names, err := List()
+	if err != nil || !slices.Equal(names, []string{"b-query", "errors"}) {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+
+	if err := Delete("errors"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := Load("errors"); err == nil {
+		t.Error("expected error loading deleted query")
+	}
+	if err := Delete("errors"); err == nil {
+		t.Error("expected error deleting missing query")
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"ok", "pod-errors", "v1.2_x"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc", "a/b", "-flag", "sp ace"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) expected error", name)
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	got, err := ParseParams([]string{"pod=api-7f9", "q=a=b"})
+	if err != nil || got["pod"] != "api-7f9" || got["q"] != "a=b" {
+		t.Errorf("ParseParams() = %v, %v", got, err)
+	}
+	if _, err := ParseParams([]string{"novalue"}); err == nil {
+		t.Error("expected error for missing '='")
+	}
+}
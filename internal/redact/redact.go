@@ -0,0 +1,279 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redact masks PII and secrets (emails, IPs, JWTs, AWS keys, card
+// numbers and user-defined patterns) in log fields before they are printed.
+package redact
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lakerunner/cli/internal/fieldnames"
+)
+
+// Built-in detector names
+const (
+	DetectorJWT        = "jwt"
+	DetectorAWSKey     = "aws_key"
+	DetectorEmail      = "email"
+	DetectorCreditCard = "credit_card"
+	DetectorIPv6       = "ipv6"
+	DetectorIPv4       = "ipv4"
+)
+
+// AllDetectors lists the built-in detectors in the order they are applied.
+var AllDetectors = []string{DetectorJWT, DetectorAWSKey, DetectorEmail, DetectorCreditCard, DetectorIPv6, DetectorIPv4}
+
+// Config is the `redaction:` section of ~/.lakerunner/config.yaml.
+type Config struct {
+	// Enabled turns redaction on by default for every endpoint.
+	Enabled bool `yaml:"enabled"`
+	// Endpoints turns redaction on by default when querying one of these URLs.
+	Endpoints []string `yaml:"endpoints,omitempty"`
+	// Detectors selects built-in detectors; empty means all of them.
+	Detectors []string `yaml:"detectors,omitempty"`
+	// Fields limits built-in detectors to these fields; empty means every field.
+	Fields []string `yaml:"fields,omitempty"`
+	// Rules are additional user-defined patterns.
+	Rules []Rule `yaml:"rules,omitempty"`
+}
+
+// Rule is a user-defined redaction pattern.
+type Rule struct {
+	Name        string `yaml:"name"`
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement,omitempty"`
+	// Fields limits the rule to these fields; empty inherits Config.Fields.
+	Fields []string `yaml:"fields,omitempty"`
+}
+
+// DefaultFor reports whether redaction is on by default for endpoint.
+func (c Config) DefaultFor(endpoint string) bool {
+	if c.Enabled {
+		return true
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	for _, e := range c.Endpoints {
+		if strings.TrimSuffix(e, "/") == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// replacer rewrites every match of re; validate, if set, must accept the match.
+type replacer struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+	validate    func(string) bool
+	fields      []string
+}
+
+// appliesTo matches field against the configured names, accepting dotted
+// spellings of the underscore keys the API returns (k8s.pod.name == k8s_pod_name).
+func (r replacer) appliesTo(field string) bool {
+	if len(r.fields) == 0 {
+		return true
+	}
+	for _, f := range r.fields {
+		if fieldnames.Equal(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r replacer) replace(s string) string {
+	if r.validate == nil {
+		return r.re.ReplaceAllLiteralString(s, r.replacement)
+	}
+	return r.re.ReplaceAllStringFunc(s, func(m string) string {
+		if r.validate(m) {
+			return r.replacement
+		}
+		return m
+	})
+}
+
+var (
+	jwtRe        = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	awsKeyRe     = regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b|(?i:aws_secret_access_key)["']?\s*[=:]\s*["']?[A-Za-z0-9/+=]{40}`)
+	emailRe      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	creditCardRe = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	ipv6Re       = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+	ipv4Re       = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+)
+
+func builtin(name string) (replacer, error) {
+	r := replacer{name: name, replacement: "[REDACTED:" + name + "]"}
+	switch name {
+	case DetectorJWT:
+		r.re = jwtRe
+	case DetectorAWSKey:
+		r.re = awsKeyRe
+	case DetectorEmail:
+		r.re = emailRe
+	case DetectorCreditCard:
+		r.re = creditCardRe
+		r.validate = luhnValid
+	case DetectorIPv6:
+		r.re = ipv6Re
+		r.validate = func(m string) bool {
+			addr, err := netip.ParseAddr(m)
+			return err == nil && addr.Is6() && strings.ContainsAny(m, "0123456789abcdefABCDEF")
+		}
+	case DetectorIPv4:
+		r.re = ipv4Re
+		r.validate = func(m string) bool {
+			addr, err := netip.ParseAddr(m)
+			return err == nil && addr.Is4()
+		}
+	default:
+		return replacer{}, fmt.Errorf("unknown redaction detector %q: must be one of %s", name, strings.Join(AllDetectors, ", "))
+	}
+	return r, nil
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	var digits []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 || !cardPrefixValid(digits) {
+		return false
+	}
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// cardPrefixValid checks the issuer prefix and length of the major card
+// networks, so long numeric IDs and epoch timestamps that happen to pass the
+// Luhn check are left alone.
+func cardPrefixValid(digits []int) bool {
+	prefix := func(n int) int {
+		v := 0
+		for _, d := range digits[:n] {
+			v = v*10 + d
+		}
+		return v
+	}
+	n := len(digits)
+	switch p2, p4 := prefix(2), prefix(4); {
+	case digits[0] == 4: // Visa
+		return n == 13 || n == 16 || n == 19
+	case p2 >= 51 && p2 <= 55, p4 >= 2221 && p4 <= 2720: // Mastercard
+		return n == 16
+	case p2 == 34 || p2 == 37: // Amex
+		return n == 15
+	case p4 == 6011 || p2 == 65, prefix(3) >= 644 && prefix(3) <= 649: // Discover
+		return n >= 16
+	case p2 == 36 || p2 == 38 || prefix(3) >= 300 && prefix(3) <= 305: // Diners
+		return n == 14
+	case p4 >= 3528 && p4 <= 3589: // JCB
+		return n >= 16
+	}
+	return false
+}
+
+// Redactor applies the configured detectors and rules to log fields.
+type Redactor struct {
+	replacers []replacer
+}
+
+// New compiles cfg into a Redactor.
+func New(cfg Config) (*Redactor, error) {
+	detectors := cfg.Detectors
+	if len(detectors) == 0 {
+		detectors = AllDetectors
+	}
+	for _, name := range detectors {
+		if !slices.Contains(AllDetectors, name) {
+			_, err := builtin(name)
+			return nil, err
+		}
+	}
+	r := &Redactor{}
+	for _, rule := range cfg.Rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("redaction rule %q has no pattern", rule.Name)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in redaction rule %q: %w", rule.Name, err)
+		}
+		repl := rule.Replacement
+		if repl == "" {
+			name := rule.Name
+			if name == "" {
+				name = "custom"
+			}
+			repl = "[REDACTED:" + name + "]"
+		}
+		fields := rule.Fields
+		if len(fields) == 0 {
+			fields = cfg.Fields
+		}
+		r.replacers = append(r.replacers, replacer{name: rule.Name, re: re, replacement: repl, fields: fields})
+	}
+	// Built-in detectors run in a fixed order so e.g. JWTs are not half-eaten by the email detector.
+	for _, name := range AllDetectors {
+		if !slices.Contains(detectors, name) {
+			continue
+		}
+		rep, err := builtin(name)
+		if err != nil {
+			return nil, err
+		}
+		rep.fields = cfg.Fields
+		r.replacers = append(r.replacers, rep)
+	}
+	return r, nil
+}
+
+// Redact returns value with every sensitive match for field replaced.
+func (r *Redactor) Redact(field, value string) string {
+	for _, rep := range r.replacers {
+		if rep.appliesTo(field) {
+			value = rep.replace(value)
+		}
+	}
+	return value
+}
+
+// Apply redacts every string value in tags in place.
+func (r *Redactor) Apply(tags map[string]any) {
+	for k, v := range tags {
+		if s, ok := v.(string); ok {
+			tags[k] = r.Redact(k, s)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package synth generates plausible log traffic for demos and tests: a few
+// services with pods, weighted levels, plain text, logfmt and JSON messages,
+// trace IDs, a volume profile over time and optional error bursts. The same
+// options always give the same records.
+package synth
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Volume profiles.
+const (
+	ProfileFlat = "flat"
+	// ProfileDiurnal peaks in the afternoon and bottoms out at night (UTC).
+	ProfileDiurnal = "diurnal"
+)
+
+// Profiles lists the supported volume profiles.
+var Profiles = []string{ProfileFlat, ProfileDiurnal}
+
+// maxBuckets bounds the resolution at which the profile shapes volume.
+const maxBuckets = 1000
+
+// DefaultServices are used when Options.Services is empty.
+var DefaultServices = []string{"frontend", "checkout", "cart", "payments", "inventory"}
+
+// LevelWeight is the relative frequency of a level.
+type LevelWeight struct {
+	Level  string
+	Weight int
+}
+
+// DefaultLevels are weighted towards INFO like real traffic.
+var DefaultLevels = []LevelWeight{
+	{"DEBUG", 10},
+	{"INFO", 70},
+	{"WARN", 12},
+	{"ERROR", 8},
+}
+
+// Options configures Generate.
+type Options struct {
+	Start, End time.Time
+	// Count is the number of records before error bursts are added.
+	Count    int
+	Services []string
+	Levels   []LevelWeight
+	Profile  string
+	// Bursts adds this many windows of BurstLength in which one service
+	// logs a flood of errors.
+	Bursts      int
+	BurstLength time.Duration
+	Seed        uint64
+}
+
+// Record is one generated log line.
+type Record struct {
+	Time    time.Time
+	Service string
+	Pod     string
+	Level   string
+	Message string
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// messages mixes plain text, logfmt and JSON lines so parser stages have
+// something to work on.
+var messages = map[string][]func(r *rand.Rand) string{
+	"DEBUG": {
+		func(r *rand.Rand) string { return fmt.Sprintf("cache hit for key user:%d", r.IntN(1000)) },
+		func(r *rand.Rand) string { return fmt.Sprintf("connection pool size=%d idle=%d", 20, r.IntN(20)) },
+	},
+	"INFO": {
+		func(r *rand.Rand) string {
+			return fmt.Sprintf("GET /api/%s %d in %dms", pick(r, "items", "cart", "user", "orders"), 200, 2+r.IntN(60))
+		},
+		func(r *rand.Rand) string {
+			return fmt.Sprintf("method=%s path=/api/%s status=%d duration=%dms", pick(r, "GET", "POST"), pick(r, "items", "cart", "checkout"), 200, 5+r.IntN(200))
+		},
+		func(r *rand.Rand) string { return fmt.Sprintf("user %d logged in", r.IntN(10000)) },
+	},
+	"WARN": {
+		func(r *rand.Rand) string { return fmt.Sprintf("slow query took %dms", 500+r.IntN(2000)) },
+		func(r *rand.Rand) string {
+			return fmt.Sprintf("retrying request to %s (attempt %d)", pick(r, "payments", "inventory"), 1+r.IntN(3))
+		},
+	},
+	"ERROR": {
+		func(r *rand.Rand) string { return fmt.Sprintf("payment timeout after %d retries", 1+r.IntN(5)) },
+		func(r *rand.Rand) string {
+			return fmt.Sprintf(`{"status":%d,"path":"/api/%s","error":"upstream unavailable"}`, pick(r, 500, 502, 503), pick(r, "cart", "checkout"))
+		},
+		func(r *rand.Rand) string { return fmt.Sprintf("failed to reserve item %d: out of stock", r.IntN(500)) },
+	},
+}
+
+// burstMessages are logged during error bursts.
+var burstMessages = []func(r *rand.Rand) string{
+	func(r *rand.Rand) string {
+		return fmt.Sprintf("connection refused: dial tcp 10.0.%d.%d:5432", r.IntN(4), 2+r.IntN(250))
+	},
+	func(r *rand.Rand) string { return "circuit breaker open for db-primary" },
+}
+
+func pick[T any](r *rand.Rand, options ...T) T {
+	return options[r.IntN(len(options))]
+}
+
+// ParseLevels parses LEVEL=WEIGHT pairs separated by commas, e.g.
+// "INFO=80,WARN=15,ERROR=5".
+func ParseLevels(s string) ([]LevelWeight, error) {
+	var levels []LevelWeight
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		level, weight, ok := strings.Cut(part, "=")
+		w, err := strconv.Atoi(weight)
+		if !ok || err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid level weight %q: expected LEVEL=WEIGHT", part)
+		}
+		levels = append(levels, LevelWeight{Level: strings.ToUpper(strings.TrimSpace(level)), Weight: w})
+	}
+	return levels, nil
+}
+
+// Pods returns the pod names of service; each service has one to three.
+func Pods(service string) []string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(service))
+	pods := make([]string, 1+h.Sum32()%3)
+	for i := range pods {
+		pods[i] = fmt.Sprintf("%s-%d", service, i)
+	}
+	return pods
+}
+
+// profileWeight is the relative volume at t.
+func profileWeight(profile string, t time.Time) float64 {
+	if profile != ProfileDiurnal {
+		return 1
+	}
+	hour := float64(t.UTC().Hour()) + float64(t.UTC().Minute())/60
+	return 1 + 0.8*math.Sin(2*math.Pi*(hour-8)/24)
+}
+
+// timestamps spreads n timestamps over [start, end) following profile.
+func timestamps(n int, start, end time.Time, profile string) []time.Time {
+	buckets := min(max(n, 1), maxBuckets)
+	width := end.Sub(start) / time.Duration(buckets)
+	weights := make([]float64, buckets)
+	var total float64
+	for b := range weights {
+		weights[b] = profileWeight(profile, start.Add(time.Duration(b)*width+width/2))
+		total += weights[b]
+	}
+
+	// Largest remainder allocation keeps the total exactly n.
+	counts := make([]int, buckets)
+	remainders := make([]int, buckets)
+	allocated := 0
+	for b, w := range weights {
+		exact := float64(n) * w / total
+		counts[b] = int(exact)
+		allocated += counts[b]
+		remainders[b] = b
+	}
+	slices.SortStableFunc(remainders, func(a, b int) int {
+		fa := float64(n)*weights[a]/total - float64(counts[a])
+		fb := float64(n)*weights[b]/total - float64(counts[b])
+		return cmp.Compare(fb, fa)
+	})
+	for _, b := range remainders[:n-allocated] {
+		counts[b]++
+	}
+
+	out := make([]time.Time, 0, n)
+	for b, k := range counts {
+		for j := range k {
+			out = append(out, start.Add(time.Duration(b)*width+time.Duration(j)*width/time.Duration(k)))
+		}
+	}
+	return out
+}
+
+// Generate returns the records for opts, oldest first.
+func Generate(opts Options) ([]Record, error) {
+	if !opts.End.After(opts.Start) {
+		return nil, fmt.Errorf("invalid time range: end %s is not after start %s", opts.End, opts.Start)
+	}
+	if opts.Profile != "" && !slices.Contains(Profiles, opts.Profile) {
+		return nil, fmt.Errorf("unknown volume profile %q (expected %s)", opts.Profile, strings.Join(Profiles, ", "))
+	}
+	services := opts.Services
+	if len(services) == 0 {
+		services = DefaultServices
+	}
+	levels := opts.Levels
+	if len(levels) == 0 {
+		levels = DefaultLevels
+	}
+	var totalWeight int
+	for _, l := range levels {
+		totalWeight += l.Weight
+	}
+	if totalWeight <= 0 {
+		return nil, fmt.Errorf("level weights must not all be zero")
+	}
+
+	r := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	record := func(t time.Time, service, level, message string) Record {
+		rec := Record{Time: t, Service: service, Pod: pick(r, Pods(service)...), Level: level, Message: message}
+		for i := range rec.TraceID {
+			rec.TraceID[i] = byte(r.IntN(256))
+		}
+		for i := range rec.SpanID {
+			rec.SpanID[i] = byte(r.IntN(256))
+		}
+		return rec
+	}
+
+	records := make([]Record, 0, opts.Count)
+	for _, t := range timestamps(opts.Count, opts.Start, opts.End, opts.Profile) {
+		service := services[r.IntN(len(services))]
+		level := pickLevel(r, levels, totalWeight)
+		templates, ok := messages[level]
+		if !ok {
+			templates = messages["INFO"]
+		}
+		records = append(records, record(t, service, level, templates[r.IntN(len(templates))](r)))
+	}
+
+	burstLength := opts.BurstLength
+	if burstLength <= 0 {
+		burstLength = 5 * time.Minute
+	}
+	burstLength = min(burstLength, opts.End.Sub(opts.Start))
+	perBurst := max(opts.Count/50, 20)
+	for range opts.Bursts {
+		service := services[r.IntN(len(services))]
+		offset := time.Duration(r.Int64N(int64(opts.End.Sub(opts.Start)-burstLength) + 1))
+		for _, t := range timestamps(perBurst, opts.Start.Add(offset), opts.Start.Add(offset+burstLength), ProfileFlat) {
+			records = append(records, record(t, service, "ERROR", burstMessages[r.IntN(len(burstMessages))](r)))
+		}
+	}
+	slices.SortStableFunc(records, func(a, b Record) int { return a.Time.Compare(b.Time) })
+	return records, nil
+}
+
+func pickLevel(r *rand.Rand, levels []LevelWeight, totalWeight int) string {
+	w := r.IntN(totalWeight)
+	for _, l := range levels {
+		if w < l.Weight {
+			return l.Level
+		}
+		w -= l.Weight
+	}
+	return levels[len(levels)-1].Level
+}
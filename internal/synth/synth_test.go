@@ -0,0 +1,121 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synth
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+
+func TestGenerate(t *testing.T) {
+	opts := Options{Start: start, End: start.Add(time.Hour), Count: 600, Seed: 3}
+	a, err := Generate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate(opts)
+	if len(a) != 600 || !slices.Equal(a, b) {
+		t.Fatalf("generated %d records, deterministic=%v", len(a), slices.Equal(a, b))
+	}
+	if !slices.IsSortedFunc(a, func(x, y Record) int { return x.Time.Compare(y.Time) }) {
+		t.Error("records not in time order")
+	}
+	levels := map[string]int{}
+	for _, r := range a {
+		levels[r.Level]++
+		if r.Time.Before(opts.Start) || !r.Time.Before(opts.End) {
+			t.Fatalf("record at %s outside the range", r.Time)
+		}
+		if !slices.Contains(Pods(r.Service), r.Pod) || r.TraceID == [16]byte{} {
+			t.Fatalf("bad record %+v", r)
+		}
+	}
+	if levels["INFO"] < levels["ERROR"] || levels["ERROR"] == 0 {
+		t.Errorf("level mix = %v", levels)
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	records, err := Generate(Options{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Count:    100,
+		Services: []string{"api"},
+		Levels:   []LevelWeight{{"WARN", 1}},
+		Bursts:   2,
+		Seed:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errors int
+	for _, r := range records {
+		if r.Service != "api" {
+			t.Fatalf("unexpected service %q", r.Service)
+		}
+		if r.Level == "ERROR" {
+			errors++
+		}
+	}
+	// Two bursts of at least 20 errors each on top of 100 warnings.
+	if len(records) != 140 || errors != 40 {
+		t.Errorf("got %d records with %d errors", len(records), errors)
+	}
+}
+
+func TestDiurnalProfile(t *testing.T) {
+	records, err := Generate(Options{Start: start, End: start.Add(24 * time.Hour), Count: 24000, Profile: ProfileDiurnal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	perHour := make([]int, 24)
+	for _, r := range records {
+		perHour[r.Time.Hour()]++
+	}
+	if perHour[14] < 2*perHour[2] {
+		t.Errorf("afternoon volume %d not well above night volume %d", perHour[14], perHour[2])
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := Generate(Options{Start: start, End: start}); err == nil {
+		t.Error("expected an error for an empty range")
+	}
+	if _, err := Generate(Options{Start: start, End: start.Add(time.Hour), Profile: "spiky"}); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	if _, err := Generate(Options{Start: start, End: start.Add(time.Hour), Levels: []LevelWeight{{"INFO", 0}}}); err == nil {
+		t.Error("expected an error for zero weights")
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	got, err := ParseLevels("info=80, WARN=15,ERROR=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LevelWeight{{"INFO", 80}, {"WARN", 15}, {"ERROR", 5}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseLevels = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"INFO", "INFO=x", "INFO=-1"} {
+		if _, err := ParseLevels(bad); err == nil {
+			t.Errorf("ParseLevels(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timerange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats are the names accepted by --time-format.
+var Formats = []string{"default", "iso", "rfc3339", "epoch", "epoch-ns"}
+
+// Formatter renders timestamps in one of Formats and a location.
+type Formatter struct {
+	Format   string
+	Location *time.Location
+	// Zone appends the zone offset to the default format.
+	Zone bool
+}
+
+// NewFormatter validates format and the --tz name.
+func NewFormatter(format, tz string) (Formatter, error) {
+	format = strings.ToLower(format)
+	valid := false
+	for _, f := range Formats {
+		valid = valid || f == format
+	}
+	if !valid {
+		return Formatter{}, fmt.Errorf("invalid time format %q: must be one of %s", format, strings.Join(Formats, ", "))
+	}
+	loc, err := LoadLocation(tz)
+	if err != nil {
+		return Formatter{}, err
+	}
+	return Formatter{Format: format, Location: loc}, nil
+}
+
+// Time formats t. precise selects nanosecond rather than millisecond
+// precision for the default format, which keeps the layout `logs get` has
+// always printed, followed by the zone offset when Zone is set.
+func (f Formatter) Time(t time.Time, precise bool) string {
+	loc := f.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	switch f.Format {
+	case "iso":
+		return t.Format("2006-01-02T15:04:05.000Z07:00")
+	case "rfc3339":
+		return t.Format(time.RFC3339Nano)
+	case "epoch":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "epoch-ns":
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	layout := "2006-01-02 15:04:05.000"
+	if precise {
+		layout = "2006-01-02 15:04:05.999999999"
+	}
+	if f.Zone {
+		layout += " -07:00"
+	}
+	return t.Format(layout)
+}
+
+// Describe renders a resolved range for headers, with the zone spelled out
+// so readers in other regions see the same instants.
+func Describe(start, end time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.Local
+	}
+	const layout = "2006-01-02 15:04:05 MST"
+	return fmt.Sprintf("%s to %s (%s)", start.In(loc).Format(layout), end.In(loc).Format(layout), end.Sub(start).Round(time.Second))
+}
@@ -0,0 +1,338 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timerange resolves the time range flags of the log commands to
+// absolute times, and formats timestamps for display.
+//
+// Times may be given as:
+//
+//	now, today, yesterday          midnight for the day names
+//	e-1h, now-30m, e+5m            relative to the other end of the range, or now
+//	15m ago, -15m                  relative to now
+//	2026-10-15T14:03:00Z           RFC 3339; seconds and the zone are optional
+//	2026-10-15 14:03, 2026-10-15   in the range's location when no zone is given
+//	14:03                          today, in the range's location
+//	1760536800, 1760536800000      epoch seconds, milliseconds, microseconds or nanoseconds
+//
+// Durations accept Go syntax plus d (days) and w (weeks).
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSpan is the length of the range when only its end is known.
+const DefaultSpan = time.Hour
+
+// DefaultWindow is how far either side of --around the range reaches.
+const DefaultWindow = 5 * time.Minute
+
+var (
+	relativeRegex = regexp.MustCompile(`^(now|e)\s*([-+])\s*(\S+)$`)
+	agoRegex      = regexp.MustCompile(`^-?\s*(\S+?)\s*(ago)?$`)
+	epochRegex    = regexp.MustCompile(`^\d{10,19}$`)
+)
+
+// layouts are tried in order for absolute times. Those without a zone are
+// read in the range's location.
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04Z07:00",
+	"2006-01-02 15:04",
+}
+
+// Spec is a time range as given on the command line. Since and Around are
+// alternatives to Start; Around cannot be combined with Start or End.
+type Spec struct {
+	Start  string
+	End    string
+	Since  string
+	Around string
+	// Window is how far either side of Around the range reaches; zero means
+	// DefaultWindow.
+	Window time.Duration
+	// Snap widens the range outwards to multiples of this duration.
+	Snap time.Duration
+	// Location is used for times without a zone and for day names; nil
+	// means time.Local.
+	Location *time.Location
+	// Now is the current time; zero means time.Now().
+	Now time.Time
+}
+
+// Resolve returns the absolute start and end of the range. With nothing set
+// it is the last DefaultSpan. A start of today, yesterday or a bare date with
+// no end covers that whole day, up to now.
+func (s Spec) Resolve() (time.Time, time.Time, error) {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := s.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.In(loc)
+
+	var start, end time.Time
+	var err error
+	switch {
+	case s.Around != "":
+		if s.Start != "" || s.End != "" || s.Since != "" {
+			return time.Time{}, time.Time{}, errors.New("--around cannot be combined with --start, --end or --since")
+		}
+		center, err := parse(s.Around, time.Time{}, now, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --around: %w", err)
+		}
+		window := s.Window
+		if window == 0 {
+			window = DefaultWindow
+		}
+		if window < 0 {
+			return time.Time{}, time.Time{}, errors.New("--window must be positive")
+		}
+		start, end = center.Add(-window), center.Add(window)
+	case s.Since != "":
+		if s.Start != "" {
+			return time.Time{}, time.Time{}, errors.New("--since cannot be combined with --start")
+		}
+		if d, err := ParseDuration(s.Since); err == nil {
+			start = now.Add(-d)
+		} else if start, err = parse(s.Since, time.Time{}, now, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: expected a duration like 15m or a time: %w", err)
+		}
+		if end, err = s.resolveEnd(start, now, loc); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	case isRelative(s.Start):
+		if isRelative(s.End) {
+			return time.Time{}, time.Time{}, errors.New("start and end are both relative to each other")
+		}
+		if end, err = s.resolveEnd(time.Time{}, now, loc); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if start, err = parse(s.Start, end, now, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
+		}
+	case s.Start == "":
+		if end, err = s.resolveEnd(time.Time{}, now, loc); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = end.Add(-DefaultSpan)
+	default:
+		if start, err = parse(s.Start, time.Time{}, now, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
+		}
+		if s.End == "" && isDay(s.Start, loc) {
+			end = start.AddDate(0, 0, 1)
+			if end.After(now) {
+				end = now
+			}
+		} else if end, err = s.resolveEnd(start, now, loc); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if s.Snap > 0 {
+		start, end = snap(start, end, s.Snap)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("end time is before start time")
+	}
+	return start, end, nil
+}
+
+// resolveEnd parses End, relative to start, defaulting to now.
+func (s Spec) resolveEnd(start, now time.Time, loc *time.Location) (time.Time, error) {
+	if s.End == "" {
+		return now, nil
+	}
+	end, err := parse(s.End, start, now, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid end time: %w", err)
+	}
+	return end, nil
+}
+
+// snap moves start back and end forward to multiples of d, counted from
+// midnight UTC so the buckets line up with the server's.
+func snap(start, end time.Time, d time.Duration) (time.Time, time.Time) {
+	start = start.Truncate(d)
+	if t := end.Truncate(d); !t.Equal(end) {
+		end = t.Add(d)
+	}
+	return start, end
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// isRelative reports whether s is relative to the other end of the range,
+// like e-1h.
+func isRelative(s string) bool {
+	m := relativeRegex.FindStringSubmatch(normalize(s))
+	return m != nil && m[1] == "e"
+}
+
+// isDay reports whether s names a whole day rather than an instant.
+func isDay(s string, loc *time.Location) bool {
+	s = normalize(s)
+	if s == "today" || s == "yesterday" {
+		return true
+	}
+	_, err := time.ParseInLocation(time.DateOnly, s, loc)
+	return err == nil
+}
+
+// Parse resolves a single time expression relative to now, in loc.
+func Parse(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	return parse(s, time.Time{}, now.In(loc), loc)
+}
+
+// parse resolves s. ref is the other end of the range, which e refers to.
+func parse(s string, ref, now time.Time, loc *time.Location) (time.Time, error) {
+	raw := strings.TrimSpace(s)
+	s = normalize(s)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	switch s {
+	case "":
+		return time.Time{}, errors.New("empty time")
+	case "now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "e":
+		if ref.IsZero() {
+			return time.Time{}, errors.New("'e' refers to the other end of the range, which is not set")
+		}
+		return ref, nil
+	}
+	if m := relativeRegex.FindStringSubmatch(s); m != nil {
+		base := now
+		if m[1] == "e" {
+			if ref.IsZero() {
+				return time.Time{}, fmt.Errorf("%q is relative to the other end of the range, which is not set", raw)
+			}
+			base = ref
+		}
+		d, err := ParseDuration(m[3])
+		if err != nil {
+			return time.Time{}, err
+		}
+		if m[2] == "-" {
+			d = -d
+		}
+		return base.Add(d), nil
+	}
+	if epochRegex.MatchString(s) {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid epoch timestamp %q: %w", raw, err)
+		}
+		switch {
+		case len(s) <= 10:
+			return time.Unix(v, 0).In(loc), nil
+		case len(s) <= 13:
+			return time.UnixMilli(v).In(loc), nil
+		case len(s) <= 16:
+			return time.UnixMicro(v).In(loc), nil
+		}
+		return time.Unix(0, v).In(loc), nil
+	}
+	if m := agoRegex.FindStringSubmatch(s); m != nil && (m[2] != "" || strings.HasPrefix(s, "-")) {
+		if d, err := ParseDuration(m[1]); err == nil {
+			return now.Add(-d), nil
+		}
+	}
+	upper := strings.ToUpper(raw)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, upper, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, upper, loc); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, upper, loc); err == nil {
+			return midnight.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected e.g. now, e-1h, 15m ago, yesterday, 2026-10-15T14:03Z or epoch seconds", raw)
+}
+
+// durationDaysRegex splits leading week and day counts, which
+// time.ParseDuration does not know, from the rest of a duration.
+var durationDaysRegex = regexp.MustCompile(`^(?:(\d+)w)?(?:(\d+)d)?(.*)$`)
+
+// ParseDuration parses a duration like 15m, 1h30m, 2d, 1w or 1d12h.
+func ParseDuration(s string) (time.Duration, error) {
+	s = normalize(s)
+	m := durationDaysRegex.FindStringSubmatch(s)
+	if s == "" || m == nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	if rest := m[3]; rest != "" {
+		r, err := time.ParseDuration(rest)
+		if err != nil || r < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d += r
+	}
+	return d, nil
+}
+
+// LoadLocation returns the location named by a --tz value: UTC, Local or an
+// IANA name such as America/New_York. Case is ignored for UTC and Local.
+func LoadLocation(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: expected UTC, Local or a name like America/New_York", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	now := time.Date(2026, 10, 15, 14, 3, 30, 0, time.UTC)
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name       string
+		spec       Spec
+		start, end string
+	}{
+		{"default", Spec{}, "2026-10-15T13:03:30Z", "2026-10-15T14:03:30Z"},
+		{"relative to end", Spec{Start: "e-30m"}, "2026-10-15T13:33:30Z", "2026-10-15T14:03:30Z"},
+		{"relative to start", Spec{Start: "2026-10-15T12:00:00Z", End: "e+15m"}, "2026-10-15T12:00:00Z", "2026-10-15T12:15:00Z"},
+		{"since", Spec{Since: "15m"}, "2026-10-15T13:48:30Z", "2026-10-15T14:03:30Z"},
+		{"since days", Spec{Since: "2d", End: "now-1d"}, "2026-10-13T14:03:30Z", "2026-10-14T14:03:30Z"},
+		{"since phrase", Spec{Since: "today"}, "2026-10-15T00:00:00Z", "2026-10-15T14:03:30Z"},
+		{"ago", Spec{Start: "2h ago", End: "-1h"}, "2026-10-15T12:03:30Z", "2026-10-15T13:03:30Z"},
+		{"around", Spec{Around: "2026-10-15T14:03Z", Window: 5 * time.Minute}, "2026-10-15T13:58:00Z", "2026-10-15T14:08:00Z"},
+		{"around default window", Spec{Around: "2026-10-15 14:00"}, "2026-10-15T13:55:00Z", "2026-10-15T14:05:00Z"},
+		{"yesterday is a whole day", Spec{Start: "yesterday"}, "2026-10-14T00:00:00Z", "2026-10-15T00:00:00Z"},
+		{"today stops at now", Spec{Start: "today"}, "2026-10-15T00:00:00Z", "2026-10-15T14:03:30Z"},
+		{"bare date", Spec{Start: "2026-10-01"}, "2026-10-01T00:00:00Z", "2026-10-02T00:00:00Z"},
+		{"yesterday to now", Spec{Start: "yesterday", End: "now"}, "2026-10-14T00:00:00Z", "2026-10-15T14:03:30Z"},
+		{"clock time", Spec{Start: "13:00", End: "13:30"}, "2026-10-15T13:00:00Z", "2026-10-15T13:30:00Z"},
+		{"epoch seconds and millis", Spec{Start: "1760536800", End: "1760536860000"}, "2025-10-15T14:00:00Z", "2025-10-15T14:01:00Z"},
+		{"snap", Spec{Since: "15m", Snap: 5 * time.Minute}, "2026-10-15T13:45:00Z", "2026-10-15T14:05:00Z"},
+		{"zone-less time in location", Spec{Start: "2026-10-15 09:00", End: "2026-10-15T10:00:00-04:00", Location: ny}, "2026-10-15T13:00:00Z", "2026-10-15T14:00:00Z"},
+		{"today in location", Spec{Start: "today", Location: ny}, "2026-10-15T04:00:00Z", "2026-10-15T14:03:30Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.Now = now
+			if tt.spec.Location == nil {
+				tt.spec.Location = time.UTC
+			}
+			start, end, err := tt.spec.Resolve()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !start.Equal(at(tt.start)) || !end.Equal(at(tt.end)) {
+				t.Errorf("Resolve() = %s .. %s, want %s .. %s", start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	for _, spec := range []Spec{
+		{Start: "e-1h", End: "e+1h"},
+		{Around: "now", Start: "e-1h"},
+		{Since: "15m", Start: "e-1h"},
+		{Start: "now", End: "e-1h"},
+		{Start: "next tuesday"},
+		{Since: "fortnight"},
+	} {
+		if _, _, err := spec.Resolve(); err == nil {
+			t.Errorf("Resolve(%+v) succeeded", spec)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"15m", 15 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"2d", 48 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"1w2d3h30m", (9*24+3)*time.Hour + 30*time.Minute},
+		{" 1D ", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got, err := ParseDuration(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "d", "1d1w", "-1h", "1d-2h", "5"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	ts := time.Date(2026, 10, 15, 14, 3, 0, 123456789, time.UTC)
+	tests := []struct {
+		format, tz string
+		precise    bool
+		want       string
+	}{
+		{"default", "UTC", true, "2026-10-15 14:03:00.123456789"},
+		{"default", "UTC", false, "2026-10-15 14:03:00.123"},
+		{"default", "America/New_York", false, "2026-10-15 10:03:00.123"},
+		{"iso", "UTC", true, "2026-10-15T14:03:00.123Z"},
+		{"rfc3339", "America/New_York", true, "2026-10-15T10:03:00.123456789-04:00"},
+		{"epoch", "Local", true, "1792072980123"},
+		{"epoch-ns", "UTC", true, "1792072980123456789"},
+	}
+	for _, tt := range tests {
+		f, err := NewFormatter(tt.format, tt.tz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.Time(ts, tt.precise); got != tt.want {
+			t.Errorf("%s in %s = %q, want %q", tt.format, tt.tz, got, tt.want)
+		}
+	}
+	if got := (Formatter{Format: "default", Location: time.UTC, Zone: true}).Time(ts, false); got != "2026-10-15 14:03:00.123 +00:00" {
+		t.Errorf("default with Zone = %q", got)
+	}
+	if _, err := NewFormatter("unix", "UTC"); err == nil {
+		t.Error("accepted an unknown format")
+	}
+	if _, err := NewFormatter("iso", "Mars/Olympus"); err == nil {
+		t.Error("accepted an unknown zone")
+	}
+	if got := Describe(ts, ts.Add(time.Hour), time.UTC); got != "2026-10-15 14:03:00 UTC to 2026-10-15 15:03:00 UTC (1h0m0s)" {
+		t.Errorf("Describe = %q", got)
+	}
+}
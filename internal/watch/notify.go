@@ -0,0 +1,172 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Sample is one matching log entry included in a notification.
+type Sample struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Message   string         `json:"message"`
+	Tags      map[string]any `json:"tags,omitempty"`
+}
+
+// Event is the data passed to notifiers and payload templates.
+type Event struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Count     int       `json:"count"`
+	Threshold string    `json:"threshold"`
+	Query     string    `json:"query"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Samples   []Sample  `json:"samples"`
+}
+
+// templateFuncs are available to payload templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+}
+
+// ParseTemplate parses a payload template. Templates receive an Event and may
+// use the json and upper functions, e.g. {"text": {{json .Status}}}.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payload template: %w", err)
+	}
+	return t, nil
+}
+
+// Payload renders ev with tmpl, or as indented JSON when tmpl is nil.
+func Payload(tmpl *template.Template, ev Event) ([]byte, error) {
+	if tmpl == nil {
+		data, err := json.MarshalIndent(ev, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event: %w", err)
+		}
+		return data, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Notifier delivers an event.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event, payload []byte) error
+}
+
+// ExecNotifier runs a shell command (sh -c, or cmd /C on Windows) with the
+// payload on stdin and the event's
+// status, name and count in LAKERUNNER_WATCH_* environment variables.
+type ExecNotifier struct {
+	Command string
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// String identifies the notifier in watch state.
+func (n *ExecNotifier) String() string {
+	return "exec " + n.Command
+}
+
+func (n *ExecNotifier) Notify(ctx context.Context, ev Event, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", n.Command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = n.Stdout
+	cmd.Stderr = n.Stderr
+	cmd.Env = append(os.Environ(),
+		"LAKERUNNER_WATCH_NAME="+ev.Name,
+		"LAKERUNNER_WATCH_STATUS="+ev.Status,
+		"LAKERUNNER_WATCH_COUNT="+strconv.Itoa(ev.Count),
+		"LAKERUNNER_WATCH_THRESHOLD="+ev.Threshold,
+		"LAKERUNNER_WATCH_QUERY="+ev.Query,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %q: %w", n.Command, err)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the payload to URL.
+type WebhookNotifier struct {
+	URL         string
+	ContentType string
+	Client      *http.Client
+}
+
+// String identifies the notifier in watch state. The URL may carry a token,
+// so only its host and a hash of the whole URL are kept.
+func (n *WebhookNotifier) String() string {
+	sum := sha256.Sum256([]byte(n.URL))
+	if u, err := url.Parse(n.URL); err == nil && u.Host != "" {
+		return fmt.Sprintf("webhook %s %x", u.Host, sum[:8])
+	}
+	return fmt.Sprintf("webhook %x", sum[:8])
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, _ Event, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	contentType := n.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "lakerunner-cli/1.0")
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
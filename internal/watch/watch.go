@@ -0,0 +1,141 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watch implements the alerting side of `lakerunner watch`: threshold
+// rules, firing/resolved state persisted across restarts, and notifiers that
+// run a command or call a webhook.
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lakerunner/cli/internal/timerange"
+)
+
+// Event statuses.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Threshold fires when at least Count entries match within Window.
+type Threshold struct {
+	Count  int
+	Window time.Duration
+}
+
+// ParseThreshold parses "COUNT/WINDOW", e.g. "10/5m" or "5/1d".
+func ParseThreshold(s string) (Threshold, error) {
+	countStr, windowStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: expected COUNT/WINDOW, e.g. 10/5m", s)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil || count < 1 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: count must be a positive integer", s)
+	}
+	window, err := timerange.ParseDuration(strings.TrimSpace(windowStr))
+	if err != nil || window <= 0 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: window must be a positive duration", s)
+	}
+	return Threshold{Count: count, Window: window}, nil
+}
+
+func (t Threshold) String() string {
+	return fmt.Sprintf("%d/%s", t.Count, t.Window)
+}
+
+// State is what a watcher remembers between runs.
+type State struct {
+	Firing    bool      `json:"firing"`
+	LastFired time.Time `json:"last_fired,omitzero"`
+	Resolved  time.Time `json:"resolved,omitzero"`
+	LastCount int       `json:"last_count"`
+	// Pending is the last event not yet delivered by every notifier, and
+	// Undelivered the notifiers (by String) still owing it. They are retried
+	// on later polls until they succeed or the next event replaces it.
+	Pending     *Event   `json:"pending,omitempty"`
+	Undelivered []string `json:"undelivered,omitempty"`
+}
+
+// LoadState reads the state file at path. A missing file yields a zero State.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("failed to read watch state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the state atomically so a crash never leaves a partial file.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create watch state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	return nil
+}
+
+// Step advances the state for one evaluation and returns the status to notify,
+// or "" for none. A firing rule re-notifies once cooldown has passed since it
+// last fired; it resolves on the first evaluation below the threshold.
+func (s *State) Step(t Threshold, count int, cooldown time.Duration, now time.Time) string {
+	s.LastCount = count
+	if count >= t.Count {
+		if s.Firing && now.Sub(s.LastFired) < cooldown {
+			return ""
+		}
+		s.Firing = true
+		s.LastFired = now
+		return StatusFiring
+	}
+	if s.Firing {
+		s.Firing = false
+		s.Resolved = now
+		return StatusResolved
+	}
+	return ""
+}
+
+// DefaultStatePath returns ~/.lakerunner/watch/NAME.json.
+func DefaultStatePath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".lakerunner", "watch", name+".json"), nil
+}
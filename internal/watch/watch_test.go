@@ -0,0 +1,184 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseThreshold(t *testing.T) {
+	th, err := ParseThreshold("10/5m")
+	if err != nil {
+		t.Fatalf("ParseThreshold() error: %v", err)
+	}
+	if th.Count != 10 || th.Window != 5*time.Minute {
+		t.Errorf("ParseThreshold() = %+v", th)
+	}
+	if th.String() != "10/5m0s" {
+		t.Errorf("String() = %q", th.String())
+	}
+	if th, err := ParseThreshold("5/1d"); err != nil || th.Window != 24*time.Hour {
+		t.Errorf("ParseThreshold(5/1d) = %+v, %v", th, err)
+	}
+	for _, s := range []string{"10", "0/5m", "x/5m", "10/soon", "10/-1m"} {
+		if _, err := ParseThreshold(s); err == nil {
+			t.Errorf("ParseThreshold(%q) expected error", s)
+		}
+	}
+}
+
+func TestStateStep(t *testing.T) {
+	th := Threshold{Count: 10, Window: 5 * time.Minute}
+	cooldown := 15 * time.Minute
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := &State{}
+
+	steps := []struct {
+		after time.Duration
+		count int
+		want  string
+	}{
+		{0, 3, ""},
+		{time.Minute, 12, StatusFiring},
+		{2 * time.Minute, 20, ""},             // within cooldown
+		{17 * time.Minute, 15, StatusFiring},  // cooldown elapsed
+		{18 * time.Minute, 2, StatusResolved}, // below threshold
+		{19 * time.Minute, 1, ""},             // stays resolved
+		{20 * time.Minute, 10, StatusFiring},  // fires again immediately
+	}
+	for i, st := range steps {
+		if got := s.Step(th, st.count, cooldown, t0.Add(st.after)); got != st.want {
+			t.Errorf("step %d: Step() = %q, want %q", i, got, st.want)
+		}
+	}
+}
+
+func TestStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "w.json")
+	s, err := LoadState(path)
+	if err != nil || s.Firing {
+		t.Fatalf("LoadState(missing) = %+v, %v", s, err)
+	}
+	fired := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s = &State{Firing: true, LastFired: fired, LastCount: 42}
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	got, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if !got.Firing || !got.LastFired.Equal(fired) || got.LastCount != 42 {
+		t.Errorf("LoadState() = %+v", got)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary state file left behind")
+	}
+}
+
+var testEvent = Event{
+	Name:      "payments",
+	Status:    StatusFiring,
+	Count:     12,
+	Threshold: "10/5m0s",
+	Query:     `{resource_service_name="payments"}`,
+	Samples:   []Sample{{Message: `card "declined"`}},
+}
+
+func TestPayloadTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("t", `{"text": {{json (printf "%s is %s: %s" .Name (upper .Status) (index .Samples 0).Message)}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := Payload(tmpl, testEvent)
+	if err != nil {
+		t.Fatalf("Payload() error: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("payload is not JSON: %s", payload)
+	}
+	if want := `payments is FIRING: card "declined"`; got["text"] != want {
+		t.Errorf("text = %q, want %q", got["text"], want)
+	}
+
+	if _, err := ParseTemplate("bad", "{{"); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var gotBody []byte
+	var gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	payload, err := Payload(nil, testEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &WebhookNotifier{URL: srv.URL}
+	if err := n.Notify(context.Background(), testEvent, payload); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q", gotType)
+	}
+	var ev Event
+	if err := json.Unmarshal(gotBody, &ev); err != nil || ev.Status != StatusFiring || ev.Count != 12 {
+		t.Errorf("received %s (%v)", gotBody, err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	n = &WebhookNotifier{URL: failing.URL}
+	if err := n.Notify(context.Background(), testEvent, payload); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Notify() error = %v, want status 502", err)
+	}
+}
+
+func TestExecNotifier(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	n := &ExecNotifier{Command: `printf '%s %s ' "$LAKERUNNER_WATCH_STATUS" "$LAKERUNNER_WATCH_COUNT" > ` + out + ` && cat >> ` + out}
+	if err := n.Notify(context.Background(), testEvent, []byte("payload")); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "firing 12 payload" {
+		t.Errorf("command saw %q", got)
+	}
+
+	n = &ExecNotifier{Command: "exit 3"}
+	if err := n.Notify(context.Background(), testEvent, nil); err == nil {
+		t.Error("expected error for failing command")
+	}
+}
@@ -15,15 +15,11 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/lakerunner/cli/cmd"
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(cmd.Execute())
 }
@@ -0,0 +1,520 @@
+// Copyright 2025-2026 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerunner/cli/internal/apitest"
+)
+
+// e2eEnv makes the test binary run the CLI instead of the tests, so that
+// every command runs in a fresh process against the mock API.
+const e2eEnv = "LAKERUNNER_E2E_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(e2eEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// cli runs the CLI with its own home and cache directories against srv.
+type cli struct {
+	t    *testing.T
+	srv  *apitest.Server
+	home string
+	url  string
+	// extraEnv is appended to the environment of every command.
+	extraEnv []string
+}
+
+// newCLI serves two hours of generated entries ending now.
+func newCLI(t *testing.T) *cli {
+	t.Helper()
+	now := time.Now()
+	return newCLIWith(t, apitest.New(apitest.Generate(2000, now.Add(-2*time.Hour), now, 1)))
+}
+
+func newCLIWith(t *testing.T, srv *apitest.Server) *cli {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return &cli{t: t, srv: srv, home: t.TempDir(), url: ts.URL}
+}
+
+func (c *cli) env() []string {
+	env := []string{
+		e2eEnv + "=1",
+		"HOME=" + c.home,
+		"XDG_CACHE_HOME=" + filepath.Join(c.home, ".cache"),
+		"LAKERUNNER_QUERY_URL=" + c.url,
+		"LAKERUNNER_API_KEY=test",
+		"PATH=" + os.Getenv("PATH"),
+	}
+	return append(env, c.extraEnv...)
+}
+
+func (c *cli) command(args ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = c.env()
+	cmd.Dir = c.home
+	return cmd
+}
+
+// run returns the command's stdout and stderr.
+func (c *cli) run(args ...string) (string, string, error) {
+	c.t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := c.command(args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// ok runs the command, failing the test if it exits non-zero.
+func (c *cli) ok(args ...string) string {
+	c.t.Helper()
+	stdout, stderr, err := c.run(args...)
+	if err != nil {
+		c.t.Fatalf("lakerunner %s: %v\nstdout:\n%s\nstderr:\n%s", strings.Join(args, " "), err, stdout, stderr)
+	}
+	return stdout
+}
+
+func (c *cli) writeConfig(config string) {
+	c.t.Helper()
+	dir := filepath.Join(c.home, ".lakerunner")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0o644); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// jsonRows decodes -o json output.
+func jsonRows(t *testing.T, out string) []map[string]any {
+	t.Helper()
+	var rows []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var row map[string]any
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestE2ELogsGet(t *testing.T) {
+	c := newCLI(t)
+
+	out := c.ok("logs", "get", "-s", "e-3h", "-l", "ERROR", "--limit", "20")
+	if !strings.Contains(out, `LogQL: {level="ERROR"}`) || strings.Count(out, " ERROR ") != 20 {
+		t.Errorf("text output:\n%s", out)
+	}
+
+	rows := jsonRows(t, c.ok("logs", "get", "-s", "e-3h", "-a", "checkout,cart", "-o", "json", "--limit", "50"))
+	if len(rows) != 50 {
+		t.Fatalf("got %d JSON rows, want 50", len(rows))
+	}
+	for _, row := range rows {
+		if row["service"] != "checkout" && row["service"] != "cart" {
+			t.Errorf("row from another service: %v", row)
+		}
+	}
+
+	out = c.ok("logs", "get", "-s", "e-3h", "-M", "timeout", "-o", "csv", "-c", "service,message", "--limit", "5")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if lines[0] != "service,message" || len(lines) != 6 || !strings.Contains(lines[1], "payment timeout") {
+		t.Errorf("csv output:\n%s", out)
+	}
+}
+
+func TestE2ELogsGetTimeRange(t *testing.T) {
+	c := newCLI(t)
+
+	out := c.ok("logs", "get", "--since", "30m", "--tz", "UTC", "-l", "ERROR", "--limit", "1")
+	if !regexp.MustCompile(`Querying logs from \d{4}-\d\d-\d\d \d\d:\d\d:\d\d UTC to .* UTC \(30m0s\)`).MatchString(out) {
+		t.Errorf("header does not show the resolved range:\n%s", out)
+	}
+
+	rows := jsonRows(t, c.ok("logs", "get", "--around", "now", "--window", "1h", "--time-format", "rfc3339", "--tz", "UTC", "-o", "json", "-c", "timestamp", "--limit", "5"))
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+	for _, row := range rows {
+		ts, _ := row["timestamp"].(string)
+		if _, err := time.Parse(time.RFC3339Nano, ts); err != nil || !strings.HasSuffix(ts, "Z") {
+			t.Errorf("timestamp %q is not RFC 3339 in UTC", ts)
+		}
+	}
+
+	// The default format adds the zone offset only for text output or --tz.
+	for _, args := range [][]string{{"-o", "csv"}, {"-o", "csv", "--tz", "UTC"}} {
+		out := c.ok(append([]string{"logs", "get", "-s", "e-1h", "-c", "timestamp", "--limit", "1"}, args...)...)
+		if zoned := strings.HasSuffix(strings.TrimSpace(out), " +00:00"); zoned != (len(args) > 2) {
+			t.Errorf("logs get %v:\n%s", args, out)
+		}
+	}
+
+	if _, stderr, err := c.run("logs", "get", "--since", "15m", "--start", "e-1h"); err == nil || !strings.Contains(stderr, "since") {
+		t.Errorf("--since with --start was accepted: %v %s", err, stderr)
+	}
+}
+
+func TestE2ELogsGetParsesClientSide(t *testing.T) {
+	c := newCLI(t)
+	stdout, stderr, err := c.run("logs", "get", "-s", "e-3h", "-l", "ERROR", "--parse", "json", "--where", "status>=502", "-o", "json", "-c", "service,status", "--limit", "2000")
+	if err != nil {
+		t.Fatalf("%v: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "server rejected parser stages") {
+		t.Errorf("expected the client-side parsing warning, got %q", stderr)
+	}
+	rows := jsonRows(t, stdout)
+	if len(rows) == 0 {
+		t.Fatal("no rows matched status>=502")
+	}
+	for _, row := range rows {
+		if status, _ := strconv.Atoi(row["status"].(string)); status < 502 {
+			t.Errorf("row with status %v", row["status"])
+		}
+	}
+}
+
+func TestE2ELogsGetOTLP(t *testing.T) {
+	c := newCLI(t)
+	stdout, stderr, err := c.run("logs", "get", "-s", "e-3h", "-a", "checkout", "-o", "otlp", "--otlp-file", "-", "--otlp-batch-size", "10", "--limit", "25")
+	if err != nil {
+		t.Fatalf("%v: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Sent 25 records in 3 batches to stdout") {
+		t.Errorf("stderr = %q", stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d OTLP/JSON lines, want 3:\n%s", len(lines), stdout)
+	}
+	var batch struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string
+					Value struct{ StringValue string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &batch); err != nil {
+		t.Fatal(err)
+	}
+	attrs := map[string]string{}
+	for _, kv := range batch.ResourceLogs[0].Resource.Attributes {
+		attrs[kv.Key] = kv.Value.StringValue
+	}
+	if attrs["service.name"] != "checkout" || attrs["k8s.namespace.name"] != "demo" || !strings.HasPrefix(attrs["k8s.pod.name"], "checkout") {
+		t.Errorf("resource attributes = %v", attrs)
+	}
+}
+
+func TestE2ELogsSendExitStatus(t *testing.T) {
+	c := newCLI(t)
+	collector := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer collector.Close()
+	_, stderr, err := c.run("logs", "send", "--otlp-endpoint", collector.URL, "--", "sh", "-c", "echo boom; exit 3")
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 || strings.Contains(stderr, "Error:") {
+		t.Errorf("logs send exited with %v, stderr %q; want exit status 3 and no error", err, stderr)
+	}
+}
+
+func TestE2ELogsGetExplain(t *testing.T) {
+	c := newCLI(t)
+	stdout, stderr, err := c.run("logs", "get", "-a", "checkout", "-l", "ERROR", "-s", "e-30d", "--explain")
+	if err != nil {
+		t.Fatalf("%v: %s", err, stderr)
+	}
+	for _, want := range []string{`Query:     {service="checkout", level="ERROR"}`, "(720h0m0s)", "Source:    " + c.url, "Estimate:  "} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output missing %q:\n%s", want, stdout)
+		}
+	}
+	if !regexp.MustCompile(`Estimate:  \d+ segments, .*B, \d+ rows \(experimental estimate endpoint\)`).MatchString(stdout) {
+		t.Errorf("no estimate in:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "spans 30 days") {
+		t.Errorf("stderr = %q, want a wide range warning", stderr)
+	}
+	for _, req := range c.srv.Requests() {
+		if req.Path != "/api/v1/logs/query/estimate" {
+			t.Errorf("--explain requested %s", req.Path)
+		}
+	}
+
+	export := filepath.Join(c.home, "export.ndjson")
+	if err := os.WriteFile(export, []byte(`{"timestamp":1760536980000,"message":"boom"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := c.ok("logs", "get", "--from-file", export, "--parse", "json", "--dry-run")
+	for _, want := range []string{"Parsing:   client-side", "Range:     whole file", "Fields:    all (read locally)", "Estimate:  1 file, 45 B"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("--dry-run output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestE2ETagsAndValues(t *testing.T) {
+	c := newCLI(t)
+	if out := c.ok("logs", "get-attr", "-s", "e-3h", "-a", "checkout"); !strings.Contains(out, "k8s_pod_name") {
+		t.Errorf("get-attr output:\n%s", out)
+	}
+	out := c.ok("logs", "get-values", "k8s_pod_name", "-s", "e-3h", "-a", "checkout")
+	if !strings.Contains(out, "checkout-0") || strings.Contains(out, "cart-0") {
+		t.Errorf("get-values output:\n%s", out)
+	}
+	if out := c.ok("__complete", "logs", "get", "-a", "pay"); !strings.Contains(out, "payments") {
+		t.Errorf("completion output:\n%s", out)
+	}
+}
+
+func TestE2EAnalysis(t *testing.T) {
+	c := newCLI(t)
+	if out := c.ok("logs", "patterns", "-s", "e-3h", "-l", "ERROR"); !strings.Contains(out, "payment timeout after <NUM> retries") {
+		t.Errorf("patterns output:\n%s", out)
+	}
+	if out := c.ok("logs", "patterns", "-s", "e-3h", "--top", "1"); !regexp.MustCompile(`\n\d+ patterns from \d+ entries, showing the top 1\n`).MatchString(out) {
+		t.Errorf("patterns --top 1 footer:\n%s", out)
+	}
+	var diff any
+	if err := json.Unmarshal([]byte(c.ok("logs", "diff", "-o", "json")), &diff); err != nil {
+		t.Errorf("diff -o json: %v", err)
+	}
+	var anomalies any
+	if err := json.Unmarshal([]byte(c.ok("logs", "anomalies", "-s", "e-2h", "--bucket", "10m", "-o", "json")), &anomalies); err != nil {
+		t.Errorf("anomalies -o json: %v", err)
+	}
+}
+
+func TestE2EWatch(t *testing.T) {
+	c := newCLI(t)
+	payload := filepath.Join(c.home, "payload.json")
+	c.ok("watch", "--once", "-a", "payments", "-l", "ERROR", "--threshold", "1/1h",
+		"--exec", "cat > "+payload, "--state", filepath.Join(c.home, "state.json"))
+	data, err := os.ReadFile(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev struct {
+		Status string `json:"status"`
+		Count  int    `json:"count"`
+	}
+	if err := json.Unmarshal(data, &ev); err != nil || ev.Status != "firing" || ev.Count == 0 {
+		t.Errorf("payload %s (%v)", data, err)
+	}
+}
+
+func TestE2EConfigPresetsAndAliases(t *testing.T) {
+	c := newCLI(t)
+	c.writeConfig("presets:\n  pay-errors: [service:payments, level:ERROR]\naliases:\n  pod: k8s_pod_name\n")
+
+	if out := c.ok("presets", "list"); !strings.Contains(out, "pay-errors") {
+		t.Errorf("presets list:\n%s", out)
+	}
+	if out := c.ok("aliases", "list"); !strings.Contains(out, "k8s_pod_name") {
+		t.Errorf("aliases list:\n%s", out)
+	}
+	if out := c.ok("config", "view", "--show-origin"); !strings.Contains(out, "pay-errors") || !strings.Contains(out, "config.yaml") {
+		t.Errorf("config view:\n%s", out)
+	}
+
+	rows := jsonRows(t, c.ok("logs", "get", "-s", "e-3h", "-p", "pay-errors", "--pod", "payments-0", "-o", "json", "-c", "service,level,k8s_pod_name"))
+	if len(rows) == 0 {
+		t.Fatal("preset matched nothing")
+	}
+	for _, row := range rows {
+		if row["service"] != "payments" || row["level"] != "ERROR" || row["k8s_pod_name"] != "payments-0" {
+			t.Errorf("row outside preset and alias filter: %v", row)
+		}
+	}
+	// Dotted column names are requested under their label.
+	if out := c.ok("logs", "get", "-s", "e-3h", "-p", "pay-errors", "-c", "timestamp,k8s.pod.name", "--limit", "1"); !strings.Contains(out, "Fields (API): [k8s_pod_name]") || !strings.Contains(out, "payments-") {
+		t.Errorf("dotted column:\n%s", out)
+	}
+
+	if out := c.ok("config", "doctor"); strings.Contains(out, "✗") || !strings.Contains(out, "API key accepted") {
+		t.Errorf("config doctor:\n%s", out)
+	}
+}
+
+func TestE2ESavedQueries(t *testing.T) {
+	c := newCLI(t)
+	c.ok("query", "save", "svc-errors", "-d", "Errors from one service", "--", "-a", "${svc}", "-l", "ERROR", "-s", "e-3h", "-o", "json", "-c", "service")
+	if out := c.ok("query", "list"); !strings.Contains(out, "svc-errors") || !strings.Contains(out, "Errors from one service") {
+		t.Errorf("query list:\n%s", out)
+	}
+	if out := c.ok("query", "show", "svc-errors"); !strings.Contains(out, "${svc}") {
+		t.Errorf("query show:\n%s", out)
+	}
+	rows := jsonRows(t, c.ok("query", "run", "svc-errors", "-P", "svc=cart", "--", "--limit", "3"))
+	if len(rows) != 3 || rows[0]["service"] != "cart" {
+		t.Errorf("query run rows: %v", rows)
+	}
+	c.extraEnv = []string{"VISUAL=sed -i s/Errors/Failures/"}
+	c.ok("query", "edit", "svc-errors")
+	if out := c.ok("query", "list"); !strings.Contains(out, "Failures from one service") {
+		t.Errorf("query list after edit:\n%s", out)
+	}
+	c.ok("query", "delete", "svc-errors")
+	if _, _, err := c.run("query", "show", "svc-errors"); err == nil {
+		t.Error("deleted query still shown")
+	}
+}
+
+func TestE2ECache(t *testing.T) {
+	c := newCLI(t)
+	// Absolute times, so both runs fall in the same cache bucket.
+	end := time.Now().UTC().Format(time.RFC3339)
+	args := []string{"logs", "get", "-s", "e-3h", "-e", end, "-a", "cart", "--limit", "10", "-o", "json"}
+	first := c.ok(args...)
+	second, stderr, err := c.run(args...)
+	if err != nil {
+		t.Fatalf("%v: %s", err, stderr)
+	}
+	if first != second {
+		t.Error("cached result differs from the first run")
+	}
+	if !strings.Contains(stderr, "(cached ") {
+		t.Errorf("stderr = %q, want a cached note", stderr)
+	}
+	if n := len(c.srv.Requests()); n != 1 {
+		t.Errorf("server saw %d requests, want 1 (second run cached)", n)
+	}
+	if out := c.ok("cache", "stats"); !strings.Contains(out, "logs") {
+		t.Errorf("cache stats:\n%s", out)
+	}
+	c.ok("cache", "clear")
+	c.ok(args...)
+	if n := len(c.srv.Requests()); n != 2 {
+		t.Errorf("server saw %d requests after cache clear, want 2", n)
+	}
+	// Redacted runs never read or write the cache.
+	c.ok(append(args, "--redact")...)
+	c.ok(append(args, "--redact")...)
+	if n := len(c.srv.Requests()); n != 4 {
+		t.Errorf("server saw %d requests, want 4 (redacted runs not cached)", n)
+	}
+}
+
+func TestE2EAPIKeyRejected(t *testing.T) {
+	srv := apitest.New(nil)
+	srv.APIKey = "secret"
+	c := newCLIWith(t, srv)
+	_, stderr, err := c.run("logs", "get", "-q")
+	if err == nil || !strings.Contains(stderr, "401") {
+		t.Errorf("expected a 401 error, got %v: %s", err, stderr)
+	}
+}
+
+func TestE2EDemoServe(t *testing.T) {
+	c := newCLIWith(t, apitest.New(nil))
+	serve := c.command("demo", "serve", "--addr", "127.0.0.1:0", "--entries", "300", "--span", "1h")
+	stdout, err := serve.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := serve.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = serve.Process.Kill()
+		_ = serve.Wait()
+	})
+	first, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, url, ok := strings.Cut(strings.TrimSpace(first), " on ")
+	if !ok {
+		t.Fatalf("unexpected first line %q", first)
+	}
+
+	c.url = url
+	rows := jsonRows(t, c.ok("logs", "get", "-s", "e-2h", "-o", "json", "--limit", "1000"))
+	if len(rows) != 300 {
+		t.Errorf("demo serve returned %d entries, want 300", len(rows))
+	}
+}
+
+func TestE2EDemoGenerate(t *testing.T) {
+	c := newCLI(t)
+	dir := t.TempDir()
+	_, stderr, err := c.run("demo", "generate", "--dir", dir, "--span", "10m", "--rate", "30", "--batch", "5m", "--format", "json", "--gzip=false")
+	if err != nil {
+		t.Fatalf("demo generate failed: %v: %s", err, stderr)
+	}
+
+	var logs, metrics int
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if !strings.HasPrefix(filepath.ToSlash(rel), "otel-raw/demo/synthetic/year=") {
+			t.Errorf("unexpected file %s", rel)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Errorf("%s: %v", rel, err)
+		}
+		switch {
+		case strings.HasPrefix(d.Name(), "logs_") && payload["resourceLogs"] != nil:
+			logs++
+		case strings.HasPrefix(d.Name(), "metrics_") && payload["resourceMetrics"] != nil:
+			metrics++
+		default:
+			t.Errorf("unexpected payload in %s", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logs != 2 || metrics != 2 {
+		t.Errorf("wrote %d log and %d metric files, want 2 each", logs, metrics)
+	}
+}